@@ -3,12 +3,10 @@ package main
 import (
 	"fmt"
 	"os"
-	"os/signal"
-	"syscall"
 
 	"github.com/moai/instant-backlog/internal/commands"
 	"github.com/moai/instant-backlog/internal/config"
-	"github.com/moai/instant-backlog/internal/watcher"
+	"github.com/moai/instant-backlog/internal/logger"
 	"github.com/spf13/cobra"
 )
 
@@ -16,70 +14,140 @@ func main() {
 	// コマンドエグゼキュータを登録
 	commands.RegisterCommandExecutor()
 
-	cfg := config.NewConfig()
+	// 設定はフラグの解析後に読み込む
+	var cfg *config.Config
+	var projectDir string
+	var logOpts logger.Options
 
 	// ルートコマンド
 	var rootCmd = &cobra.Command{
 		Use:     "instant-backlog",
 		Aliases: []string{"ib"},
 		Short:   "スクラムバックログ管理ツール",
-		Long:    `マークダウンファイルを使用してスクラム開発のバックログを管理するシンプルなCLIツール`,
+		Long: `マークダウンファイルを使用してスクラム開発のバックログを管理するシンプルなCLIツール
+
+設定ファイル(.instant-backlog.yaml)はカレントディレクトリ（または--projectで指定したディレクトリ）から
+親ディレクトリへ遡って探索されます。設定は環境変数(IB_*)で上書きできます`,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := logger.Setup(logOpts); err != nil {
+				return err
+			}
+
+			loaded, err := config.Load(config.LoadOptions{ProjectDir: projectDir})
+			if err != nil {
+				return fmt.Errorf("設定の読み込みに失敗しました: %w", err)
+			}
+			cfg = loaded
+			return nil
+		},
+	}
+	rootCmd.PersistentFlags().BoolVarP(&logOpts.Verbose, "verbose", "v", false, "詳細なログを出力する")
+	rootCmd.PersistentFlags().BoolVarP(&logOpts.Quiet, "quiet", "q", false, "警告とエラーのみ出力する")
+	rootCmd.PersistentFlags().StringVar(&logOpts.Format, "log-format", logger.FormatText, "ログの出力形式（text, json）")
+	rootCmd.PersistentFlags().StringVarP(&projectDir, "project", "p", "", "プロジェクトのディレクトリ（設定ファイルの探索を開始するディレクトリ）")
+
+	// リネーム先のファイルが既に存在する場合の処理方法（sync, rename共通）
+	var onConflict string
+	applyOnConflict := func() error {
+		if onConflict == "" {
+			return nil
+		}
+		policy, err := config.ParseConflictPolicy(onConflict)
+		if err != nil {
+			return err
+		}
+		cfg.OnConflict = policy
+		return nil
 	}
+	const onConflictUsage = "ファイル名が衝突した場合の処理方法（skip, conflicts, merge）"
+
+	// 新しいIssueをorder.csvに追加する位置（sync, new issue共通）
+	var placement string
+	applyPlacement := func() error {
+		if placement == "" {
+			return nil
+		}
+		policy, err := config.ParsePlacementPolicy(placement)
+		if err != nil {
+			return err
+		}
+		cfg.Placement = policy
+		return nil
+	}
+	const placementUsage = "新しいIssueをorder.csvに追加する位置（bottom, top, epic, priority）"
 
 	// syncコマンド
+	var syncOpts commands.SyncOptions
+	var syncDryRun bool
 	var syncCmd = &cobra.Command{
 		Use:   "sync",
 		Short: "order.csvを同期",
 		Long:  `オープンIssueをorder.csvに同期し、クローズIssueを削除します`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return commands.SyncCommand(cfg)
+			if err := applyOnConflict(); err != nil {
+				return err
+			}
+			if err := applyPlacement(); err != nil {
+				return err
+			}
+			if syncDryRun {
+				return commands.SyncDryRunCommand(cfg, syncOpts, cmd.OutOrStdout())
+			}
+			return commands.SyncCommandWithOptions(cfg, syncOpts)
 		},
 	}
+	syncCmd.Flags().BoolVar(&syncOpts.SortByDependencies, "sort-deps", false, "依存先が上位になるようにorder.csvを並び替える")
+	syncCmd.Flags().BoolVar(&syncOpts.FromCSV, "from-csv", false, "order.csvとIssueファイルで値が異なる場合、order.csvの値をIssueファイルに書き戻す")
+	syncCmd.Flags().StringVar(&onConflict, "on-conflict", "", onConflictUsage)
+	syncCmd.Flags().StringVar(&placement, "placement", "", placementUsage)
+	syncCmd.Flags().BoolVar(&syncDryRun, "dry-run", false, "変更内容を表示するだけでファイルは変更しない")
 
 	// renameコマンド
+	var renameDryRun bool
 	var renameCmd = &cobra.Command{
 		Use:   "rename",
 		Short: "ファイル名を更新",
-		Long:  `Front Matterの内容に基づいてファイル名を更新します`,
+		Long: `Front Matterの内容に基づいてファイル名を更新します。
+リネーム先のファイルが既に存在する場合は、--on-conflict（または設定ファイルのon_conflict）の方針に従って処理します`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := applyOnConflict(); err != nil {
+				return err
+			}
+			if renameDryRun {
+				return commands.RenameDryRunCommand(cfg, cmd.OutOrStdout())
+			}
 			return commands.RenameCommand(cfg)
 		},
 	}
+	renameCmd.Flags().StringVar(&onConflict, "on-conflict", "", onConflictUsage)
+	renameCmd.Flags().BoolVar(&renameDryRun, "dry-run", false, "変更内容を表示するだけでファイルは変更しない")
 
 	// watchコマンド
+	var watchDaemon, watchList bool
 	var watchCmd = &cobra.Command{
 		Use:   "watch [project_path]",
 		Short: "プロジェクトの監視を開始",
-		Long:  `指定したプロジェクト配下のissuesディレクトリを監視し、変更があれば自動でsyncとrenameを実行します`,
-		Args:  cobra.MaximumNArgs(1),
+		Long: `指定したプロジェクト配下のissuesディレクトリを監視し、変更があれば自動でsyncとrenameを実行します。
+既に監視プロセスが起動している場合は、そのプロセスに監視対象を追加します`,
+		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if watchList {
+				return commands.WatchListCommand(cfg, cmd.OutOrStdout())
+			}
+
 			projectPath := ""
 			if len(args) > 0 {
 				projectPath = args[0]
 			}
 
-			// シグナルハンドリングのセットアップ
-			sigChan := make(chan os.Signal, 1)
-			signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
-
-			// ゴルーチンで監視を開始
-			errChan := make(chan error, 1)
-			go func() {
-				errChan <- commands.WatchCommand(cfg, projectPath)
-			}()
-
-			// シグナルまたはエラーを待機
-			select {
-			case <-sigChan:
-				fmt.Println("\n終了シグナルを受信しました")
-				// すべての監視を停止
-				watcher.GetManager().StopAll()
-				return nil
-			case err := <-errChan:
-				return err
+			if watchDaemon {
+				return commands.WatchDaemonCommand(cfg, projectPath)
 			}
+			return commands.WatchCommand(cfg, projectPath)
 		},
 	}
+	watchCmd.Flags().BoolVarP(&watchDaemon, "daemon", "d", false, "バックグラウンドの監視デーモンとして起動する")
+	watchCmd.Flags().BoolVar(&watchList, "list", false, "監視中のプロジェクトを一覧表示する")
 
 	// unwatchコマンド
 	var unwatchCmd = &cobra.Command{
@@ -113,12 +181,185 @@ func main() {
 		},
 	}
 
+	// newコマンド
+	var newCmd = &cobra.Command{
+		Use:   "new",
+		Short: "IssueまたはEpicを作成",
+		Long:  `次の空きIDを採番してIssueまたはEpicのファイルを作成します`,
+	}
+
+	// new issueコマンド
+	var newIssueOpts commands.NewIssueOptions
+	var newIssueCmd = &cobra.Command{
+		Use:   "issue",
+		Short: "Issueを作成",
+		Long:  `次の空きIDで新しいIssueを作成し、order.csvに追加します`,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := applyPlacement(); err != nil {
+				return err
+			}
+			return commands.NewIssueCommand(cfg, newIssueOpts)
+		},
+	}
+	newIssueCmd.Flags().StringVar(&placement, "placement", "", placementUsage)
+	newIssueCmd.Flags().StringVarP(&newIssueOpts.Title, "title", "t", "", "Issueのタイトル")
+	newIssueCmd.Flags().IntVarP(&newIssueOpts.Epic, "epic", "e", 0, "関連するEpicのID")
+	newIssueCmd.Flags().IntVarP(&newIssueOpts.Estimate, "estimate", "s", 0, "見積もりポイント")
+	newIssueCmd.Flags().StringVar(&newIssueOpts.Status, "status", "", "初期ステータス（省略時は最初の未完了ステータス）")
+	newIssueCmd.Flags().BoolVar(&newIssueOpts.Edit, "edit", false, "作成後に$EDITORで開く")
+	newIssueCmd.MarkFlagRequired("title")
+	newIssueCmd.MarkFlagRequired("epic")
+
+	// new epicコマンド
+	var newEpicOpts commands.NewEpicOptions
+	var newEpicCmd = &cobra.Command{
+		Use:   "epic",
+		Short: "Epicを作成",
+		Long:  `次の空きIDで新しいEpicを作成します`,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return commands.NewEpicCommand(cfg, newEpicOpts)
+		},
+	}
+	newEpicCmd.Flags().StringVarP(&newEpicOpts.Title, "title", "t", "", "Epicのタイトル")
+	newEpicCmd.Flags().StringVar(&newEpicOpts.Status, "status", "", "初期ステータス（省略時は最初の未完了ステータス）")
+	newEpicCmd.Flags().BoolVar(&newEpicOpts.Edit, "edit", false, "作成後に$EDITORで開く")
+	newEpicCmd.MarkFlagRequired("title")
+
+	newCmd.AddCommand(newIssueCmd)
+	newCmd.AddCommand(newEpicCmd)
+
+	// listコマンド
+	var listOpts commands.ListOptions
+	var listCmd = &cobra.Command{
+		Use:   "list [issues|epics]",
+		Short: "IssueまたはEpicを一覧表示",
+		Long:  `IssueまたはEpicを条件で絞り込み、表・JSON・CSVのいずれかの形式で一覧表示します`,
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 0 {
+				switch args[0] {
+				case "issues", "issue":
+				case "epics", "epic":
+					listOpts.Epics = true
+				default:
+					return fmt.Errorf("不明な一覧の種類です: %s（issues または epics を指定してください）", args[0])
+				}
+			}
+			return commands.ListCommand(cfg, listOpts, cmd.OutOrStdout())
+		},
+	}
+	listCmd.Flags().StringSliceVar(&listOpts.Statuses, "status", nil, "表示するステータス（複数指定可）")
+	listCmd.Flags().BoolVar(&listOpts.Open, "open", false, "未完了のもののみ表示")
+	listCmd.Flags().IntVarP(&listOpts.Epic, "epic", "e", 0, "指定したEpicに紐づくIssueのみ表示")
+	listCmd.Flags().IntVar(&listOpts.MinEstimate, "min-estimate", -1, "見積もりの下限（Epicでは未完了のIssueの見積もり合計）")
+	listCmd.Flags().IntVar(&listOpts.MaxEstimate, "max-estimate", -1, "見積もりの上限（Epicでは未完了のIssueの見積もり合計）")
+	listCmd.Flags().StringVarP(&listOpts.Title, "title", "t", "", "タイトルに含まれる文字列")
+	listCmd.Flags().StringVar(&listOpts.Sort, "sort", commands.ListSortOrder, "並び順（order, id, estimate）")
+	listCmd.Flags().StringVarP(&listOpts.Format, "format", "f", commands.ListFormatTable, "出力形式（table, json, csv）")
+
+	// moveコマンド
+	var moveOpts commands.MoveOptions
+	var moveCmd = &cobra.Command{
+		Use:   "move <id>...",
+		Short: "order.csvの並び順を変更",
+		Long: `order.csvでIssueの並び順を変更します。複数のIDを指定した場合（カンマ区切りも可）は、
+指定した順序のまま1つのまとまりとして移動します`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ids, err := commands.ParseIDs(args)
+			if err != nil {
+				return err
+			}
+			return commands.MoveCommand(cfg, ids, moveOpts)
+		},
+	}
+	moveCmd.Flags().BoolVar(&moveOpts.Top, "top", false, "先頭に移動する")
+	moveCmd.Flags().BoolVar(&moveOpts.Bottom, "bottom", false, "末尾に移動する")
+	moveCmd.Flags().IntVar(&moveOpts.Before, "before", 0, "指定したIDのIssueの直前に移動する")
+	moveCmd.Flags().IntVar(&moveOpts.After, "after", 0, "指定したIDのIssueの直後に移動する")
+	moveCmd.Flags().IntVar(&moveOpts.Position, "position", 0, "指定した順位（1始まり）に移動する")
+
+	// merge-orderコマンド
+	var mergeOrderCmd = &cobra.Command{
+		Use:   "merge-order <base> <ours> <theirs>",
+		Short: "order.csvをIssue IDごとにマージ（gitのマージドライバー）",
+		Long: `gitのマージドライバーとして、order.csvをIssue IDごとに3方向マージし、結果を<ours>に書き込みます。
+追加された行はすべて残し、削除された行と完了済みのIssueの行は除きます。両方で異なる位置に移動された行は自分の位置を採用します。
+両方で同じ列が異なる値に変更された場合のみ競合として終了コード1で終了します。
+
+  git config merge.ib-order.driver "ib merge-order %O %A %B"
+  echo "order.csv merge=ib-order" >> .gitattributes`,
+		Args:         cobra.ExactArgs(3),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return commands.MergeOrderCommand(cfg, args[0], args[1], args[2], cmd.ErrOrStderr())
+		},
+	}
+
+	// migrate-orderコマンド
+	var migrateTo string
+	var migrateOrderCmd = &cobra.Command{
+		Use:   "migrate-order",
+		Short: "Issueの並び順の管理方法を移行",
+		Long: `Issueの並び順の管理方法をorder.csv（csv）とFront Matterのrank（rank）の間で移行します。
+rankに移行する場合は現在のorder.csvの並び順でrankを設定し、csvに移行する場合はrankの並び順でorder.csvを書き込んでrankを削除します`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			to, err := config.ParseOrderingMode(migrateTo)
+			if err != nil {
+				return err
+			}
+			return commands.MigrateOrderCommand(cfg, to, cmd.OutOrStdout())
+		},
+	}
+	migrateOrderCmd.Flags().StringVar(&migrateTo, "to", "", "移行先の管理方法（csv, rank）")
+	migrateOrderCmd.MarkFlagRequired("to")
+
+	// doctorコマンド
+	var doctorOpts commands.DoctorOptions
+	var doctorCmd = &cobra.Command{
+		Use:   "doctor",
+		Short: "バックログの整合性を診断",
+		Long: `ID の重複、ファイル名と Front Matter の不一致、存在しない Epic への参照、不正な値、
+order.csv の不要な行や不足を検出します。--fix を指定すると安全に修正できる問題を修正します`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return commands.DoctorCommand(cfg, doctorOpts, cmd.OutOrStdout())
+		},
+	}
+	doctorCmd.Flags().BoolVar(&doctorOpts.Fix, "fix", false, "安全に修正できる問題を修正する")
+
+	// checkコマンド
+	var checkOpts commands.CheckOptions
+	var checkCmd = &cobra.Command{
+		Use:   "check",
+		Short: "バックログを検証（CI向け）",
+		Long: `Issue、Epic、order.csvを検証し、問題をファイルと行番号付きで出力します。ファイルは一切変更しません。
+エラーがある場合（--strictの場合は警告がある場合も）は終了コード1で終了します`,
+		Args: cobra.NoArgs,
+		// 検証結果のエラーで使い方を表示しない
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return commands.CheckCommand(cfg, checkOpts, cmd.OutOrStdout())
+		},
+	}
+	checkCmd.Flags().StringVarP(&checkOpts.Format, "format", "f", commands.CheckFormatText, "出力形式（text, json, github）")
+	checkCmd.Flags().BoolVar(&checkOpts.Strict, "strict", false, "警告もエラーとして扱う")
+
 	// コマンドをルートコマンドに追加
 	rootCmd.AddCommand(syncCmd)
 	rootCmd.AddCommand(renameCmd)
 	rootCmd.AddCommand(watchCmd)
 	rootCmd.AddCommand(unwatchCmd)
 	rootCmd.AddCommand(initCmd)
+	rootCmd.AddCommand(newCmd)
+	rootCmd.AddCommand(listCmd)
+	rootCmd.AddCommand(moveCmd)
+	rootCmd.AddCommand(mergeOrderCmd)
+	rootCmd.AddCommand(migrateOrderCmd)
+	rootCmd.AddCommand(doctorCmd)
+	rootCmd.AddCommand(checkCmd)
 
 	// 実行
 	if err := rootCmd.Execute(); err != nil {
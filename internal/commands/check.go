@@ -0,0 +1,147 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/moai/instant-backlog/internal/config"
+)
+
+// 診断結果の出力形式
+const (
+	CheckFormatText   = "text"
+	CheckFormatJSON   = "json"
+	CheckFormatGitHub = "github" // GitHub Actionsのワークフローコマンド（アノテーション）
+)
+
+// CheckOptions - checkコマンドのオプション
+type CheckOptions struct {
+	Format string // 出力形式（text, json, github）
+	Strict bool   // 警告もエラーとして扱う
+}
+
+// checkResult - JSON形式で出力する診断結果
+type checkResult struct {
+	Diagnostics []*DoctorProblem `json:"diagnostics"`
+	Errors      int              `json:"errors"`
+	Warnings    int              `json:"warnings"`
+}
+
+// CheckCommand - バックログを検証し、診断結果を出力する（ファイルは一切変更しない）
+// エラーがある場合（Strictの場合は警告がある場合も）はエラーを返す
+func CheckCommand(cfg *config.Config, opts CheckOptions, w io.Writer) error {
+	if opts.Format == "" {
+		opts.Format = CheckFormatText
+	}
+	switch opts.Format {
+	case CheckFormatText, CheckFormatJSON, CheckFormatGitHub:
+	default:
+		return fmt.Errorf("不明な出力形式です: %s（text, json, github のいずれかを指定してください）", opts.Format)
+	}
+
+	report, err := Diagnose(cfg, DoctorOptions{})
+	if err != nil {
+		return err
+	}
+
+	result := checkResult{Diagnostics: report.Problems}
+	if result.Diagnostics == nil {
+		result.Diagnostics = []*DoctorProblem{}
+	}
+	for _, p := range report.Problems {
+		// 出力するパスはカレントディレクトリからの相対パスにする（CIのアノテーションやエディタで開けるように）
+		p.File = displayPath(p.File)
+		if opts.Strict {
+			p.Severity = SeverityError
+		}
+		if p.Severity == SeverityError {
+			result.Errors++
+		} else {
+			result.Warnings++
+		}
+	}
+
+	switch opts.Format {
+	case CheckFormatJSON:
+		err = writeJSON(w, result)
+	case CheckFormatGitHub:
+		err = writeGitHubAnnotations(w, report.Problems)
+	default:
+		err = writeCheckText(w, result)
+	}
+	if err != nil {
+		return err
+	}
+
+	if result.Errors > 0 {
+		return fmt.Errorf("バックログの検証で%d件のエラーが見つかりました", result.Errors)
+	}
+	return nil
+}
+
+// writeCheckText - 診断結果を「ファイル:行: 重大度: 内容 [種類]」の形式で出力する
+func writeCheckText(w io.Writer, result checkResult) error {
+	for _, p := range result.Diagnostics {
+		if _, err := fmt.Fprintf(w, "%s: %s: %s [%s]\n", position(p), p.Severity, p.Message, p.Kind); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "エラー %d件、警告 %d件\n", result.Errors, result.Warnings)
+	return err
+}
+
+// writeGitHubAnnotations - 診断結果をGitHub Actionsのアノテーションとして出力する
+func writeGitHubAnnotations(w io.Writer, problems []*DoctorProblem) error {
+	for _, p := range problems {
+		var props []string
+		if p.File != "" {
+			props = append(props, "file="+escapeGitHubProperty(p.File))
+		}
+		if p.Line > 0 {
+			props = append(props, fmt.Sprintf("line=%d", p.Line))
+		}
+		props = append(props, "title="+escapeGitHubProperty(problemLabels[p.Kind]))
+
+		if _, err := fmt.Fprintf(w, "::%s %s::%s\n", p.Severity, strings.Join(props, ","), escapeGitHubData(p.Message)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// position - 診断結果の位置を「ファイル:行」の形式で返す
+func position(p *DoctorProblem) string {
+	if p.Line > 0 {
+		return fmt.Sprintf("%s:%d", p.File, p.Line)
+	}
+	return p.File
+}
+
+// displayPath - カレントディレクトリからの相対パスを返す（外側のパスの場合はそのまま返す）
+func displayPath(path string) string {
+	if path == "" {
+		return path
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+	rel, err := filepath.Rel(wd, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return path
+	}
+	return filepath.ToSlash(rel)
+}
+
+// escapeGitHubData - ワークフローコマンドのメッセージをエスケープする
+func escapeGitHubData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// escapeGitHubProperty - ワークフローコマンドのプロパティ値をエスケープする
+func escapeGitHubProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
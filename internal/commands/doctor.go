@@ -0,0 +1,520 @@
+package commands
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/moai/instant-backlog/internal/config"
+	"github.com/moai/instant-backlog/internal/fileops"
+	"github.com/moai/instant-backlog/internal/logger"
+	"github.com/moai/instant-backlog/internal/models"
+	"github.com/moai/instant-backlog/internal/parser"
+	"github.com/moai/instant-backlog/pkg/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// 診断で検出する問題の種類
+const (
+	ProblemParseError       = "parse_error"       // Front Matterを解析できない
+	ProblemDuplicateID      = "duplicate_id"      // 同じIDのファイルが複数ある
+	ProblemFilenameMismatch = "filename_mismatch" // ファイル名とFront Matterが一致しない
+	ProblemOrphanEpic       = "orphan_epic"       // 存在しないEpicを参照している
+	ProblemInvalidField     = "invalid_field"     // Front Matterの値が不正
+	ProblemStaleOrder       = "stale_order"       // order.csvに不要な行がある
+	ProblemMissingOrder     = "missing_order"     // 未完了のIssueがorder.csvにない
+	ProblemDependency       = "dependency"        // blocked_byの参照切れまたは循環
+)
+
+// 問題の重大度
+const (
+	SeverityError   = "error"   // バックログが壊れている
+	SeverityWarning = "warning" // syncで自動的に解消される
+)
+
+// problemSeverities - 問題の種類ごとの重大度
+var problemSeverities = map[string]string{
+	ProblemParseError:       SeverityError,
+	ProblemDuplicateID:      SeverityError,
+	ProblemFilenameMismatch: SeverityWarning,
+	ProblemOrphanEpic:       SeverityError,
+	ProblemInvalidField:     SeverityError,
+	ProblemStaleOrder:       SeverityWarning,
+	ProblemMissingOrder:     SeverityWarning,
+	ProblemDependency:       SeverityError,
+}
+
+// problemLabels - 問題の種類の表示名
+var problemLabels = map[string]string{
+	ProblemParseError:       "解析エラー",
+	ProblemDuplicateID:      "ID重複",
+	ProblemFilenameMismatch: "ファイル名不一致",
+	ProblemOrphanEpic:       "Epic参照切れ",
+	ProblemInvalidField:     "不正な値",
+	ProblemStaleOrder:       "order.csvの不要な行",
+	ProblemMissingOrder:     "order.csvの不足",
+	ProblemDependency:       "依存関係",
+}
+
+// DoctorOptions - doctorコマンドのオプション
+type DoctorOptions struct {
+	Fix bool // 安全に修正できる問題を修正する
+}
+
+// DoctorProblem - 診断で検出した問題
+type DoctorProblem struct {
+	Kind     string `json:"kind"`
+	Severity string `json:"severity"`       // 重大度（error, warning）
+	File     string `json:"file,omitempty"` // 問題のあるファイル
+	Line     int    `json:"line,omitempty"` // 問題のある行（1始まり、不明な場合は0）
+	ID       int    `json:"id,omitempty"`   // 問題のあるIssueまたはEpicのID
+	Message  string `json:"message"`
+	Fixable  bool   `json:"fixable"` // --fix で修正できるかどうか
+	Fixed    bool   `json:"fixed"`   // --fix で修正したかどうか
+}
+
+// DoctorReport - 診断結果
+type DoctorReport struct {
+	Problems []*DoctorProblem
+}
+
+// Remaining - 修正されずに残っている問題の数を返す
+func (r *DoctorReport) Remaining() int {
+	count := 0
+	for _, p := range r.Problems {
+		if !p.Fixed {
+			count++
+		}
+	}
+	return count
+}
+
+// Fixable - 修正可能だが修正されていない問題の数を返す
+func (r *DoctorReport) Fixable() int {
+	count := 0
+	for _, p := range r.Problems {
+		if p.Fixable && !p.Fixed {
+			count++
+		}
+	}
+	return count
+}
+
+// add - 問題を追加し、追加した問題を返す
+func (r *DoctorReport) add(p DoctorProblem) *DoctorProblem {
+	if p.Severity == "" {
+		p.Severity = problemSeverities[p.Kind]
+	}
+	r.Problems = append(r.Problems, &p)
+	return &p
+}
+
+// backlogFile - 診断対象のIssueまたはEpicのファイル
+type backlogFile struct {
+	path   string
+	id     int
+	title  string
+	status string
+	issue  *models.Issue // Issueの場合のみ
+	epic   *models.Epic  // Epicの場合のみ
+	doc    *yaml.Node    // Front Matterのドキュメント（行番号の取得に使用）
+}
+
+// line - Front Matterのキーの行番号を返す（見つからない場合は1行目）
+func (f *backlogFile) line(key string) int {
+	if line := parser.FieldLine(f.doc, key); line > 0 {
+		return line
+	}
+	return 1
+}
+
+// DoctorCommand - バックログの整合性を診断し、結果を出力する
+// 修正されずに残った問題がある場合はエラーを返す
+func DoctorCommand(cfg *config.Config, opts DoctorOptions, w io.Writer) error {
+	report, err := Diagnose(cfg, opts)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range report.Problems {
+		mark := "  "
+		if p.Fixed {
+			mark = "✓ "
+		}
+		location := ""
+		if p.File != "" {
+			location = relativePath(cfg.ProjectsDir, p.File)
+			if p.Line > 0 {
+				location += fmt.Sprintf(":%d", p.Line)
+			}
+			location += ": "
+		}
+		fmt.Fprintf(w, "%s[%s] %s%s\n", mark, problemLabels[p.Kind], location, p.Message)
+	}
+
+	remaining := report.Remaining()
+	fixed := len(report.Problems) - remaining
+	switch {
+	case len(report.Problems) == 0:
+		fmt.Fprintln(w, "問題は見つかりませんでした")
+		return nil
+	case fixed > 0:
+		fmt.Fprintf(w, "%d件の問題を修正しました\n", fixed)
+	}
+	if remaining == 0 {
+		return nil
+	}
+
+	if fixable := report.Fixable(); fixable > 0 {
+		fmt.Fprintf(w, "%d件の問題が見つかりました（うち%d件は --fix で修正できます）\n", remaining, fixable)
+	} else {
+		fmt.Fprintf(w, "%d件の問題が見つかりました（手動での修正が必要です）\n", remaining)
+	}
+	return fmt.Errorf("%d件の問題が残っています", remaining)
+}
+
+// Diagnose - バックログの整合性を診断する
+// opts.Fix が指定された場合は、安全に修正できる問題を修正する
+func Diagnose(cfg *config.Config, opts DoctorOptions) (*DoctorReport, error) {
+	statuses := cfg.StatusSet()
+	report := &DoctorReport{}
+
+	issues, err := loadBacklogFiles(cfg.IssuesDir, true, report)
+	if err != nil {
+		return nil, fmt.Errorf("Issueの読み込みに失敗しました: %w", err)
+	}
+	epics, err := loadBacklogFiles(cfg.EpicDir, false, report)
+	if err != nil {
+		return nil, fmt.Errorf("Epicの読み込みに失敗しました: %w", err)
+	}
+
+	// 重複を先に検出し、代表のファイルのみを以降の診断に使う
+	issues, issueDuplicates := checkDuplicateIDs(issues, "Issue", statuses, report, opts.Fix)
+	epics, epicDuplicates := checkDuplicateIDs(epics, "Epic", statuses, report, opts.Fix)
+
+	for _, f := range issues {
+		if err := utils.ValidateIssueWithStatuses(statuses, f.issue); err != nil {
+			report.add(invalidFieldProblem(f, err))
+		}
+	}
+	for _, f := range epics {
+		if err := utils.ValidateEpicWithStatuses(statuses, f.epic); err != nil {
+			report.add(invalidFieldProblem(f, err))
+		}
+	}
+
+	checkFilenames(cfg, issues, issueDuplicates, report, opts.Fix)
+	checkFilenames(cfg, epics, epicDuplicates, report, opts.Fix)
+
+	// 存在しないEpicへの参照
+	epicIDs := make(map[int]bool, len(epics))
+	for _, f := range epics {
+		epicIDs[f.id] = true
+	}
+	for _, f := range issues {
+		if f.issue.Epic > 0 && !epicIDs[f.issue.Epic] {
+			report.add(DoctorProblem{
+				Kind:    ProblemOrphanEpic,
+				File:    f.path,
+				Line:    f.line("epic"),
+				ID:      f.id,
+				Message: fmt.Sprintf("存在しないEpic（ID=%d）を参照しています", f.issue.Epic),
+			})
+		}
+	}
+
+	checkDependencies(issues, report)
+
+	if err := checkOrderCSV(cfg, issues, report, opts.Fix); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// loadBacklogFiles - ディレクトリ内のIssueまたはEpicのファイルをすべて読み込む
+// 重複したIDも含めてそのまま返し、解析できないファイルは問題として記録する
+func loadBacklogFiles(directory string, isIssue bool, report *DoctorReport) ([]*backlogFile, error) {
+	entries, err := os.ReadDir(directory)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []*backlogFile
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".md" {
+			continue
+		}
+
+		path := filepath.Join(directory, entry.Name())
+		if isIssue {
+			issue, err := parser.ParseIssueFile(path)
+			if err != nil {
+				report.add(DoctorProblem{Kind: ProblemParseError, File: path, Line: 1, Message: err.Error()})
+				continue
+			}
+			files = append(files, &backlogFile{path: path, id: issue.ID, title: issue.Title, status: issue.Status, issue: issue, doc: issue.FrontMatter})
+		} else {
+			epic, err := parser.ParseEpicFile(path)
+			if err != nil {
+				report.add(DoctorProblem{Kind: ProblemParseError, File: path, Line: 1, Message: err.Error()})
+				continue
+			}
+			files = append(files, &backlogFile{path: path, id: epic.ID, title: epic.Title, status: epic.Status, epic: epic, doc: epic.FrontMatter})
+		}
+	}
+
+	return files, nil
+}
+
+// checkDuplicateIDs - 同じIDのファイルを検出する
+// 同じIDのファイルのうち1つを代表とし、それ以外を重複として報告する。
+// 内容がまったく同じ重複ファイルは安全に削除できるため、fixの場合は代表以外を削除する。
+// 以降の診断に使う代表のファイルの一覧と、解消できなかった重複IDを返す
+func checkDuplicateIDs(files []*backlogFile, kind string, statuses models.StatusSet, report *DoctorReport, fix bool) ([]*backlogFile, map[int]bool) {
+	byID := make(map[int][]*backlogFile)
+	var ids []int
+	for _, f := range files {
+		if _, exists := byID[f.id]; !exists {
+			ids = append(ids, f.id)
+		}
+		byID[f.id] = append(byID[f.id], f)
+	}
+	sort.Ints(ids)
+
+	var primaries []*backlogFile
+	unresolved := make(map[int]bool)
+	for _, id := range ids {
+		group := byID[id]
+		identical := len(group) > 1 && sameContents(group)
+		primary := primaryFile(group, statuses, identical)
+		primaries = append(primaries, primary)
+
+		for _, f := range group {
+			if f == primary {
+				continue
+			}
+			message := fmt.Sprintf("%s ID=%d が %s と重複しています", kind, id, filepath.Base(primary.path))
+			if identical {
+				message += "（内容が同一のため削除できます）"
+			}
+			problem := report.add(DoctorProblem{Kind: ProblemDuplicateID, File: f.path, Line: f.line("id"), ID: id, Message: message, Fixable: identical})
+
+			if fix && identical {
+				utils.RecordSelfRemoval(f.path)
+				if err := os.Remove(f.path); err != nil {
+					logger.Warn("重複ファイルの削除に失敗しました", "file", f.path, "error", err)
+					continue
+				}
+				problem.Fixed = true
+			}
+		}
+		if len(group) > 1 && !identical {
+			unresolved[id] = true
+		}
+	}
+
+	return primaries, unresolved
+}
+
+// primaryFile - 同じIDのファイルのうち代表とするファイルを選ぶ
+// 内容が同一の場合はファイル名が正しいものを、異なる場合はReadAllIssuesと同様に完了扱いのものを優先する
+func primaryFile(group []*backlogFile, statuses models.StatusSet, identical bool) *backlogFile {
+	for _, f := range group {
+		if identical && filepath.Base(f.path) == utils.GenerateFilenameWithStatuses(statuses, f.id, f.status, f.title) {
+			return f
+		}
+		if !identical && statuses.IsDone(f.status) {
+			return f
+		}
+	}
+	return group[0]
+}
+
+// sameContents - すべてのファイルの内容が同一かどうかを返す
+func sameContents(files []*backlogFile) bool {
+	first, err := os.ReadFile(files[0].path)
+	if err != nil {
+		return false
+	}
+	for _, f := range files[1:] {
+		data, err := os.ReadFile(f.path)
+		if err != nil || !bytes.Equal(first, data) {
+			return false
+		}
+	}
+	return true
+}
+
+// checkFilenames - ファイル名がFront Matterの内容と一致しているかを確認する
+// 重複が解消されていないIDやステータスが不正なファイルは、正しいファイル名が決まらないため修正しない
+func checkFilenames(cfg *config.Config, files []*backlogFile, duplicates map[int]bool, report *DoctorReport, fix bool) {
+	statuses := cfg.StatusSet()
+
+	for _, f := range files {
+		name := filepath.Base(f.path)
+		if !statuses.IsValid(f.status) || f.id <= 0 {
+			continue
+		}
+		expected := utils.GenerateFilenameWithStatuses(statuses, f.id, f.status, f.title)
+		if name == expected {
+			continue
+		}
+
+		var message string
+		line := 1
+		id, status, _, err := utils.ParseFilenameWithStatuses(statuses, name)
+		switch {
+		case err != nil:
+			message = fmt.Sprintf("ファイル名の形式が不正です（%v）", err)
+		case id != f.id:
+			message = fmt.Sprintf("ファイル名のID（%d）がFront Matterのid（%d）と一致しません", id, f.id)
+			line = f.line("id")
+		case status != f.status:
+			message = fmt.Sprintf("ファイル名のステータス（%s）がFront Matterのstatus（%s）と一致しません", status, f.status)
+			line = f.line("status")
+		default:
+			message = "ファイル名のタイトルがFront Matterのtitleと一致しません"
+			line = f.line("title")
+		}
+		message += fmt.Sprintf("。正しいファイル名は %s です", expected)
+
+		fixable := !duplicates[f.id]
+		problem := report.add(DoctorProblem{Kind: ProblemFilenameMismatch, File: f.path, Line: line, ID: f.id, Message: message, Fixable: fixable})
+		if !fix || !fixable {
+			continue
+		}
+
+		newPath := filepath.Join(filepath.Dir(f.path), expected)
+		if err := fileops.RenameWithPolicy(f.path, newPath, cfg.ConflictPolicy(), cfg.ConflictsDir()); err != nil {
+			logger.Warn("ファイル名を修正できませんでした", "file", name, "error", err)
+			continue
+		}
+		problem.Fixed = true
+		f.path = newPath
+	}
+}
+
+// invalidFieldProblem - バリデーションエラーを不正なフィールドの行番号付きの問題にする
+func invalidFieldProblem(f *backlogFile, err error) DoctorProblem {
+	line := 1
+	var validationErr *utils.ValidationError
+	if errors.As(err, &validationErr) {
+		line = f.line(validationErr.Field)
+	}
+	return DoctorProblem{Kind: ProblemInvalidField, File: f.path, Line: line, ID: f.id, Message: err.Error()}
+}
+
+// checkDependencies - blocked_byの参照切れと循環を検出する
+func checkDependencies(files []*backlogFile, report *DoctorReport) {
+	issues := make([]*models.Issue, 0, len(files))
+	fileByID := make(map[int]*backlogFile, len(files))
+	for _, f := range files {
+		issues = append(issues, f.issue)
+		fileByID[f.id] = f
+	}
+
+	// syncやlistの読み込みと同じ検出を使う
+	for _, problem := range fileops.FindDependencyProblems(issues) {
+		message := fmt.Sprintf("blocked_byに存在しないIssue（ID=%d）が指定されています", problem.MissingID)
+		if problem.Cycle != nil {
+			message = fmt.Sprintf("Issueの依存関係が循環しています（%s）", fileops.FormatCycle(problem.Cycle))
+		}
+		f := fileByID[problem.IssueID]
+		report.add(DoctorProblem{
+			Kind:    ProblemDependency,
+			File:    f.path,
+			Line:    f.line("blocked_by"),
+			ID:      f.id,
+			Message: message,
+		})
+	}
+}
+
+// checkOrderCSV - order.csvの行とIssueファイルの対応を確認する
+// 不要な行（ファイルがない・完了済み・重複）と、order.csvにない未完了のIssueを検出する
+func checkOrderCSV(cfg *config.Config, files []*backlogFile, report *DoctorReport, fix bool) error {
+	statuses := cfg.StatusSet()
+
+	orderItems, fileColumns, err := parser.ReadOrderCSVWithColumns(cfg.OrderCSV)
+	if err != nil {
+		report.add(DoctorProblem{Kind: ProblemParseError, File: cfg.OrderCSV, Message: err.Error()})
+		return nil
+	}
+
+	issues := make(map[int]*models.Issue, len(files))
+	for _, f := range files {
+		issues[f.id] = f.issue
+	}
+
+	var problems []*DoctorProblem
+	var kept []models.OrderCSVItem
+	seen := make(map[int]bool)
+	for i, item := range orderItems {
+		var message string
+		issue, exists := issues[item.ID]
+		switch {
+		case seen[item.ID]:
+			message = "同じIDの行が重複しています"
+		case !exists:
+			message = "対応するIssueファイルがありません"
+		case statuses.IsDone(issue.Status):
+			message = fmt.Sprintf("完了済み（%s）のIssueが残っています", issue.Status)
+		default:
+			seen[item.ID] = true
+			kept = append(kept, item)
+			continue
+		}
+		problems = append(problems, report.add(DoctorProblem{
+			Kind:    ProblemStaleOrder,
+			File:    cfg.OrderCSV,
+			Line:    i + 2, // 1行目はヘッダー
+			ID:      item.ID,
+			Message: fmt.Sprintf("ID=%d: %s", item.ID, message),
+			Fixable: true,
+		}))
+	}
+
+	// order.csvにない未完了のIssueをsyncと同じく設定した位置(placement)に追加する
+	var missing []*models.Issue
+	for _, issue := range issues {
+		if !statuses.IsDone(issue.Status) && !seen[issue.ID] {
+			missing = append(missing, issue)
+		}
+	}
+	sort.Slice(missing, func(i, j int) bool { return missing[i].ID < missing[j].ID })
+	for _, issue := range missing {
+		problems = append(problems, report.add(DoctorProblem{
+			Kind:    ProblemMissingOrder,
+			File:    cfg.OrderCSV,
+			ID:      issue.ID,
+			Message: fmt.Sprintf("未完了のIssue（ID=%d）がorder.csvにありません", issue.ID),
+			Fixable: true,
+		}))
+	}
+	kept = placeNewItems(cfg, kept, missing, issues)
+
+	if !fix || len(problems) == 0 {
+		return nil
+	}
+
+	if err := parser.WriteOrderCSVWithColumns(cfg.OrderCSV, kept, orderColumns(cfg, fileColumns)); err != nil {
+		return fmt.Errorf("order.csvの書き込みに失敗しました: %w", err)
+	}
+	for _, p := range problems {
+		p.Fixed = true
+	}
+	return nil
+}
+
+// relativePath - baseからの相対パスを返す（求められない場合はそのまま返す）
+func relativePath(base, path string) string {
+	if rel, err := filepath.Rel(base, path); err == nil {
+		return rel
+	}
+	return path
+}
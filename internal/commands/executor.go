@@ -1,12 +1,18 @@
 package commands
 
 import (
+	"sync"
+
 	"github.com/moai/instant-backlog/internal/config"
 	"github.com/moai/instant-backlog/internal/watcher"
+	"github.com/moai/instant-backlog/pkg/utils"
 )
 
 // CommandExecutorImpl - watcher.CommandExecutor の実装
-type CommandExecutorImpl struct{}
+type CommandExecutorImpl struct {
+	mu      sync.Mutex
+	indexes map[string]*BacklogIndex // projectsディレクトリをキーとした監視中のプロジェクトのインデックス
+}
 
 // ExecuteSync - SyncCommand を実行
 func (e *CommandExecutorImpl) ExecuteSync(cfg *config.Config) error {
@@ -18,6 +24,35 @@ func (e *CommandExecutorImpl) ExecuteRename(cfg *config.Config) error {
 	return RenameCommand(cfg)
 }
 
+// ExecuteIncrementalSync - 変更されたファイルだけを読み直して IncrementalSyncCommand を実行
+// プロジェクトごとのインデックスは最初の実行時にすべてのファイルを読み込んで作成する
+func (e *CommandExecutorImpl) ExecuteIncrementalSync(cfg *config.Config, changed []string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	key := utils.AbsPath(cfg.ProjectsDir)
+	index, ok := e.indexes[key]
+	if !ok {
+		var err error
+		if index, err = NewBacklogIndex(cfg); err != nil {
+			return err
+		}
+		if e.indexes == nil {
+			e.indexes = make(map[string]*BacklogIndex)
+		}
+		e.indexes[key] = index
+	}
+	return IncrementalSyncCommand(cfg, index, changed)
+}
+
+// ReleaseIndex - 監視を停止したプロジェクトのインデックスを破棄する
+// 停止中の変更を反映するため、監視を再開した後の最初の実行でインデックスを作成し直す
+func (e *CommandExecutorImpl) ReleaseIndex(projectsDir string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	delete(e.indexes, utils.AbsPath(projectsDir))
+}
+
 // RegisterCommandExecutor - コマンド実行インスタンスを登録
 func RegisterCommandExecutor() {
 	watcher.SetCommandExecutor(&CommandExecutorImpl{})
@@ -0,0 +1,67 @@
+package commands
+
+import (
+	"github.com/moai/instant-backlog/internal/config"
+	"github.com/moai/instant-backlog/internal/hooks"
+	"github.com/moai/instant-backlog/internal/models"
+)
+
+// orderEvents - 新しく作成されorder.csvに追加される未完了のIssueと、完了扱いになりorder.csvから削除されるIssueを返す
+// knownは前回のsyncの時点で存在したIssueのIDで、含まれるIssueは再オープンなどでorder.csvに追加されても作成とはみなさない
+// （前回のsyncの記録がない場合はnilで、order.csvになかったIssueを作成とみなす）
+func orderEvents(statuses models.StatusSet, known map[int]bool, before, after []models.OrderCSVItem, issuesByID map[int]*models.Issue) (created, closed []*models.Issue) {
+	listed := make(map[int]bool, len(before))
+	for _, item := range before {
+		issue, ok := issuesByID[item.ID]
+		if !listed[item.ID] && ok && statuses.IsDone(issue.Status) {
+			closed = append(closed, issue)
+		}
+		listed[item.ID] = true
+	}
+	for _, item := range after {
+		issue, ok := issuesByID[item.ID]
+		if !ok || listed[item.ID] || (known != nil && known[item.ID]) {
+			continue
+		}
+		created = append(created, issue)
+	}
+	return created, closed
+}
+
+// runSyncHooks - syncで発生したイベントのフックを実行する
+func runSyncHooks(cfg *config.Config, plan *SyncPlan) {
+	if len(cfg.Hooks) == 0 {
+		return
+	}
+
+	summary := &hooks.SyncSummary{Issues: len(plan.OrderAfter), Created: []int{}, Closed: []int{}, EpicsClosed: []int{}}
+	for _, issue := range plan.Created {
+		hooks.Run(cfg, hooks.Event{Name: config.HookIssueCreated, Issue: hookIssue(issue)})
+		summary.Created = append(summary.Created, issue.ID)
+	}
+	for _, issue := range plan.Closed {
+		hooks.Run(cfg, hooks.Event{Name: config.HookIssueClosed, Issue: hookIssue(issue)})
+		summary.Closed = append(summary.Closed, issue.ID)
+	}
+	runEpicClosedHooks(cfg, plan.EpicChanges)
+	for _, change := range plan.EpicChanges {
+		summary.EpicsClosed = append(summary.EpicsClosed, change.Epic.ID)
+	}
+	hooks.Run(cfg, hooks.Event{Name: config.HookAfterSync, Sync: summary})
+}
+
+// runEpicClosedHooks - 完了にしたEpicごとにon_epic_closedのフックを実行する
+func runEpicClosedHooks(cfg *config.Config, changes []EpicStatusChange) {
+	for _, change := range changes {
+		epic := change.Epic
+		hooks.Run(cfg, hooks.Event{
+			Name: config.HookEpicClosed,
+			Epic: &hooks.Epic{ID: epic.ID, Title: epic.Title, Status: epic.Status, From: change.From},
+		})
+	}
+}
+
+// hookIssue - フックに渡すIssueの内容
+func hookIssue(issue *models.Issue) *hooks.Issue {
+	return &hooks.Issue{ID: issue.ID, Title: issue.Title, Status: issue.Status, Epic: issue.Epic, Estimate: issue.Estimate}
+}
@@ -0,0 +1,206 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/moai/instant-backlog/internal/config"
+	"github.com/moai/instant-backlog/internal/fileops"
+	"github.com/moai/instant-backlog/internal/logger"
+	"github.com/moai/instant-backlog/internal/models"
+	"github.com/moai/instant-backlog/internal/parser"
+	"github.com/moai/instant-backlog/pkg/utils"
+)
+
+// BacklogIndex - プロジェクトのIssueとEpicをファイルごとに保持するインデックス
+// 変更されたファイルだけを読み直すことで、増分syncでのファイルの読み込みを減らす
+type BacklogIndex struct {
+	issuesDir string                   // issuesディレクトリの絶対パス
+	epicDir   string                   // epicディレクトリの絶対パス
+	issues    map[string]*models.Issue // ファイルのパスをキーとしたIssue
+	epics     map[string]*models.Epic  // ファイルのパスをキーとしたEpic
+}
+
+// indexChanges - インデックスの更新で影響を受けるファイルとEpic
+type indexChanges struct {
+	issuePaths []string     // 追加・変更されたIssueファイル（削除されたものは含まない）
+	epicIDs    map[int]bool // ステータスを確認するEpic（変更されたIssueの変更前後のEpicと、変更されたEpic）
+}
+
+// NewBacklogIndex - すべてのIssueとEpicのファイルを読み込んでインデックスを作成する
+func NewBacklogIndex(cfg *config.Config) (*BacklogIndex, error) {
+	index := &BacklogIndex{
+		issuesDir: utils.AbsPath(cfg.IssuesDir),
+		epicDir:   utils.AbsPath(cfg.EpicDir),
+		issues:    make(map[string]*models.Issue),
+		epics:     make(map[string]*models.Epic),
+	}
+
+	for _, dir := range []string{index.issuesDir, index.epicDir} {
+		files, err := os.ReadDir(dir)
+		if err != nil {
+			return nil, fmt.Errorf("ディレクトリの読み込みに失敗しました: %w", err)
+		}
+		var paths []string
+		for _, file := range files {
+			paths = append(paths, filepath.Join(dir, file.Name()))
+		}
+		index.Update(paths)
+	}
+
+	logger.Debug("インデックスを作成しました", "issues", len(index.issues), "epics", len(index.epics))
+	return index, nil
+}
+
+// Update - 変更されたファイルを読み直してインデックスを更新し、影響を受けるファイルとEpicを返す
+// 存在しなくなったファイル（削除・リネーム元）はインデックスから除き、issues/epicディレクトリ以外のファイルと.md以外のファイルは無視する
+func (x *BacklogIndex) Update(paths []string) indexChanges {
+	changes := indexChanges{epicIDs: make(map[int]bool)}
+	seen := make(map[string]bool, len(paths))
+
+	for _, path := range paths {
+		path = utils.AbsPath(path)
+		if seen[path] || filepath.Ext(path) != ".md" {
+			continue
+		}
+		seen[path] = true
+
+		switch filepath.Dir(path) {
+		case x.issuesDir:
+			if old, ok := x.issues[path]; ok {
+				changes.epicIDs[old.Epic] = true
+				delete(x.issues, path)
+			}
+			issue, ok := parseIndexFile(path, parser.ParseIssueFile)
+			if !ok {
+				continue
+			}
+			x.issues[path] = issue
+			changes.issuePaths = append(changes.issuePaths, path)
+			changes.epicIDs[issue.Epic] = true
+
+		case x.epicDir:
+			if old, ok := x.epics[path]; ok {
+				changes.epicIDs[old.ID] = true
+				delete(x.epics, path)
+			}
+			epic, ok := parseIndexFile(path, parser.ParseEpicFile)
+			if !ok {
+				continue
+			}
+			x.epics[path] = epic
+			changes.epicIDs[epic.ID] = true
+		}
+	}
+
+	return changes
+}
+
+// Issues - IDごとに採用するIssueをID順に返す（ReadAllIssuesWithStatusesと同じ規則で重複を解決する）
+func (x *BacklogIndex) Issues(statuses models.StatusSet) []*models.Issue {
+	paths := make([]string, 0, len(x.issues))
+	for path := range x.issues {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	parsed := make([]*models.Issue, 0, len(paths))
+	for _, path := range paths {
+		parsed = append(parsed, x.issues[path])
+	}
+	return fileops.UniqueIssuesWithStatuses(parsed, statuses)
+}
+
+// epicFiles - 指定したIDのEpicのファイルのパスをファイル名順に返す
+func (x *BacklogIndex) epicFiles(ids map[int]bool) []string {
+	var paths []string
+	for path, epic := range x.epics {
+		if ids[epic.ID] {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// parseIndexFile - インデックスに登録するファイルを解析する（削除されたファイルと解析できないファイルはfalse）
+func parseIndexFile[T any](path string, parse func(string) (T, error)) (T, bool) {
+	var zero T
+	if info, err := os.Stat(path); err != nil || info.IsDir() {
+		return zero, false
+	}
+
+	value, err := parse(path)
+	if err != nil {
+		logger.Warn("ファイルの解析に失敗しました", "file", filepath.Base(path), "error", err)
+		return zero, false
+	}
+	return value, true
+}
+
+// IncrementalSyncCommand - 変更されたファイルだけを読み直して、order.csv・Epicのステータス・ファイル名を更新する
+// order.csvはインデックスのすべてのIssueから求め、Epicのステータスとファイル名は変更されたファイルに関係するものだけを確認する。
+// order.csvが変更された場合も、order.csvは毎回読み込むためIssueファイルと同じように反映される
+func IncrementalSyncCommand(cfg *config.Config, index *BacklogIndex, changed []string) error {
+	statuses := cfg.StatusSet()
+	logger.Debug("増分syncを開始します", "files", len(changed))
+
+	// sync自身による変更もインデックスに反映する
+	stop := utils.TrackSelfWrites()
+	defer func() { index.Update(stop()) }()
+
+	changes := index.Update(changed)
+	issues := index.Issues(statuses)
+	fileops.WarnDependencyProblems(fileops.FindDependencyProblems(issues))
+
+	plan, err := planOrderSync(cfg, SyncOptions{}, issues)
+	if err != nil {
+		return err
+	}
+
+	// order.csvの編集でepicを書き戻すIssueの変更前後のEpicも確認する
+	for _, wb := range plan.WriteBacks {
+		changes.epicIDs[wb.Change.Before.Epic] = true
+		changes.epicIDs[wb.Issue.Epic] = true
+	}
+
+	var epics []*models.Epic
+	for _, path := range index.epicFiles(changes.epicIDs) {
+		epics = append(epics, index.epics[path])
+	}
+	plan.EpicChanges = epicStatusChanges(statuses, epics, issues)
+
+	if err := applySyncPlan(cfg, plan); err != nil {
+		return err
+	}
+
+	// Epicの更新でファイル名が変わった場合も含めるため、書き込み後のインデックスからファイル名の変更を求める
+	index.Update(stop())
+	stop = utils.TrackSelfWrites()
+
+	var renames []RenamePlan
+	for _, path := range changes.issuePaths {
+		if issue, ok := index.issues[path]; ok {
+			if rename, ok := planFileRename(path, statuses, false, issue.ID, issue.Status, issue.Title); ok {
+				renames = append(renames, rename)
+			}
+		}
+	}
+	for _, path := range index.epicFiles(changes.epicIDs) {
+		epic := index.epics[path]
+		if rename, ok := planFileRename(path, statuses, true, epic.ID, epic.Status, epic.Title); ok {
+			renames = append(renames, rename)
+		}
+	}
+	renameErr := ExecuteRenamePlan(cfg, renames)
+
+	// ファイル名の更新に失敗しても、反映済みの変更のWebhookとフックは実行する
+	enqueueSyncWebhooks(cfg, plan)
+	runSyncHooks(cfg, plan)
+	if renameErr != nil {
+		return fmt.Errorf("ファイル名の更新に失敗しました: %w", renameErr)
+	}
+	return nil
+}
@@ -2,26 +2,43 @@ package commands
 
 import (
 	"fmt"
-	"io"
 	"os"
 	"path/filepath"
 	"strings"
 
 	"github.com/moai/instant-backlog/internal/config"
 	"github.com/moai/instant-backlog/internal/embedtemplate"
+	"github.com/moai/instant-backlog/internal/logger"
+	"github.com/moai/instant-backlog/pkg/utils"
 )
 
 // InitCommand - プロジェクトを初期化するコマンド
 func InitCommand(cfg *config.Config, projectPath string) error {
 	// プロジェクトパスを設定
+	// 指定されていない場合は設定のベースディレクトリ（設定ファイルがなければ現在のディレクトリ）を使用
 	targetPath := projectPath
+	projectCfg := cfg
 	if targetPath == "" {
-		// プロジェクトパスが指定されていない場合は現在のディレクトリを使用
-		currentDir, err := os.Getwd()
+		targetPath = cfg.BaseDir
+	}
+	if targetPath == "" || projectPath != "" {
+		if targetPath == "" {
+			currentDir, err := os.Getwd()
+			if err != nil {
+				return fmt.Errorf("現在のディレクトリを取得できません: %v", err)
+			}
+			targetPath = currentDir
+		}
+
+		// 対象ディレクトリに設定ファイルがあればそのディレクトリ構成を使用
+		loaded, err := config.LoadConfig(targetPath)
 		if err != nil {
-			return fmt.Errorf("現在のディレクトリを取得できません: %v", err)
+			return fmt.Errorf("設定ファイルの読み込みに失敗しました: %v", err)
 		}
-		targetPath = currentDir
+		if loaded.TemplatePath == "" {
+			loaded.TemplatePath = cfg.TemplatePath
+		}
+		projectCfg = loaded
 	}
 
 	// ディレクトリが存在しない場合は作成
@@ -30,9 +47,9 @@ func InitCommand(cfg *config.Config, projectPath string) error {
 	}
 
 	// プロジェクト内のディレクトリ作成
-	projectsDir := filepath.Join(targetPath, "projects")
-	epicDir := filepath.Join(projectsDir, "epic")
-	issuesDir := filepath.Join(projectsDir, "issues")
+	projectsDir := projectCfg.ProjectsDir
+	epicDir := projectCfg.EpicDir
+	issuesDir := projectCfg.IssuesDir
 
 	// ディレクトリを作成
 	if err := os.MkdirAll(epicDir, 0755); err != nil {
@@ -47,8 +64,8 @@ func InitCommand(cfg *config.Config, projectPath string) error {
 	templateFound := false
 
 	// 設定からテンプレートパスを取得
-	if cfg.TemplatePath != "" {
-		templateDir = cfg.TemplatePath
+	if projectCfg.TemplatePath != "" {
+		templateDir = projectCfg.TemplatePath
 		if _, err := os.Stat(templateDir); err == nil {
 			templateFound = true
 		}
@@ -93,11 +110,11 @@ func InitCommand(cfg *config.Config, projectPath string) error {
 		}
 	}
 
-	fmt.Printf("プロジェクトを初期化しました: %s\n", targetPath)
-	fmt.Println("次のステップ:")
-	fmt.Println("1. projects/README.mdファイルを参照して運用方法を確認")
-	fmt.Println("2. 必要に応じてEpicとIssueを編集")
-	fmt.Println("3. 'instant-backlog watch projects'コマンドを実行して自動監視を開始")
+	logger.Info("プロジェクトを初期化しました", "path", targetPath)
+	logger.Info("次のステップ:")
+	logger.Info("1. projects/README.mdファイルを参照して運用方法を確認")
+	logger.Info("2. 必要に応じてEpicとIssueを編集")
+	logger.Info("3. 'instant-backlog watch projects'コマンドを実行して自動監視を開始")
 
 	return nil
 }
@@ -181,30 +198,18 @@ func copyDir(src, dst string) error {
 
 // copyFile - ファイルをコピー
 func copyFile(src, dst string) error {
-	// 送信元ファイルを開く
-	in, err := os.Open(src)
-	if err != nil {
-		return err
-	}
-	defer in.Close()
-
 	// 送信元ファイルの情報を取得
-	srcInfo, err := in.Stat()
+	srcInfo, err := os.Stat(src)
 	if err != nil {
 		return err
 	}
 
-	// 送信先ファイルを作成
-	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, srcInfo.Mode())
+	// 送信元ファイルを読み込み
+	data, err := os.ReadFile(src)
 	if err != nil {
 		return err
 	}
-	defer out.Close()
 
-	// コピー実行
-	if _, err = io.Copy(out, in); err != nil {
-		return err
-	}
-
-	return nil
+	// 一時ファイル経由で書き込み
+	return utils.WriteFileAtomic(dst, data, srcInfo.Mode().Perm())
 }
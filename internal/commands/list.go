@@ -0,0 +1,375 @@
+package commands
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/moai/instant-backlog/internal/config"
+	"github.com/moai/instant-backlog/internal/fileops"
+	"github.com/moai/instant-backlog/internal/logger"
+	"github.com/moai/instant-backlog/internal/models"
+	"github.com/moai/instant-backlog/internal/parser"
+)
+
+// 一覧の出力形式
+const (
+	ListFormatTable = "table"
+	ListFormatJSON  = "json"
+	ListFormatCSV   = "csv"
+)
+
+// 一覧の並び順
+const (
+	ListSortOrder    = "order"
+	ListSortID       = "id"
+	ListSortEstimate = "estimate"
+)
+
+// ListOptions - listコマンドのオプション
+type ListOptions struct {
+	Epics       bool     // Issueの代わりにEpicを一覧表示する
+	Statuses    []string // 表示するステータス（空の場合はすべて）
+	Open        bool     // 未完了のもののみ表示する
+	Epic        int      // 指定したEpicに紐づくIssueのみ表示する（0の場合はすべて）
+	MinEstimate int      // 見積もりの下限（負の場合は指定なし）
+	MaxEstimate int      // 見積もりの上限（負の場合は指定なし）
+	Title       string   // タイトルに含まれる文字列（大文字小文字は区別しない）
+	Sort        string   // 並び順（order, id, estimate）
+	Format      string   // 出力形式（table, json, csv）
+}
+
+// issueListItem - Issue一覧の1行
+type issueListItem struct {
+	Rank      int    `json:"rank,omitempty"` // order.csvでの順位（1始まり、含まれない場合は0）
+	ID        int    `json:"id"`
+	Title     string `json:"title"`
+	Status    string `json:"status"`
+	Epic      int    `json:"epic"`
+	Estimate  int    `json:"estimate"`
+	BlockedBy []int  `json:"blocked_by,omitempty"`
+}
+
+// epicListItem - Epic一覧の1行
+type epicListItem struct {
+	ID         int    `json:"id"`
+	Title      string `json:"title"`
+	Status     string `json:"status"`
+	Issues     int    `json:"issues"`      // 紐づくIssueの数
+	OpenIssues int    `json:"open_issues"` // 紐づく未完了のIssueの数
+	Estimate   int    `json:"estimate"`    // 紐づく未完了のIssueの見積もり合計
+}
+
+// ListCommand - IssueまたはEpicを条件で絞り込んで一覧表示する
+func ListCommand(cfg *config.Config, opts ListOptions, w io.Writer) error {
+	if opts.Format == "" {
+		opts.Format = ListFormatTable
+	}
+	if opts.Sort == "" {
+		opts.Sort = ListSortOrder
+	}
+	switch opts.Format {
+	case ListFormatTable, ListFormatJSON, ListFormatCSV:
+	default:
+		return fmt.Errorf("不明な出力形式です: %s（table, json, csv のいずれかを指定してください）", opts.Format)
+	}
+	switch opts.Sort {
+	case ListSortOrder, ListSortID, ListSortEstimate:
+	default:
+		return fmt.Errorf("不明な並び順です: %s（order, id, estimate のいずれかを指定してください）", opts.Sort)
+	}
+
+	statuses := cfg.StatusSet()
+	issues, dependencyProblems, err := fileops.ReadAllIssuesWithProblems(cfg.IssuesDir, statuses)
+	if err != nil {
+		return fmt.Errorf("Issueの読み込みに失敗しました: %w", err)
+	}
+	fileops.WarnDependencyProblems(dependencyProblems)
+
+	if opts.Epics {
+		epics, err := fileops.ReadAllEpics(cfg.EpicDir)
+		if err != nil {
+			return fmt.Errorf("Epicの読み込みに失敗しました: %w", err)
+		}
+		return writeEpicList(w, opts.Format, filterEpics(epics, issues, statuses, opts))
+	}
+
+	// 手で編集した不正な行があっても一覧を表示する
+	orderItems, _, problems, err := parser.ReadOrderCSVWithProblems(cfg.OrderCSV)
+	if err != nil {
+		return fmt.Errorf("order.csvの読み込みに失敗しました: %w", err)
+	}
+	for _, problem := range problems {
+		logger.Warn("order.csvに不正な行があります", "line", problem.Line, "id", problem.ID, "error", problem.Message)
+	}
+	if cfg.OrderingMode() == config.OrderingRank {
+		// rankで並び順を管理する場合は、syncしていなくてもrankの並び順で表示する
+		orderItems, _, _ = rankedOrder(cfg, issues, orderItems, false)
+	}
+
+	return writeIssueList(w, opts.Format, filterIssues(issues, orderItems, statuses, opts))
+}
+
+// filterIssues - 条件に一致するIssueを指定した順に並べて返す
+func filterIssues(issues []*models.Issue, orderItems []models.OrderCSVItem, statuses models.StatusSet, opts ListOptions) []issueListItem {
+	rank := make(map[int]int, len(orderItems))
+	for i, item := range orderItems {
+		if _, exists := rank[item.ID]; !exists {
+			rank[item.ID] = i + 1
+		}
+	}
+
+	var items []issueListItem
+	for _, issue := range issues {
+		if !matchStatus(issue.Status, statuses, opts) {
+			continue
+		}
+		if opts.Epic > 0 && issue.Epic != opts.Epic {
+			continue
+		}
+		if opts.MinEstimate >= 0 && issue.Estimate < opts.MinEstimate {
+			continue
+		}
+		if opts.MaxEstimate >= 0 && issue.Estimate > opts.MaxEstimate {
+			continue
+		}
+		if !matchTitle(issue.Title, opts.Title) {
+			continue
+		}
+
+		items = append(items, issueListItem{
+			Rank:      rank[issue.ID],
+			ID:        issue.ID,
+			Title:     issue.Title,
+			Status:    issue.Status,
+			Epic:      issue.Epic,
+			Estimate:  issue.Estimate,
+			BlockedBy: issue.BlockedBy,
+		})
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i], items[j]
+		switch opts.Sort {
+		case ListSortOrder:
+			// order.csvに含まれるものを順位順に、含まれないものはその後にID順で並べる
+			if (a.Rank == 0) != (b.Rank == 0) {
+				return a.Rank != 0
+			}
+			if a.Rank != b.Rank {
+				return a.Rank < b.Rank
+			}
+		case ListSortEstimate:
+			if a.Estimate != b.Estimate {
+				return a.Estimate < b.Estimate
+			}
+		}
+		return a.ID < b.ID
+	})
+
+	return items
+}
+
+// filterEpics - 条件に一致するEpicをID順に並べて返す
+func filterEpics(epics []*models.Epic, issues []*models.Issue, statuses models.StatusSet, opts ListOptions) []epicListItem {
+	var items []epicListItem
+	for _, epic := range epics {
+		if !matchStatus(epic.Status, statuses, opts) || !matchTitle(epic.Title, opts.Title) {
+			continue
+		}
+
+		item := epicListItem{ID: epic.ID, Title: epic.Title, Status: epic.Status}
+		for _, issue := range issues {
+			if issue.Epic != epic.ID {
+				continue
+			}
+			item.Issues++
+			if !statuses.IsDone(issue.Status) {
+				item.OpenIssues++
+				item.Estimate += issue.Estimate
+			}
+		}
+		// 見積もりの条件は未完了のIssueの見積もり合計に適用する
+		if opts.MinEstimate >= 0 && item.Estimate < opts.MinEstimate {
+			continue
+		}
+		if opts.MaxEstimate >= 0 && item.Estimate > opts.MaxEstimate {
+			continue
+		}
+		items = append(items, item)
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		if opts.Sort == ListSortEstimate && items[i].Estimate != items[j].Estimate {
+			return items[i].Estimate < items[j].Estimate
+		}
+		return items[i].ID < items[j].ID
+	})
+
+	return items
+}
+
+// matchStatus - ステータスの絞り込み条件に一致するかどうかを返す
+func matchStatus(status string, statuses models.StatusSet, opts ListOptions) bool {
+	if opts.Open && statuses.IsDone(status) {
+		return false
+	}
+	if len(opts.Statuses) == 0 {
+		return true
+	}
+	for _, s := range opts.Statuses {
+		if strings.EqualFold(s, status) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchTitle - タイトルに指定した文字列が含まれるかどうかを返す
+func matchTitle(title, substr string) bool {
+	return substr == "" || strings.Contains(strings.ToLower(title), strings.ToLower(substr))
+}
+
+// writeIssueList - Issue一覧を指定した形式で出力する
+func writeIssueList(w io.Writer, format string, items []issueListItem) error {
+	header := []string{"rank", "id", "title", "status", "epic", "estimate", "blocked_by"}
+	rows := make([][]string, 0, len(items))
+	for _, item := range items {
+		rank := ""
+		if item.Rank > 0 {
+			rank = strconv.Itoa(item.Rank)
+		}
+		rows = append(rows, []string{
+			rank,
+			strconv.Itoa(item.ID),
+			item.Title,
+			item.Status,
+			strconv.Itoa(item.Epic),
+			strconv.Itoa(item.Estimate),
+			joinIDs(item.BlockedBy),
+		})
+	}
+
+	if format == ListFormatJSON {
+		if items == nil {
+			items = []issueListItem{}
+		}
+		return writeJSON(w, items)
+	}
+	return writeRows(w, format, header, rows)
+}
+
+// writeEpicList - Epic一覧を指定した形式で出力する
+func writeEpicList(w io.Writer, format string, items []epicListItem) error {
+	header := []string{"id", "title", "status", "issues", "open_issues", "estimate"}
+	rows := make([][]string, 0, len(items))
+	for _, item := range items {
+		rows = append(rows, []string{
+			strconv.Itoa(item.ID),
+			item.Title,
+			item.Status,
+			strconv.Itoa(item.Issues),
+			strconv.Itoa(item.OpenIssues),
+			strconv.Itoa(item.Estimate),
+		})
+	}
+
+	if format == ListFormatJSON {
+		if items == nil {
+			items = []epicListItem{}
+		}
+		return writeJSON(w, items)
+	}
+	return writeRows(w, format, header, rows)
+}
+
+// writeJSON - インデント付きのJSONで出力する
+func writeJSON(w io.Writer, v interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// writeRows - ヘッダーと行をCSVまたは整列した表として出力する
+func writeRows(w io.Writer, format string, header []string, rows [][]string) error {
+	if format == ListFormatCSV {
+		writer := csv.NewWriter(w)
+		if err := writer.Write(header); err != nil {
+			return err
+		}
+		if err := writer.WriteAll(rows); err != nil {
+			return err
+		}
+		return writer.Error()
+	}
+
+	// 全角文字の表示幅を考慮して列幅を求める
+	widths := make([]int, len(header))
+	upper := make([]string, len(header))
+	for i, h := range header {
+		upper[i] = strings.ToUpper(h)
+		widths[i] = displayWidth(upper[i])
+	}
+	for _, row := range rows {
+		for i, cell := range row {
+			if width := displayWidth(cell); width > widths[i] {
+				widths[i] = width
+			}
+		}
+	}
+
+	for _, row := range append([][]string{upper}, rows...) {
+		var line strings.Builder
+		for i, cell := range row {
+			line.WriteString(cell)
+			if i < len(row)-1 {
+				line.WriteString(strings.Repeat(" ", widths[i]-displayWidth(cell)+2))
+			}
+		}
+		if _, err := fmt.Fprintln(w, strings.TrimRight(line.String(), " ")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// joinIDs - IDのスライスをカンマ区切りの文字列にする
+func joinIDs(ids []int) string {
+	parts := make([]string, 0, len(ids))
+	for _, id := range ids {
+		parts = append(parts, strconv.Itoa(id))
+	}
+	return strings.Join(parts, ",")
+}
+
+// displayWidth - 端末での表示幅を返す（全角文字は2として数える）
+func displayWidth(s string) int {
+	width := 0
+	for len(s) > 0 {
+		r, size := utf8.DecodeRuneInString(s)
+		s = s[size:]
+		if isWideRune(r) {
+			width += 2
+		} else {
+			width++
+		}
+	}
+	return width
+}
+
+// isWideRune - 東アジアの全角文字かどうかを返す
+func isWideRune(r rune) bool {
+	return (r >= 0x1100 && r <= 0x115F) ||
+		(r >= 0x2E80 && r <= 0xA4CF) ||
+		(r >= 0xAC00 && r <= 0xD7A3) ||
+		(r >= 0xF900 && r <= 0xFAFF) ||
+		(r >= 0xFE30 && r <= 0xFE4F) ||
+		(r >= 0xFF00 && r <= 0xFF60) ||
+		(r >= 0xFFE0 && r <= 0xFFE6) ||
+		(r >= 0x20000 && r <= 0x3FFFD)
+}
@@ -0,0 +1,349 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/moai/instant-backlog/internal/config"
+	"github.com/moai/instant-backlog/internal/fileops"
+	"github.com/moai/instant-backlog/internal/logger"
+	"github.com/moai/instant-backlog/internal/models"
+	"github.com/moai/instant-backlog/internal/parser"
+)
+
+// MergeConflict - order.csvのマージで両方のブランチが異なる変更をした箇所
+type MergeConflict struct {
+	ID      int
+	Message string
+}
+
+// MergeOrderResult - order.csvのマージ結果
+type MergeOrderResult struct {
+	Items     []models.OrderCSVItem
+	Conflicts []MergeConflict // 両方で同じ列が異なる値に変更された行（oursの値を採用）
+	Moves     []MergeConflict // 両方で異なる位置に移動された行（oursの位置を採用）
+}
+
+// MergeOrderCommand - gitのマージドライバーとしてorder.csvをIssue IDごとにマージする
+// base, ours, theirs はgitが渡す共通祖先・自分・相手のファイルで、結果はoursに書き込む。
+// 両方で同じ列が異なる値に変更された行があればoursの値で書き込んだうえでエラーを返す
+func MergeOrderCommand(cfg *config.Config, basePath, oursPath, theirsPath string, w io.Writer) error {
+	base, baseColumns, err := parser.ReadOrderCSVWithColumns(basePath)
+	if err != nil {
+		return fmt.Errorf("共通祖先のorder.csvの読み込みに失敗しました: %w", err)
+	}
+	ours, oursColumns, err := parser.ReadOrderCSVWithColumns(oursPath)
+	if err != nil {
+		return fmt.Errorf("自分のorder.csvの読み込みに失敗しました: %w", err)
+	}
+	theirs, theirsColumns, err := parser.ReadOrderCSVWithColumns(theirsPath)
+	if err != nil {
+		return fmt.Errorf("相手のorder.csvの読み込みに失敗しました: %w", err)
+	}
+
+	result := MergeOrder(base, ours, theirs)
+	items := removeClosedItems(cfg, result.Items)
+
+	columns := mergeColumns(oursColumns, theirsColumns, baseColumns)
+	if err := parser.WriteOrderCSVWithColumns(oursPath, items, columns); err != nil {
+		return fmt.Errorf("order.csvの書き込みに失敗しました: %w", err)
+	}
+
+	for _, move := range result.Moves {
+		fmt.Fprintf(w, "移動: ID=%d: %s\n", move.ID, move.Message)
+	}
+	for _, conflict := range result.Conflicts {
+		fmt.Fprintf(w, "競合: ID=%d: %s\n", conflict.ID, conflict.Message)
+	}
+	if len(result.Conflicts) > 0 {
+		return fmt.Errorf("order.csvのマージで%d件の競合が発生しました（自分の値を採用しています）", len(result.Conflicts))
+	}
+	return nil
+}
+
+// MergeOrder - order.csvの行をIssue IDごとに3方向マージする
+//
+// どちらかで削除された行は削除し、追加された行はすべて残す。
+// 並び順は、どちらでも移動されていない行の共通祖先での順序を基準に、
+// 一方でのみ移動・追加された行をそのブランチでの直前の行の後ろに配置する。
+// 両方で異なる位置に移動された行はoursの位置を採用する
+func MergeOrder(base, ours, theirs []models.OrderCSVItem) MergeOrderResult {
+	base, ours, theirs = uniqueItems(base), uniqueItems(ours), uniqueItems(theirs)
+	baseRows, oursRows, theirsRows := itemsByID(base), itemsByID(ours), itemsByID(theirs)
+
+	// どちらかで削除された行を除く
+	survives := func(id int) bool {
+		_, inBase := baseRows[id]
+		_, inOurs := oursRows[id]
+		_, inTheirs := theirsRows[id]
+		return !inBase || (inOurs && inTheirs)
+	}
+	oursIDs, theirsIDs := survivingIDs(ours, survives), survivingIDs(theirs, survives)
+	baseIDs := survivingIDs(base, survives)
+
+	// 共通祖先から順序が変わっていない行（最長共通部分列）以外を、移動・追加された行とする
+	oursMoved, theirsMoved := movedIDs(baseIDs, oursIDs), movedIDs(baseIDs, theirsIDs)
+
+	var result MergeOrderResult
+	var order []int
+	for _, id := range baseIDs {
+		if !oursMoved[id] && !theirsMoved[id] {
+			order = append(order, id)
+		}
+	}
+
+	oursPrev, theirsPrev := previousIDs(oursIDs), previousIDs(theirsIDs)
+	for _, id := range oursIDs {
+		if !oursMoved[id] {
+			continue
+		}
+		if theirsMoved[id] && oursPrev[id] != theirsPrev[id] {
+			result.Moves = append(result.Moves, MergeConflict{ID: id, Message: "両方で異なる位置に移動されているため、自分の位置を採用しました"})
+		}
+		order = insertAfter(order, id, oursIDs, nil)
+	}
+	// theirsでの直前の行のうち、oursで移動された行は位置の基準にしない
+	for _, id := range theirsIDs {
+		if theirsMoved[id] && !oursMoved[id] {
+			order = insertAfter(order, id, theirsIDs, oursMoved)
+		}
+	}
+
+	// 行の内容を列ごとにマージする
+	for _, id := range order {
+		b, hasBase := baseRows[id]
+		o, inOurs := oursRows[id]
+		t, inTheirs := theirsRows[id]
+		switch {
+		case !inTheirs:
+			result.Items = append(result.Items, o)
+		case !inOurs:
+			result.Items = append(result.Items, t)
+		default:
+			if !hasBase {
+				// 両方で追加された行は共通祖先を空として扱う
+				b = models.OrderCSVItem{ID: id}
+			}
+			item, conflicts := mergeOrderRow(b, o, t)
+			result.Items = append(result.Items, item)
+			for _, conflict := range conflicts {
+				result.Conflicts = append(result.Conflicts, MergeConflict{ID: id, Message: conflict})
+			}
+		}
+	}
+
+	return result
+}
+
+// mergeOrderRow - 行の各列を3方向マージする
+// 両方で異なる値に変更された列はoursの値を採用し、競合として返す
+func mergeOrderRow(base, ours, theirs models.OrderCSVItem) (models.OrderCSVItem, []string) {
+	var conflicts []string
+	merged := ours
+
+	var conflict bool
+	if merged.Title, conflict = mergeValue(base.Title, ours.Title, theirs.Title); conflict {
+		conflicts = append(conflicts, fmt.Sprintf("title: 自分=%s, 相手=%s", ours.Title, theirs.Title))
+	}
+	if merged.Epic, conflict = mergeValue(base.Epic, ours.Epic, theirs.Epic); conflict {
+		conflicts = append(conflicts, fmt.Sprintf("epic: 自分=%d, 相手=%d", ours.Epic, theirs.Epic))
+	}
+	if merged.Estimate, conflict = mergeValue(base.Estimate, ours.Estimate, theirs.Estimate); conflict {
+		conflicts = append(conflicts, fmt.Sprintf("estimate: 自分=%d, 相手=%d", ours.Estimate, theirs.Estimate))
+	}
+
+	columns := make(map[string]bool)
+	for _, extra := range []map[string]string{base.Extra, ours.Extra, theirs.Extra} {
+		for column := range extra {
+			columns[column] = true
+		}
+	}
+	if len(columns) > 0 {
+		names := make([]string, 0, len(columns))
+		for column := range columns {
+			names = append(names, column)
+		}
+		sort.Strings(names)
+
+		merged.Extra = make(map[string]string, len(names))
+		for _, column := range names {
+			value, conflict := mergeValue(base.Extra[column], ours.Extra[column], theirs.Extra[column])
+			if conflict {
+				conflicts = append(conflicts, fmt.Sprintf("%s: 自分=%s, 相手=%s", column, ours.Extra[column], theirs.Extra[column]))
+			}
+			merged.Extra[column] = value
+		}
+	}
+
+	return merged, conflicts
+}
+
+// mergeValue - 3方向マージで採用する値を返す（両方で異なる値に変更された場合はoursの値と競合を返す）
+func mergeValue[T comparable](base, ours, theirs T) (T, bool) {
+	switch {
+	case ours == theirs, theirs == base:
+		return ours, false
+	case ours == base:
+		return theirs, false
+	}
+	return ours, true
+}
+
+// uniqueItems - 同じIDの行が複数ある場合に最初の行のみを残す
+func uniqueItems(items []models.OrderCSVItem) []models.OrderCSVItem {
+	seen := make(map[int]bool, len(items))
+	unique := make([]models.OrderCSVItem, 0, len(items))
+	for _, item := range items {
+		if !seen[item.ID] {
+			seen[item.ID] = true
+			unique = append(unique, item)
+		}
+	}
+	return unique
+}
+
+// itemsByID - IDから行を引くマップを作成する
+func itemsByID(items []models.OrderCSVItem) map[int]models.OrderCSVItem {
+	rows := make(map[int]models.OrderCSVItem, len(items))
+	for _, item := range items {
+		rows[item.ID] = item
+	}
+	return rows
+}
+
+// survivingIDs - マージ後に残る行のIDを順に返す
+func survivingIDs(items []models.OrderCSVItem, survives func(int) bool) []int {
+	ids := make([]int, 0, len(items))
+	for _, item := range items {
+		if survives(item.ID) {
+			ids = append(ids, item.ID)
+		}
+	}
+	return ids
+}
+
+// movedIDs - 共通祖先から移動または追加された行のIDを返す
+// 共通祖先との最長共通部分列に含まれない行を移動されたものとする
+func movedIDs(base, side []int) map[int]bool {
+	inBase := make(map[int]bool, len(base))
+	for _, id := range base {
+		inBase[id] = true
+	}
+	var common []int
+	for _, id := range side {
+		if inBase[id] {
+			common = append(common, id)
+		}
+	}
+
+	// 最長共通部分列を動的計画法で求める
+	lengths := make([][]int, len(base)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(common)+1)
+	}
+	for i := len(base) - 1; i >= 0; i-- {
+		for j := len(common) - 1; j >= 0; j-- {
+			if base[i] == common[j] {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else {
+				lengths[i][j] = max(lengths[i+1][j], lengths[i][j+1])
+			}
+		}
+	}
+	stable := make(map[int]bool)
+	for i, j := 0, 0; i < len(base) && j < len(common); {
+		switch {
+		case base[i] == common[j]:
+			stable[base[i]] = true
+			i++
+			j++
+		case lengths[i+1][j] >= lengths[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+
+	moved := make(map[int]bool)
+	for _, id := range side {
+		if !stable[id] {
+			moved[id] = true
+		}
+	}
+	return moved
+}
+
+// previousIDs - 各行の直前の行のIDを返す（先頭の行は0）
+func previousIDs(ids []int) map[int]int {
+	prev := make(map[int]int, len(ids))
+	for i, id := range ids {
+		if i > 0 {
+			prev[id] = ids[i-1]
+		}
+	}
+	return prev
+}
+
+// insertAfter - idを、sideでidより前にある行のうちorderに配置済みの最も近い行の直後に挿入する
+// skipに含まれる行は基準にせず、該当する行がなければ先頭に挿入する
+func insertAfter(order []int, id int, side []int, skip map[int]bool) []int {
+	position := make(map[int]int, len(order))
+	for i, placed := range order {
+		position[placed] = i
+	}
+
+	index := 0
+	for i := 0; i < len(side) && side[i] != id; i++ {
+		if p, ok := position[side[i]]; ok && !skip[side[i]] {
+			index = p + 1
+		}
+	}
+	order = append(order, 0)
+	copy(order[index+1:], order[index:])
+	order[index] = id
+	return order
+}
+
+// mergeColumns - 固定の列以外の列を、ours, theirs, baseの順に重複なく並べる
+func mergeColumns(columnLists ...[]string) []string {
+	seen := make(map[string]bool)
+	var columns []string
+	for _, list := range columnLists {
+		for _, column := range list {
+			if !seen[column] {
+				seen[column] = true
+				columns = append(columns, column)
+			}
+		}
+	}
+	return columns
+}
+
+// removeClosedItems - 作業ツリーのIssueファイルで完了扱いになっている行を除く
+// Issueファイルを読み込めない場合はそのまま返す
+func removeClosedItems(cfg *config.Config, items []models.OrderCSVItem) []models.OrderCSVItem {
+	statuses := cfg.StatusSet()
+	issues, err := fileops.ReadAllIssuesWithStatuses(cfg.IssuesDir, statuses)
+	if err != nil {
+		logger.Debug("Issueファイルを読み込めないため完了済みの行を除きません", "error", err)
+		return items
+	}
+
+	closed := make(map[int]bool)
+	for _, issue := range issues {
+		if statuses.IsDone(issue.Status) {
+			closed[issue.ID] = true
+		}
+	}
+
+	kept := make([]models.OrderCSVItem, 0, len(items))
+	for _, item := range items {
+		if closed[item.ID] {
+			logger.Info("完了済みのIssueをorder.csvから除きました", "id", item.ID)
+			continue
+		}
+		kept = append(kept, item)
+	}
+	return kept
+}
@@ -0,0 +1,92 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/moai/instant-backlog/internal/config"
+	"github.com/moai/instant-backlog/internal/fileops"
+	"github.com/moai/instant-backlog/internal/logger"
+	"github.com/moai/instant-backlog/internal/models"
+	"github.com/moai/instant-backlog/internal/parser"
+	"github.com/moai/instant-backlog/pkg/utils"
+)
+
+// MigrateOrderCommand - Issueの並び順の管理方法を移行する
+// rankに移行する場合は現在のorder.csvの並び順で未完了のIssueに等間隔のrankを設定し、
+// csvに移行する場合はrankの並び順でorder.csvを書き込んでからすべてのIssueのrankを削除する
+func MigrateOrderCommand(cfg *config.Config, to config.OrderingMode, w io.Writer) error {
+	statuses := cfg.StatusSet()
+	issues, err := fileops.ReadAllIssuesWithStatuses(cfg.IssuesDir, statuses)
+	if err != nil {
+		return fmt.Errorf("Issueの読み込みに失敗しました: %w", err)
+	}
+	orderItems, fileColumns, err := parser.ReadOrderCSVWithColumns(cfg.OrderCSV)
+	if err != nil {
+		return fmt.Errorf("order.csvの読み込みに失敗しました: %w", err)
+	}
+	issuesByID := make(map[int]*models.Issue, len(issues))
+	for _, issue := range issues {
+		issuesByID[issue.ID] = issue
+	}
+
+	var items []models.OrderCSVItem
+	var updated []*models.Issue
+	switch to {
+	case config.OrderingRank:
+		// order.csvの未完了のIssueの行を残し、order.csvにないIssueを設定した位置に追加する
+		listed := make(map[int]bool, len(orderItems))
+		for _, item := range orderItems {
+			if issue, ok := issuesByID[item.ID]; ok && !statuses.IsDone(issue.Status) && !listed[item.ID] {
+				items = append(items, orderRow(cfg, issue, item.Extra))
+				listed[item.ID] = true
+			}
+		}
+		var added []*models.Issue
+		for _, issue := range issues {
+			if !statuses.IsDone(issue.Status) && !listed[issue.ID] {
+				added = append(added, issue)
+			}
+		}
+		items = placeNewItems(cfg, items, added, issuesByID)
+
+		for i, rank := range utils.EvenRanks(len(items)) {
+			issue := issuesByID[items[i].ID]
+			if issue.Rank != rank {
+				issue.Rank = rank
+				updated = append(updated, issue)
+			}
+		}
+		refreshRankColumn(cfg, items, issuesByID)
+
+	case config.OrderingCSV:
+		items, _, _ = rankedOrder(cfg, issues, orderItems, false)
+		for _, issue := range issues {
+			if issue.Rank != "" {
+				issue.Rank = ""
+				updated = append(updated, issue)
+			}
+		}
+		refreshRankColumn(cfg, items, issuesByID)
+
+	default:
+		return fmt.Errorf("不明な並び順の管理方法です: %s", to)
+	}
+
+	// rankを削除する前にorder.csvを書き込み、途中で失敗しても並び順が失われないようにする
+	if err := parser.WriteOrderCSVWithColumns(cfg.OrderCSV, items, orderColumns(cfg, fileColumns)); err != nil {
+		return fmt.Errorf("order.csvの書き込みに失敗しました: %w", err)
+	}
+	for _, issue := range updated {
+		if err := fileops.UpdateIssueWithStatuses(cfg.IssuesDir, statuses, issue); err != nil {
+			return fmt.Errorf("rankの書き込みに失敗しました（ID=%d）: %w", issue.ID, err)
+		}
+	}
+	logger.Info("並び順の管理方法を移行しました", "to", to, "issues", len(updated))
+
+	fmt.Fprintf(w, "%d件のIssueのrankを更新しました\n", len(updated))
+	if cfg.OrderingMode() != to {
+		fmt.Fprintf(w, "設定ファイル(%s)に ordering: %s を設定してください\n", config.FileName, to)
+	}
+	return nil
+}
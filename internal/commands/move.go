@@ -0,0 +1,189 @@
+package commands
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/moai/instant-backlog/internal/config"
+	"github.com/moai/instant-backlog/internal/fileops"
+	"github.com/moai/instant-backlog/internal/logger"
+	"github.com/moai/instant-backlog/internal/models"
+	"github.com/moai/instant-backlog/internal/parser"
+)
+
+// MoveOptions - moveコマンドの移動先（いずれか1つを指定する）
+type MoveOptions struct {
+	Top      bool // 先頭に移動する
+	Bottom   bool // 末尾に移動する
+	Before   int  // 指定したIDのIssueの直前に移動する（0の場合は指定なし）
+	After    int  // 指定したIDのIssueの直後に移動する（0の場合は指定なし）
+	Position int  // 指定した順位（1始まり）に移動する（0の場合は指定なし）
+}
+
+// MoveCommand - order.csvでIssueの並び順を変更する
+// 複数のIDを指定した場合は、指定した順序のまま1つのまとまりとして移動する。
+// order.csvにない未完了のIssueは移動先に追加する。
+// rankで並び順を管理する場合は、移動したIssueのrankだけを変更してorder.csvを生成し直す
+func MoveCommand(cfg *config.Config, ids []int, opts MoveOptions) error {
+	if err := validateMoveOptions(opts); err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return fmt.Errorf("移動するIssueのIDを指定してください")
+	}
+
+	statuses := cfg.StatusSet()
+	issues, dependencyProblems, err := fileops.ReadAllIssuesWithProblems(cfg.IssuesDir, statuses)
+	if err != nil {
+		return fmt.Errorf("Issueの読み込みに失敗しました: %w", err)
+	}
+	fileops.WarnDependencyProblems(dependencyProblems)
+	issuesByID := make(map[int]*models.Issue, len(issues))
+	for _, issue := range issues {
+		issuesByID[issue.ID] = issue
+	}
+
+	// 移動するIssueが未完了のIssueであることを確認
+	moving := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		issue, ok := issuesByID[id]
+		switch {
+		case moving[id]:
+			return fmt.Errorf("IDが重複しています: %d", id)
+		case !ok:
+			return fmt.Errorf("Issueが見つかりません: %d", id)
+		case statuses.IsDone(issue.Status):
+			return fmt.Errorf("完了済み（%s）のIssueは移動できません: %d", issue.Status, id)
+		}
+		moving[id] = true
+	}
+
+	orderItems, fileColumns, err := parser.ReadOrderCSVWithColumns(cfg.OrderCSV)
+	if err != nil {
+		return fmt.Errorf("order.csvの読み込みに失敗しました: %w", err)
+	}
+
+	rankMode := cfg.OrderingMode() == config.OrderingRank
+	original := make(map[int]string, len(issues))
+	if rankMode {
+		// 現在の並び順をrankから求める
+		for _, issue := range issues {
+			original[issue.ID] = issue.Rank
+		}
+		orderItems, _, _ = rankedOrder(cfg, issues, orderItems, false)
+	}
+
+	// 移動するIssueの行を取り出す（order.csvにないものは新しい行を作成する）
+	rows := make(map[int]models.OrderCSVItem, len(ids))
+	var rest []models.OrderCSVItem
+	for _, item := range orderItems {
+		if !moving[item.ID] {
+			rest = append(rest, item)
+		} else if _, ok := rows[item.ID]; !ok {
+			rows[item.ID] = item
+		}
+	}
+	block := make([]models.OrderCSVItem, 0, len(ids))
+	for _, id := range ids {
+		row, ok := rows[id]
+		if !ok {
+			row = orderRow(cfg, issuesByID[id], nil)
+		}
+		block = append(block, row)
+	}
+
+	position, err := movePosition(rest, opts)
+	if err != nil {
+		return err
+	}
+	items := slices.Insert(rest, position, block...)
+
+	// 依存関係に反する並び順になった場合は警告する
+	checkDependencyOrder(issues, items, statuses, false)
+
+	if rankMode {
+		// 移動したIssueに移動先の前後の間のrankを割り当てる
+		for _, id := range ids {
+			issuesByID[id].Rank = ""
+		}
+		assignRanks(items, issuesByID)
+		refreshRankColumn(cfg, items, issuesByID)
+		for _, issue := range issues {
+			if issue.Rank == original[issue.ID] {
+				continue
+			}
+			if err := fileops.UpdateIssueWithStatuses(cfg.IssuesDir, statuses, issue); err != nil {
+				return fmt.Errorf("rankの書き込みに失敗しました（ID=%d）: %w", issue.ID, err)
+			}
+		}
+	}
+
+	if err := parser.WriteOrderCSVWithColumns(cfg.OrderCSV, items, orderColumns(cfg, fileColumns)); err != nil {
+		return fmt.Errorf("order.csvの書き込みに失敗しました: %w", err)
+	}
+
+	logger.Info("order.csvの並び順を変更しました", "ids", ids, "position", position+1)
+	return nil
+}
+
+// ParseIDs - 引数のIssue IDを解析する（1つの引数にカンマ区切りで複数指定できる）
+func ParseIDs(args []string) ([]int, error) {
+	var ids []int
+	for _, arg := range args {
+		for _, value := range strings.Split(arg, ",") {
+			if value = strings.TrimSpace(value); value == "" {
+				continue
+			}
+			id, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("IDは整数で指定してください: %s", value)
+			}
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// validateMoveOptions - 移動先がちょうど1つ指定されていることを確認する
+func validateMoveOptions(opts MoveOptions) error {
+	count := 0
+	for _, specified := range []bool{opts.Top, opts.Bottom, opts.Before != 0, opts.After != 0, opts.Position != 0} {
+		if specified {
+			count++
+		}
+	}
+	if count != 1 {
+		return fmt.Errorf("移動先を --top, --bottom, --before, --after, --position のいずれか1つで指定してください")
+	}
+	return nil
+}
+
+// movePosition - 移動するIssueを除いた行のうち、移動先となる位置（0始まり）を返す
+func movePosition(rest []models.OrderCSVItem, opts MoveOptions) (int, error) {
+	indexOf := func(id int) (int, error) {
+		for i, item := range rest {
+			if item.ID == id {
+				return i, nil
+			}
+		}
+		return 0, fmt.Errorf("移動先の基準となるIssueがorder.csvにありません（移動するIssueは指定できません）: %d", id)
+	}
+
+	switch {
+	case opts.Top:
+		return 0, nil
+	case opts.Before != 0:
+		return indexOf(opts.Before)
+	case opts.After != 0:
+		i, err := indexOf(opts.After)
+		return i + 1, err
+	case opts.Position != 0:
+		if opts.Position < 1 || opts.Position > len(rest)+1 {
+			return 0, fmt.Errorf("順位は1から%dの範囲で指定してください: %d", len(rest)+1, opts.Position)
+		}
+		return opts.Position - 1, nil
+	}
+	return len(rest), nil
+}
@@ -0,0 +1,198 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/moai/instant-backlog/internal/config"
+	"github.com/moai/instant-backlog/internal/fileops"
+	"github.com/moai/instant-backlog/internal/logger"
+	"github.com/moai/instant-backlog/internal/models"
+	"github.com/moai/instant-backlog/internal/parser"
+	"github.com/moai/instant-backlog/pkg/utils"
+)
+
+// 新規作成時の本文テンプレート
+const (
+	newIssueContent = "## 詳細\n\n## 受け入れ基準\n- [ ] "
+	newEpicContent  = "## 概要\n\n## 目的\n- "
+)
+
+// NewIssueOptions - new issueコマンドのオプション
+type NewIssueOptions struct {
+	Title    string
+	Epic     int
+	Estimate int
+	Status   string // 未指定の場合は最初の未完了ステータス
+	Edit     bool   // 作成後に$EDITORで開くかどうか
+}
+
+// NewEpicOptions - new epicコマンドのオプション
+type NewEpicOptions struct {
+	Title  string
+	Status string // 未指定の場合は最初の未完了ステータス
+	Edit   bool   // 作成後に$EDITORで開くかどうか
+}
+
+// NewIssueCommand - 次の空きIDで新しいIssueを作成する
+func NewIssueCommand(cfg *config.Config, opts NewIssueOptions) error {
+	statuses := cfg.StatusSet()
+
+	id, err := nextID(cfg.IssuesDir)
+	if err != nil {
+		return fmt.Errorf("IssueのIDの採番に失敗しました: %w", err)
+	}
+
+	status := opts.Status
+	if status == "" {
+		status = statuses.InitialStatus().Name
+	}
+
+	issue := &models.Issue{
+		ID:       id,
+		Title:    opts.Title,
+		Status:   status,
+		Epic:     opts.Epic,
+		Estimate: opts.Estimate,
+		Content:  newIssueContent,
+	}
+	if err := utils.ValidateIssueWithStatuses(statuses, issue); err != nil {
+		return fmt.Errorf("Issueの内容が不正です: %w", err)
+	}
+
+	// 存在しないEpicを指定した場合は警告のみ
+	if !epicExists(cfg.EpicDir, opts.Epic) {
+		logger.Warn("指定したEpicが見つかりません", "epic", opts.Epic)
+	}
+
+	if err := fileops.WriteIssueWithStatuses(cfg.IssuesDir, statuses, issue); err != nil {
+		return fmt.Errorf("Issueの書き込みに失敗しました: %w", err)
+	}
+
+	filePath := filepath.Join(cfg.IssuesDir, utils.GenerateFilenameWithStatuses(statuses, issue.ID, issue.Status, issue.Title))
+	logger.Info("Issueを作成しました", "id", issue.ID, "file", filePath)
+
+	if opts.Edit {
+		if err := openEditor(filePath); err != nil {
+			return err
+		}
+	}
+
+	// order.csvへの追加とファイル名の更新
+	return SyncCommand(cfg)
+}
+
+// NewEpicCommand - 次の空きIDで新しいEpicを作成する
+func NewEpicCommand(cfg *config.Config, opts NewEpicOptions) error {
+	statuses := cfg.StatusSet()
+
+	id, err := nextID(cfg.EpicDir)
+	if err != nil {
+		return fmt.Errorf("EpicのIDの採番に失敗しました: %w", err)
+	}
+
+	status := opts.Status
+	if status == "" {
+		status = statuses.InitialStatus().Name
+	}
+
+	epic := &models.Epic{
+		ID:      id,
+		Title:   opts.Title,
+		Status:  status,
+		Content: newEpicContent,
+	}
+	if err := utils.ValidateEpicWithStatuses(statuses, epic); err != nil {
+		return fmt.Errorf("Epicの内容が不正です: %w", err)
+	}
+
+	if err := fileops.WriteEpicWithStatuses(cfg.EpicDir, statuses, epic); err != nil {
+		return fmt.Errorf("Epicの書き込みに失敗しました: %w", err)
+	}
+
+	filePath := filepath.Join(cfg.EpicDir, utils.GenerateFilenameWithStatuses(statuses, epic.ID, epic.Status, epic.Title))
+	logger.Info("Epicを作成しました", "id", epic.ID, "file", filePath)
+
+	if opts.Edit {
+		if err := openEditor(filePath); err != nil {
+			return err
+		}
+	}
+
+	// 編集でステータスやタイトルが変わった場合に備えて同期する
+	return SyncCommand(cfg)
+}
+
+// nextID - ディレクトリ内のOpen/Closeを問わずすべてのファイルから次の空きIDを求める
+// Front Matterを解析できないファイルはファイル名の先頭のIDを使用する
+func nextID(directory string) (int, error) {
+	files, err := os.ReadDir(directory)
+	if err != nil {
+		return 0, err
+	}
+
+	maxID := 0
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != ".md" {
+			continue
+		}
+
+		// IDのみが必要なため、Issue/Epicを問わずEpicとして解析する
+		id := 0
+		if parsed, err := parser.ParseEpicFile(filepath.Join(directory, file.Name())); err == nil {
+			id = parsed.ID
+		}
+
+		// ファイル名のIDも考慮する（Front Matterとファイル名が食い違っている場合の衝突を避ける）
+		var filenameID int
+		if _, err := fmt.Sscanf(file.Name(), "%d_", &filenameID); err == nil && filenameID > id {
+			id = filenameID
+		}
+
+		if id > maxID {
+			maxID = id
+		}
+	}
+
+	return maxID + 1, nil
+}
+
+// epicExists - 指定したIDのEpicが存在するかどうかを返す
+func epicExists(epicDir string, id int) bool {
+	epics, err := fileops.ReadAllEpics(epicDir)
+	if err != nil {
+		return false
+	}
+	for _, epic := range epics {
+		if epic.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// openEditor - $VISUALまたは$EDITORでファイルを開く
+func openEditor(filePath string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		return fmt.Errorf("エディタが設定されていません。環境変数 EDITOR を設定してください")
+	}
+
+	// "code -w" のように引数を含む指定にも対応する
+	args := strings.Fields(editor)
+	cmd := exec.Command(args[0], append(args[1:], filePath)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("エディタの実行に失敗しました: %w", err)
+	}
+	return nil
+}
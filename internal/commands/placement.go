@@ -0,0 +1,113 @@
+package commands
+
+import (
+	"slices"
+	"sort"
+	"strconv"
+
+	"github.com/moai/instant-backlog/internal/config"
+	"github.com/moai/instant-backlog/internal/logger"
+	"github.com/moai/instant-backlog/internal/models"
+	"github.com/moai/instant-backlog/internal/parser"
+)
+
+// placeNewItems - order.csvにない未完了のIssueの行を、設定した位置(placement)に追加する
+// 新しいIssueはID順に追加するため、同じ位置に追加されるIssueはID順に並ぶ
+func placeNewItems(cfg *config.Config, items []models.OrderCSVItem, added []*models.Issue, issuesByID map[int]*models.Issue) []models.OrderCSVItem {
+	if len(added) == 0 {
+		return items
+	}
+
+	added = slices.Clone(added)
+	sort.Slice(added, func(i, j int) bool { return added[i].ID < added[j].ID })
+
+	switch cfg.PlacementPolicy() {
+	case config.PlacementTop:
+		rows := make([]models.OrderCSVItem, 0, len(items)+len(added))
+		for _, issue := range added {
+			rows = append(rows, orderRow(cfg, issue, nil))
+		}
+		return append(rows, items...)
+
+	case config.PlacementEpic:
+		for _, issue := range added {
+			// 同じEpicの最後の行の直後（同じEpicがなければ末尾）
+			position := len(items)
+			for i := len(items) - 1; i >= 0; i-- {
+				if items[i].Epic == issue.Epic {
+					position = i + 1
+					break
+				}
+			}
+			items = slices.Insert(items, position, orderRow(cfg, issue, nil))
+		}
+		return items
+
+	case config.PlacementPriority:
+		// 優先度はorder.csvに含まれる行と追加するIssueについて求める
+		issues := slices.Clone(added)
+		for _, item := range items {
+			if issue, ok := issuesByID[item.ID]; ok {
+				issues = append(issues, issue)
+			}
+		}
+		priorities := issuePriorities(issues)
+		sort.SliceStable(added, func(i, j int) bool {
+			pi, oki := priorities[added[i].ID]
+			pj, okj := priorities[added[j].ID]
+			return oki && (!okj || pi < pj)
+		})
+		for _, issue := range added {
+			// 優先度が同じか高い（priorityが同じか小さい）最後の行の直後
+			// そのような行がなければpriorityが設定された最初の行の直前（priorityがなければ末尾）
+			position := len(items)
+			if p, ok := priorities[issue.ID]; ok {
+				last, first := -1, -1
+				for i, item := range items {
+					q, ok := priorities[item.ID]
+					if !ok {
+						continue
+					}
+					if first < 0 {
+						first = i
+					}
+					if q <= p {
+						last = i
+					}
+				}
+				switch {
+				case last >= 0:
+					position = last + 1
+				case first >= 0:
+					position = first
+				}
+			}
+			items = slices.Insert(items, position, orderRow(cfg, issue, nil))
+		}
+		return items
+	}
+
+	for _, issue := range added {
+		items = append(items, orderRow(cfg, issue, nil))
+	}
+	return items
+}
+
+// issuePriorities - Front Matterのpriorityが設定されているIssueの優先度を返す
+// 整数でない値は警告して未設定として扱う
+func issuePriorities(issues []*models.Issue) map[int]int {
+	priorities := make(map[int]int, len(issues))
+	for _, issue := range issues {
+		value := parser.FieldValue(issue.FrontMatter, "priority")
+		if value == "" {
+			continue
+		}
+		priority, err := strconv.Atoi(value)
+		if err != nil {
+			logger.Warn("priorityが整数ではないため無視します", "id", issue.ID, "priority", value)
+			continue
+		}
+		priorities[issue.ID] = priority
+	}
+	return priorities
+}
@@ -0,0 +1,165 @@
+package commands
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/moai/instant-backlog/internal/config"
+	"github.com/moai/instant-backlog/internal/logger"
+	"github.com/moai/instant-backlog/internal/models"
+	"github.com/moai/instant-backlog/pkg/utils"
+)
+
+// RankChange - IssueのFront Matterのrankの変更
+type RankChange struct {
+	Issue *models.Issue // 変更後の内容
+	From  string        // 変更前のrank（未設定の場合は空）
+}
+
+// rankedOrder - rankで並び順を管理する場合の、未完了のIssueの並び順を求める
+// rankが設定されているIssueを(rank, ID)の順に並べ、rankがない（または不正な）Issueは設定した位置(placement)に追加する。
+// 追加したIssueと、rankの順序が前後と矛盾するIssueには新しいrankを割り当てる
+func rankedOrder(cfg *config.Config, issues []*models.Issue, orderItems []models.OrderCSVItem, sortByDependencies bool) ([]models.OrderCSVItem, []RankChange, int) {
+	statuses := cfg.StatusSet()
+
+	// 既存の行の追加の列を引き継ぐ
+	extras := make(map[int]map[string]string, len(orderItems))
+	for _, item := range orderItems {
+		if _, ok := extras[item.ID]; !ok {
+			extras[item.ID] = item.Extra
+		}
+	}
+
+	issuesByID := make(map[int]*models.Issue, len(issues))
+	var ranked, added []*models.Issue
+	for _, issue := range issues {
+		issuesByID[issue.ID] = issue
+		switch {
+		case statuses.IsDone(issue.Status):
+		case issue.Rank == "":
+			added = append(added, issue)
+		case !utils.IsValidRank(issue.Rank):
+			logger.Warn("rankが不正なため、rankのないIssueとして並べ直します", "id", issue.ID, "rank", issue.Rank)
+			added = append(added, issue)
+		default:
+			ranked = append(ranked, issue)
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].Rank != ranked[j].Rank {
+			return ranked[i].Rank < ranked[j].Rank
+		}
+		return ranked[i].ID < ranked[j].ID
+	})
+
+	items := make([]models.OrderCSVItem, 0, len(ranked)+len(added))
+	for _, issue := range ranked {
+		items = append(items, orderRow(cfg, issue, extras[issue.ID]))
+	}
+	items = placeNewItems(cfg, items, added, issuesByID)
+	for i, item := range items {
+		if extra, ok := extras[item.ID]; ok && item.Extra == nil {
+			items[i] = orderRow(cfg, issuesByID[item.ID], extra)
+		}
+	}
+
+	items, reordered := checkDependencyOrder(issues, items, statuses, sortByDependencies)
+
+	changes := assignRanks(items, issuesByID)
+	refreshRankColumn(cfg, items, issuesByID)
+	return items, changes, reordered
+}
+
+// refreshRankColumn - order.csvにrankの列を出力する場合に、割り当てた後のrankを設定する
+func refreshRankColumn(cfg *config.Config, items []models.OrderCSVItem, issuesByID map[int]*models.Issue) {
+	if !slices.Contains(cfg.OrderColumns, "rank") {
+		return
+	}
+	for i, item := range items {
+		items[i] = orderRow(cfg, issuesByID[item.ID], item.Extra)
+	}
+}
+
+// assignRanks - 並び順に合うようにIssueのrankを割り当てる
+// 並び順とrankの順序が一致する最長の範囲のrankは維持し、それ以外のIssueにだけ前後の間のrankを割り当てる。
+// 不正なrankは前後のrankとして使用せず、rankがないものとして割り当て直す
+func assignRanks(items []models.OrderCSVItem, issuesByID map[int]*models.Issue) []RankChange {
+	ranks := make([]string, len(items))
+	for i, item := range items {
+		if rank := issuesByID[item.ID].Rank; utils.IsValidRank(rank) {
+			ranks[i] = rank
+		}
+	}
+	keep := increasingRanks(ranks)
+
+	var changes []RankChange
+	for i, item := range items {
+		if keep[i] {
+			continue
+		}
+		prev := ""
+		if i > 0 {
+			prev = ranks[i-1]
+		}
+		next := ""
+		for j := i + 1; j < len(items); j++ {
+			if keep[j] {
+				next = ranks[j]
+				break
+			}
+		}
+		ranks[i] = utils.RankBetween(prev, next)
+
+		issue := issuesByID[item.ID]
+		changes = append(changes, RankChange{Issue: issue, From: issue.Rank})
+		issue.Rank = ranks[i]
+	}
+	return changes
+}
+
+// increasingRanks - 空でないrankのうち、並び順で狭義単調増加になる最長の部分列に含まれるかどうかを返す
+func increasingRanks(ranks []string) []bool {
+	length := make([]int, len(ranks))
+	prev := make([]int, len(ranks))
+	best := -1
+	for i, rank := range ranks {
+		prev[i] = -1
+		if rank == "" {
+			continue
+		}
+		length[i] = 1
+		for j := 0; j < i; j++ {
+			if ranks[j] != "" && ranks[j] < rank && length[j]+1 > length[i] {
+				length[i] = length[j] + 1
+				prev[i] = j
+			}
+		}
+		if best < 0 || length[i] > length[best] {
+			best = i
+		}
+	}
+
+	keep := make([]bool, len(ranks))
+	for i := best; i >= 0; i = prev[i] {
+		keep[i] = true
+	}
+	return keep
+}
+
+// rankedRowChanges - 生成したorder.csvで内容が変わる既存の行を返す
+func rankedRowChanges(before, after []models.OrderCSVItem) []OrderRowChange {
+	rows := make(map[int]models.OrderCSVItem, len(before))
+	for _, item := range before {
+		if _, ok := rows[item.ID]; !ok {
+			rows[item.ID] = item
+		}
+	}
+
+	var changes []OrderRowChange
+	for _, item := range after {
+		if row, ok := rows[item.ID]; ok && !row.Equal(item) {
+			changes = append(changes, OrderRowChange{Before: row, After: item})
+		}
+	}
+	return changes
+}
@@ -1,89 +1,106 @@
 package commands
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 
 	"github.com/moai/instant-backlog/internal/config"
+	"github.com/moai/instant-backlog/internal/fileops"
+	"github.com/moai/instant-backlog/internal/logger"
+	"github.com/moai/instant-backlog/internal/models"
 	"github.com/moai/instant-backlog/internal/parser"
 	"github.com/moai/instant-backlog/pkg/utils"
 )
 
+// RenamePlan - ファイル名の変更予定
+type RenamePlan struct {
+	ID       int
+	Epic     bool   // Epicのファイルかどうか
+	From     string // 現在のファイルのパス
+	To       string // 変更後のファイルのパス
+	Conflict bool   // 変更後のファイルが既に存在するかどうか
+}
+
 // RenameCommand - Front Matterの内容に基づいてファイル名を更新
+// リネーム先のファイルが既に存在する場合は設定の方針(on_conflict)に従って処理し、
+// 解決できなかった衝突があればエラーを返す
 func RenameCommand(cfg *config.Config) error {
-	fmt.Println("===== ファイル名の更新を開始します... ====")
+	logger.Debug("ファイル名の更新を開始します")
 
-	// Epicファイルの更新
-	if err := renameEpicFiles(cfg.EpicDir); err != nil {
-		return fmt.Errorf("Epicファイルの更新に失敗しました: %w", err)
+	plans, err := PlanRename(cfg)
+	if err != nil {
+		return err
 	}
-
-	// Issueファイルの更新
-	if err := renameIssueFiles(cfg.IssuesDir); err != nil {
-		return fmt.Errorf("Issueファイルの更新に失敗しました: %w", err)
+	if err := ExecuteRenamePlan(cfg, plans); err != nil {
+		return err
 	}
 
-	fmt.Println("===== ファイル名の更新が完了しました ====")
+	logger.Debug("ファイル名の更新が完了しました")
 	return nil
 }
 
-// renameEpicFiles - Epicディレクトリ内のファイル名を更新
-func renameEpicFiles(directory string) error {
-	files, err := os.ReadDir(directory)
+// RenameDryRunCommand - ファイル名の変更予定を出力する（ファイルは変更しない）
+func RenameDryRunCommand(cfg *config.Config, w io.Writer) error {
+	plans, err := PlanRename(cfg)
 	if err != nil {
 		return err
 	}
 
-	// ディレクトリ内のファイル数をチェック
-	if len(files) == 0 {
-		return nil
+	if len(plans) == 0 {
+		_, err := fmt.Fprintln(w, "変更はありません")
+		return err
 	}
+	fmt.Fprintln(w, "dry-run: 以下の変更は適用されていません")
+	return writeRenamePlans(w, cfg, plans)
+}
 
-	for _, file := range files {
-		if file.IsDir() || filepath.Ext(file.Name()) != ".md" {
-			continue
-		}
+// PlanRename - Front Matterの内容と一致しないファイル名の変更予定を作成する
+func PlanRename(cfg *config.Config) ([]RenamePlan, error) {
+	statuses := cfg.StatusSet()
 
-		filePath := filepath.Join(directory, file.Name())
+	epicPlans, err := planRenames(cfg.EpicDir, statuses, true)
+	if err != nil {
+		return nil, fmt.Errorf("Epicファイルの読み込みに失敗しました: %w", err)
+	}
 
-		epic, err := parser.ParseEpicFile(filePath)
-		if err != nil {
-			fmt.Printf("警告: ファイルの解析に失敗しました %s: %v\n", file.Name(), err)
-			continue
-		}
+	issuePlans, err := planRenames(cfg.IssuesDir, statuses, false)
+	if err != nil {
+		return nil, fmt.Errorf("Issueファイルの読み込みに失敗しました: %w", err)
+	}
 
-		// 正しいファイル名を生成
-		correctFilename := utils.GenerateFilename(epic.ID, epic.Status, epic.Title)
+	return append(epicPlans, issuePlans...), nil
+}
 
-		// 現在のファイル名と違う場合は名前変更
-		if file.Name() != correctFilename {
-			newPath := filepath.Join(directory, correctFilename)
-			fmt.Printf("リネーム: %s → %s\n", file.Name(), correctFilename)
-
-			// 一時ファイルが既に存在する場合は削除
-			if _, err := os.Stat(newPath); err == nil {
-				fmt.Printf("警告: 対象ファイルが既に存在します。置き換えます: %s\n", newPath)
-				os.Remove(newPath)
-			}
+// ExecuteRenamePlan - ファイル名の変更予定を実行する
+// 解決できなかった衝突があれば、すべての変更を試みた後にエラーを返す
+func ExecuteRenamePlan(cfg *config.Config, plans []RenamePlan) error {
+	policy, conflictsDir := cfg.ConflictPolicy(), cfg.ConflictsDir()
 
-			err = os.Rename(filePath, newPath)
-			if err != nil {
-				fmt.Printf("警告: ファイルのリネームに失敗しました %s: %v\n", file.Name(), err)
-			}
+	var collisions []error
+	for _, plan := range plans {
+		if err := renameFile(plan.From, plan.To, policy, conflictsDir); err != nil {
+			collisions = append(collisions, err)
 		}
 	}
 
+	if len(collisions) > 0 {
+		return fmt.Errorf("%d件のファイル名の衝突を解決できませんでした（--on-conflict=conflicts または merge で処理できます）:\n%w",
+			len(collisions), errors.Join(collisions...))
+	}
 	return nil
 }
 
-// renameIssueFiles - Issueディレクトリ内のファイル名を更新
-func renameIssueFiles(directory string) error {
+// planRenames - ディレクトリ内のEpicまたはIssueのファイル名の変更予定を作成する
+func planRenames(directory string, statuses models.StatusSet, epic bool) ([]RenamePlan, error) {
 	files, err := os.ReadDir(directory)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
+	var plans []RenamePlan
 	for _, file := range files {
 		if file.IsDir() || filepath.Ext(file.Name()) != ".md" {
 			continue
@@ -91,32 +108,77 @@ func renameIssueFiles(directory string) error {
 
 		filePath := filepath.Join(directory, file.Name())
 
-		issue, err := parser.ParseIssueFile(filePath)
-		if err != nil {
-			fmt.Printf("警告: ファイルの解析に失敗しました %s: %v\n", file.Name(), err)
-			continue
-		}
-
-		// 正しいファイル名を生成
-		correctFilename := utils.GenerateFilename(issue.ID, issue.Status, issue.Title)
-
-		// 現在のファイル名と違う場合は名前変更
-		if file.Name() != correctFilename {
-			newPath := filepath.Join(directory, correctFilename)
-			fmt.Printf("リネーム: %s → %s\n", file.Name(), correctFilename)
-
-			// 一時ファイルが既に存在する場合は削除
-			if _, err := os.Stat(newPath); err == nil {
-				fmt.Printf("警告: 対象ファイルが既に存在します。置き換えます: %s\n", newPath)
-				os.Remove(newPath)
+		var id int
+		var status, title string
+		if epic {
+			e, err := parser.ParseEpicFile(filePath)
+			if err != nil {
+				logger.Warn("ファイルの解析に失敗しました", "file", file.Name(), "error", err)
+				continue
 			}
-
-			err = os.Rename(filePath, newPath)
+			id, status, title = e.ID, e.Status, e.Title
+		} else {
+			issue, err := parser.ParseIssueFile(filePath)
 			if err != nil {
-				fmt.Printf("警告: ファイルのリネームに失敗しました %s: %v\n", file.Name(), err)
+				logger.Warn("ファイルの解析に失敗しました", "file", file.Name(), "error", err)
+				continue
 			}
+			id, status, title = issue.ID, issue.Status, issue.Title
 		}
+
+		// 現在のファイル名と違う場合は名前変更
+		if plan, ok := planFileRename(filePath, statuses, epic, id, status, title); ok {
+			plans = append(plans, plan)
+		}
+	}
+
+	return plans, nil
+}
+
+// planFileRename - 1つのファイルのファイル名がFront Matterの内容と一致しない場合に変更予定を作成する
+func planFileRename(filePath string, statuses models.StatusSet, epic bool, id int, status, title string) (RenamePlan, bool) {
+	// 正しいファイル名を生成
+	correctFilename := utils.GenerateFilenameWithStatuses(statuses, id, status, title)
+	if filepath.Base(filePath) == correctFilename {
+		return RenamePlan{}, false
 	}
 
+	newPath := filepath.Join(filepath.Dir(filePath), correctFilename)
+	_, statErr := os.Stat(newPath)
+	return RenamePlan{ID: id, Epic: epic, From: filePath, To: newPath, Conflict: statErr == nil}, true
+}
+
+// renameFile - 1つのファイルをリネームする
+// 解決できなかった衝突のみエラーとして返し、その他の失敗は警告して続行する
+func renameFile(filePath, newPath string, policy config.ConflictPolicy, conflictsDir string) error {
+	err := fileops.RenameWithPolicy(filePath, newPath, policy, conflictsDir)
+
+	var collision *fileops.CollisionError
+	switch {
+	case errors.As(err, &collision):
+		logger.Warn("リネーム先のファイルが既に存在するためスキップしました", "file", filepath.Base(filePath), "target", filepath.Base(newPath))
+		return err
+	case err != nil:
+		logger.Warn("ファイルのリネームに失敗しました", "file", filepath.Base(filePath), "error", err)
+	}
+	return nil
+}
+
+// writeRenamePlans - ファイル名の変更予定を出力する
+func writeRenamePlans(w io.Writer, cfg *config.Config, plans []RenamePlan) error {
+	if len(plans) == 0 {
+		return nil
+	}
+
+	fmt.Fprintln(w, "ファイル名の変更:")
+	for _, plan := range plans {
+		note := ""
+		if plan.Conflict {
+			note = fmt.Sprintf("（変更先が既に存在します。on_conflict=%s に従って処理します）", cfg.ConflictPolicy())
+		}
+		if _, err := fmt.Fprintf(w, "  %s → %s%s\n", relativePath(cfg.ProjectsDir, plan.From), filepath.Base(plan.To), note); err != nil {
+			return err
+		}
+	}
 	return nil
 }
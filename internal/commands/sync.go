@@ -2,50 +2,195 @@ package commands
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/moai/instant-backlog/internal/config"
 	"github.com/moai/instant-backlog/internal/fileops"
+	"github.com/moai/instant-backlog/internal/logger"
 	"github.com/moai/instant-backlog/internal/models"
 	"github.com/moai/instant-backlog/internal/parser"
 	"github.com/moai/instant-backlog/pkg/utils"
 )
 
+// EpicStatusChange - Epicのステータスの変更予定
+type EpicStatusChange struct {
+	Epic *models.Epic // 変更後の内容
+	From string       // 変更前のステータス
+}
+
+// OrderRowChange - Issueファイルの内容で更新するorder.csvの行
+type OrderRowChange struct {
+	Before models.OrderCSVItem
+	After  models.OrderCSVItem
+}
+
+// Fields - 変更された列を「列: 変更前 → 変更後」の形式で返す
+func (c OrderRowChange) Fields() []string {
+	var fields []string
+	if c.Before.Title != c.After.Title {
+		fields = append(fields, fmt.Sprintf("title: %s → %s", c.Before.Title, c.After.Title))
+	}
+	if c.Before.Epic != c.After.Epic {
+		fields = append(fields, fmt.Sprintf("epic: %d → %d", c.Before.Epic, c.After.Epic))
+	}
+	if c.Before.Estimate != c.After.Estimate {
+		fields = append(fields, fmt.Sprintf("estimate: %d → %d", c.Before.Estimate, c.After.Estimate))
+	}
+
+	// 固定の列以外は列名順に並べる
+	var columns []string
+	for column := range c.Before.Extra {
+		columns = append(columns, column)
+	}
+	for column := range c.After.Extra {
+		if _, ok := c.Before.Extra[column]; !ok {
+			columns = append(columns, column)
+		}
+	}
+	sort.Strings(columns)
+	for _, column := range columns {
+		if before, after := c.Before.Extra[column], c.After.Extra[column]; before != after {
+			fields = append(fields, fmt.Sprintf("%s: %s → %s", column, before, after))
+		}
+	}
+	return fields
+}
+
+// orderRow - Issueの内容からorder.csvの行を作成する
+// extraにある固定の列以外の値を引き継ぎ、設定した列(order_columns)の値をFront Matterから取得する
+func orderRow(cfg *config.Config, issue *models.Issue, extra map[string]string) models.OrderCSVItem {
+	item := models.OrderCSVItem{ID: issue.ID, Title: issue.Title, Epic: issue.Epic, Estimate: issue.Estimate}
+	item.Extra = withOrderColumns(cfg, issue, extra)
+	return item
+}
+
+// withOrderColumns - 固定の列以外の値に、設定した列(order_columns)の値をFront Matterから取得して設定する
+func withOrderColumns(cfg *config.Config, issue *models.Issue, extra map[string]string) map[string]string {
+	if len(extra) == 0 && len(cfg.OrderColumns) == 0 {
+		return nil
+	}
+
+	values := make(map[string]string, len(extra)+len(cfg.OrderColumns))
+	for column, value := range extra {
+		values[column] = value
+	}
+	for _, column := range cfg.OrderColumns {
+		values[column] = orderColumnValue(issue, column)
+	}
+	return values
+}
+
+// orderColumnValue - order.csvに追加する列の値をIssueから取得する
+func orderColumnValue(issue *models.Issue, column string) string {
+	switch column {
+	case "status":
+		return issue.Status
+	case "rank":
+		return issue.Rank
+	case "blocked_by":
+		ids := make([]string, 0, len(issue.BlockedBy))
+		for _, id := range issue.BlockedBy {
+			ids = append(ids, strconv.Itoa(id))
+		}
+		return strings.Join(ids, ", ")
+	}
+	return parser.FieldValue(issue.FrontMatter, column)
+}
+
+// orderColumns - order.csvに書き込む固定の列以外の列を返す
+// 設定した列(order_columns)に続けて、order.csvにあるそれ以外の列をファイルでの順序で並べる
+func orderColumns(cfg *config.Config, fileColumns []string) []string {
+	columns := append([]string{}, cfg.OrderColumns...)
+	configured := make(map[string]bool, len(cfg.OrderColumns))
+	for _, column := range cfg.OrderColumns {
+		configured[column] = true
+	}
+	for _, column := range fileColumns {
+		if !configured[column] {
+			columns = append(columns, column)
+		}
+	}
+	return columns
+}
+
+// OrderConflict - order.csvとIssueファイルの両方で変更された行
+type OrderConflict struct {
+	ID     int
+	Fields []string // 衝突した列を「列: order.csv=値, ファイル=値」の形式で表したもの
+}
+
+// IssueWriteBack - order.csvで変更された値をIssueファイルに書き戻す予定
+type IssueWriteBack struct {
+	Issue  *models.Issue  // 書き戻した後の内容
+	Change OrderRowChange // Beforeが書き戻す前のIssueファイルの値、Afterがorder.csvの値
+}
+
+// SyncPlan - syncで行う変更の計画
+type SyncPlan struct {
+	OrderBefore []models.OrderCSVItem // 現在のorder.csv
+	OrderAfter  []models.OrderCSVItem // 同期後のorder.csv
+	Columns     []string              // 同期後のorder.csvの固定の列以外の列
+	Refreshed   []OrderRowChange      // Issueファイルの内容で更新する行
+	WriteBacks  []IssueWriteBack      // order.csvの内容でIssueファイルを更新する行
+	Conflicts   []OrderConflict       // 両方で変更されたため同期しない行
+	NextBase    []models.OrderCSVItem // 同期後に保存する前回の同期内容
+	RankChanges []RankChange          // rankで並び順を管理する場合のrankの変更
+	Reordered   int                   // 依存関係に基づく並び替えで解消する違反の数
+	EpicChanges []EpicStatusChange    // Epicのステータスの変更
+	Renames     []RenamePlan          // ファイル名の変更（ステータスを変更するEpicのものは含まない）
+	Created     []*models.Issue       // order.csvに追加する未完了のIssue
+	Closed      []*models.Issue       // 完了扱いになりorder.csvから削除するIssue
+	Updated     []*models.Issue       // order.csvの行をIssueファイルの内容で更新した、またはorder.csvの値を書き戻したIssue
+	Known       []int                 // 同期した時点で存在するIssueのID（次回のsyncで新しいIssueを判定するために保存する）
+}
+
 // UpdateEpicStatusBasedOnIssues - Epicのステータスを関連するIssueの状態に基づいて更新する
 func UpdateEpicStatusBasedOnIssues(cfg *config.Config) error {
-	fmt.Println("Epicステータスの更新を開始します...")
-	// すべてのIssueを読み込む
-	issues, err := fileops.ReadAllIssues(cfg.IssuesDir)
+	logger.Debug("Epicステータスの更新を開始します")
+
+	issues, err := fileops.ReadAllIssuesWithStatuses(cfg.IssuesDir, cfg.StatusSet())
 	if err != nil {
 		return fmt.Errorf("Issueの読み込みに失敗しました: %w", err)
 	}
 
-	// IDごとにIssueをグループ化
-	issuesByID := make(map[int][]*models.Issue)
-	for _, issue := range issues {
-		issuesByID[issue.ID] = append(issuesByID[issue.ID], issue)
+	changes, err := planEpicStatusChanges(cfg, issues)
+	if err != nil {
+		return err
+	}
+	if err := applyEpicStatusChanges(cfg, changes); err != nil {
+		return err
 	}
 
-	// 重複チェック
-	for id, issueGroup := range issuesByID {
-		if len(issueGroup) > 1 {
-			fmt.Printf("警告: Issue ID=%d に複数のファイルが見つかりました（%d件）\n", id, len(issueGroup))
-			for _, i := range issueGroup {
-				fmt.Printf("  - ID=%d, Title=%s, Status=%s\n", i.ID, i.Title, i.Status)
-			}
+	// ステータスが変更された場合のみファイル名を更新する
+	if len(changes) > 0 {
+		logger.Debug("Epicステータス変更によりファイル名を更新します")
+		if err := RenameCommand(cfg); err != nil {
+			return fmt.Errorf("ファイル名の更新に失敗しました: %w", err)
 		}
 	}
 
+	enqueueWebhooks(cfg, epicWebhookEvents(changes))
+	runEpicClosedHooks(cfg, changes)
+	return nil
+}
+
+// planEpicStatusChanges - 紐づくIssueがすべて完了扱いになったEpicのステータス変更を計画する
+func planEpicStatusChanges(cfg *config.Config, issues []*models.Issue) ([]EpicStatusChange, error) {
 	// すべてのEpicを読み込む
 	epics, err := fileops.ReadAllEpics(cfg.EpicDir)
 	if err != nil {
-		return fmt.Errorf("Epicの読み込みに失敗しました: %w", err)
+		return nil, fmt.Errorf("Epicの読み込みに失敗しました: %w", err)
 	}
+	return epicStatusChanges(cfg.StatusSet(), epics, issues), nil
+}
 
-	// ステータスが変更されたかどうかを追跡
-	statusChanged := false
-
+// epicStatusChanges - 指定したEpicのうち、紐づくIssueがすべて完了扱いになったもののステータスを変更する
+func epicStatusChanges(statuses models.StatusSet, epics []*models.Epic, issues []*models.Issue) []EpicStatusChange {
 	// Epic IDごとにIssueをグループ化
 	issuesByEpic := make(map[int][]*models.Issue)
 	for _, issue := range issues {
@@ -53,9 +198,10 @@ func UpdateEpicStatusBasedOnIssues(cfg *config.Config) error {
 	}
 
 	// 各Epicについて、関連するIssueのステータスをチェック
+	var changes []EpicStatusChange
 	for _, epic := range epics {
-		if epic.Status == "Close" {
-			// すでにClosedなら何もしない
+		if statuses.IsDone(epic.Status) {
+			// すでに完了扱いなら何もしない
 			continue
 		}
 
@@ -67,122 +213,573 @@ func UpdateEpicStatusBasedOnIssues(cfg *config.Config) error {
 			continue
 		}
 
-		// すべてのIssueがCloseか確認
+		// すべてのIssueが完了扱いか確認
 		allClosed := true
 		for _, issue := range epicIssues {
-			if issue.Status != "Close" {
+			if !statuses.IsDone(issue.Status) {
 				allClosed = false
 				break
 			}
 		}
 
-		// すべてのIssueがClosedの場合、Epicも閉じる
-		if allClosed {
+		// すべてのIssueが完了した場合、Epicも完了ステータスにする
+		if allClosed && epic.Status != statuses.DoneStatus().Name {
 			old := epic.Status
-			epic.Status = "Close"
-
-			// 変更があった場合のみファイルを更新
-			if old != epic.Status {
-				statusChanged = true
-				fmt.Printf("Epic更新: ID=%d, タイトル=%s (ステータス: %s → %s)\n",
-					epic.ID, epic.Title, old, epic.Status)
-
-				// 旧ファイル名を生成
-				oldFilename := utils.GenerateFilename(epic.ID, old, epic.Title)
-				oldFilePath := filepath.Join(cfg.EpicDir, oldFilename)
-
-				// 更新されたEpicを書き込む
-				if err := fileops.WriteEpic(cfg.EpicDir, epic); err != nil {
-					return fmt.Errorf("Epicの更新に失敗しました: %w", err)
-				}
-
-				// 古いファイルを明示的に削除（同じIDの重複ファイルを避けるため）
-				if err := os.Remove(oldFilePath); err != nil {
-					fmt.Printf("警告: 古いEpicファイルの削除に失敗しました: %v\n", err)
-					// 削除に失敗しても進める
-				}
-			}
+			epic.Status = statuses.DoneStatus().Name
+			changes = append(changes, EpicStatusChange{Epic: epic, From: old})
 		}
 	}
 
-	// ステータスが変更された場合のみファイル名を更新する
-	if statusChanged {
-		fmt.Println("Epicステータス変更によりファイル名を更新します...")
-		err = RenameCommand(cfg)
-		if err != nil {
-			return fmt.Errorf("ファイル名の更新に失敗しました: %w", err)
+	return changes
+}
+
+// applyEpicStatusChanges - Epicのステータス変更をファイルに反映する
+func applyEpicStatusChanges(cfg *config.Config, changes []EpicStatusChange) error {
+	statuses := cfg.StatusSet()
+
+	for _, change := range changes {
+		epic := change.Epic
+		logger.Info("Epicのステータスを更新しました", "id", epic.ID, "title", epic.Title, "from", change.From, "to", epic.Status)
+
+		// 旧ファイル名を生成
+		oldFilename := utils.GenerateFilenameWithStatuses(statuses, epic.ID, change.From, epic.Title)
+
+		// 旧ファイルを更新後の内容とファイル名に置き換える（同じIDの重複ファイルを避けるため）
+		if err := fileops.ReplaceEpicWithStatuses(cfg.EpicDir, statuses, epic, oldFilename); err != nil {
+			return fmt.Errorf("Epicの更新に失敗しました: %w", err)
 		}
 	}
 
 	return nil
 }
 
+// SyncOptions - syncコマンドのオプション
+type SyncOptions struct {
+	// 依存先が依存元より下位にある場合にorder.csvを並び替えるかどうか
+	SortByDependencies bool
+	// order.csvとIssueファイルで値が異なる場合に、常にorder.csvの値をIssueファイルに書き戻すかどうか
+	FromCSV bool
+}
+
 // SyncCommand - order.csvとIssueファイルの同期を行う
 func SyncCommand(cfg *config.Config) error {
-	fmt.Println("===== order.csvの同期を開始します... ====")
+	return SyncCommandWithOptions(cfg, SyncOptions{})
+}
+
+// SyncCommandWithOptions - オプションを指定してorder.csvとIssueファイルの同期を行う
+func SyncCommandWithOptions(cfg *config.Config, opts SyncOptions) error {
+	logger.Debug("order.csvの同期を開始します", "file", cfg.OrderCSV)
+
+	plan, err := PlanSync(cfg, opts)
+	if err != nil {
+		return err
+	}
+	return ExecuteSyncPlan(cfg, plan)
+}
+
+// SyncDryRunCommand - syncで行う変更を出力する（ファイルは変更しない）
+func SyncDryRunCommand(cfg *config.Config, opts SyncOptions, w io.Writer) error {
+	plan, err := PlanSync(cfg, opts)
+	if err != nil {
+		return err
+	}
+	return writeSyncPlan(w, cfg, plan)
+}
+
+// PlanSync - order.csvの同期、Epicのステータス更新、ファイル名の変更を計画する（ファイルは変更しない）
+func PlanSync(cfg *config.Config, opts SyncOptions) (*SyncPlan, error) {
+	statuses := cfg.StatusSet()
 
 	// 1. すべてのIssueを読み込む
-	issues, err := fileops.ReadAllIssues(cfg.IssuesDir)
+	issues, dependencyProblems, err := fileops.ReadAllIssuesWithProblems(cfg.IssuesDir, statuses)
 	if err != nil {
-		return fmt.Errorf("Issueの読み込みに失敗しました: %w", err)
+		return nil, fmt.Errorf("Issueの読み込みに失敗しました: %w", err)
+	}
+	fileops.WarnDependencyProblems(dependencyProblems)
+
+	plan, err := planOrderSync(cfg, opts, issues)
+	if err != nil {
+		return nil, err
+	}
+
+	// Epicステータスを関連するIssueに基づいて更新
+	if plan.EpicChanges, err = planEpicStatusChanges(cfg, issues); err != nil {
+		return nil, fmt.Errorf("Epicステータスの更新に失敗しました: %w", err)
 	}
 
-	// 2. 現在のorder.csvを読み込む
-	orderItems, err := parser.ReadOrderCSV(cfg.OrderCSV)
+	// ステータスを変更するEpicのファイル名はEpicの更新時に変更されるため除外する
+	renames, err := PlanRename(cfg)
 	if err != nil {
-		return fmt.Errorf("order.csvの読み込みに失敗しました: %w", err)
+		return nil, fmt.Errorf("ファイル名の更新に失敗しました: %w", err)
 	}
+	plan.Renames = excludeEpicRenames(renames, plan.EpicChanges)
+
+	return plan, nil
+}
 
-	// 3. Closeになっているものをorder.csvから削除
-	// 4. 新しいOpenのIssueをorder.csvに追加
+// planOrderSync - 読み込んだIssueとorder.csvの同期を計画する
+func planOrderSync(cfg *config.Config, opts SyncOptions, issues []*models.Issue) (*SyncPlan, error) {
+	statuses := cfg.StatusSet()
+
+	// 2. 現在のorder.csvを読み込む（手で編集した不正な行があっても同期を続ける）
+	orderItems, fileColumns, problems, err := parser.ReadOrderCSVWithProblems(cfg.OrderCSV)
+	if err != nil {
+		return nil, fmt.Errorf("order.csvの読み込みに失敗しました: %w", err)
+	}
+	invalid := make(map[int]bool)
+	for _, problem := range problems {
+		if problem.ID != 0 {
+			invalid[problem.ID] = true
+			logger.Warn("order.csvの行の値が不正なため、Issueファイルの値を使用します", "line", problem.Line, "id", problem.ID, "error", problem.Message)
+		} else {
+			logger.Warn("order.csvの不正な行を削除します", "line", problem.Line, "error", problem.Message)
+		}
+	}
+
+	// 3. 完了扱いになっているものをorder.csvから削除
+	// 4. 新しい未完了のIssueをorder.csvに追加
 	var newOrderItems []models.OrderCSVItem
 
 	// 既存のマップを作成
 	existingIDs := make(map[int]bool)
 	for _, issue := range issues {
-		if issue.Status == "Open" {
+		if !statuses.IsDone(issue.Status) {
 			existingIDs[issue.ID] = true
 		}
 	}
 
-	// クローズされたIssueを除外
-	for _, item := range orderItems {
-		if existingIDs[item.ID] {
-			newOrderItems = append(newOrderItems, item)
+	issuesByID := make(map[int]*models.Issue, len(issues))
+	for _, issue := range issues {
+		issuesByID[issue.ID] = issue
+	}
+
+	plan := &SyncPlan{OrderBefore: orderItems, Columns: orderColumns(cfg, fileColumns)}
+	if cfg.OrderingMode() == config.OrderingRank {
+		// rankで並び順を管理する場合、order.csvはIssueファイルから生成する
+		plan.OrderAfter, plan.RankChanges, plan.Reordered = rankedOrder(cfg, issues, orderItems, opts.SortByDependencies)
+		plan.Refreshed = rankedRowChanges(orderItems, plan.OrderAfter)
+	} else {
+		// 変更された側を判定するための情報
+		reconciler, err := newOrderReconciler(cfg, opts.FromCSV)
+		if err != nil {
+			return nil, err
+		}
+
+		// クローズされたIssueを除外し、残す行とIssueファイルの値の違いを同期する
+		// 行の順序は変えず、存在しないIssueと重複した行は警告して削除する
+		listed := make(map[int]bool, len(orderItems))
+		for _, item := range orderItems {
+			issue, known := issuesByID[item.ID]
+			switch {
+			case !known:
+				logger.Warn("order.csvに存在しないIssueの行があるため削除します", "id", item.ID)
+			case listed[item.ID]:
+				logger.Warn("order.csvで重複している行を削除します", "id", item.ID)
+			case existingIDs[item.ID] && invalid[item.ID]:
+				row := orderRow(cfg, issue, item.Extra)
+				plan.Refreshed = append(plan.Refreshed, OrderRowChange{Before: item, After: row})
+				newOrderItems = append(newOrderItems, row)
+			case existingIDs[item.ID]:
+				newOrderItems = append(newOrderItems, reconciler.reconcile(plan, item, issue))
+			}
+			listed[item.ID] = true
 			delete(existingIDs, item.ID)
 		}
-	}
 
-	// 新しいOpenのIssueを追加
+		// 新しい未完了のIssueを設定した位置に追加
+		var added []*models.Issue
+		for _, issue := range issues {
+			if !statuses.IsDone(issue.Status) && existingIDs[issue.ID] {
+				added = append(added, issue)
+			}
+		}
+		newOrderItems = placeNewItems(cfg, newOrderItems, added, issuesByID)
+
+		// 依存関係と並び順の整合性をチェック
+		plan.OrderAfter, plan.Reordered = checkDependencyOrder(issues, newOrderItems, statuses, opts.SortByDependencies)
+		plan.NextBase = reconciler.nextBase(plan)
+	}
+	known, err := readKnownIssues(cfg.KnownIssuesFile())
+	if err != nil {
+		return nil, fmt.Errorf("前回の同期内容の読み込みに失敗しました: %w", err)
+	}
+	plan.Created, plan.Closed = orderEvents(statuses, known, orderItems, plan.OrderAfter, issuesByID)
 	for _, issue := range issues {
-		if issue.Status == "Open" && existingIDs[issue.ID] {
-			newOrderItems = append(newOrderItems, models.OrderCSVItem{
-				ID:       issue.ID,
-				Title:    issue.Title,
-				Epic:     issue.Epic,
-				Estimate: issue.Estimate,
-			})
+		plan.Known = append(plan.Known, issue.ID)
+	}
+	plan.Updated = updatedIssues(plan, issuesByID)
+
+	return plan, nil
+}
+
+// excludeEpicRenames - ステータスを変更するEpicのファイル名の変更を除く
+func excludeEpicRenames(renames []RenamePlan, changes []EpicStatusChange) []RenamePlan {
+	changing := make(map[int]bool, len(changes))
+	for _, change := range changes {
+		changing[change.Epic.ID] = true
+	}
+
+	var kept []RenamePlan
+	for _, rename := range renames {
+		if !(rename.Epic && changing[rename.ID]) {
+			kept = append(kept, rename)
 		}
 	}
+	return kept
+}
+
+// ExecuteSyncPlan - syncの計画をファイルに反映する
+func ExecuteSyncPlan(cfg *config.Config, plan *SyncPlan) error {
+	if err := applySyncPlan(cfg, plan); err != nil {
+		return err
+	}
 
+	// Epicステータス変更後に確実にファイル名を更新する
+	// （Epicの更新でリネームが衝突処理に任された場合も含めるため、計画ではなく現在のファイルから求める）
+	renameErr := RenameCommand(cfg)
+
+	// ファイル名の更新に失敗しても、order.csvとステータスの変更は反映済みのためWebhookとフックを実行する
+	// （次回のsyncでは変更として検出されず、送信・実行する機会がなくなる）
+	enqueueSyncWebhooks(cfg, plan)
+	runSyncHooks(cfg, plan)
+	if renameErr != nil {
+		return fmt.Errorf("ファイル名の更新に失敗しました: %w", renameErr)
+	}
+	return nil
+}
+
+// applySyncPlan - syncの計画のうち、ファイル名の変更以外をファイルに反映する
+func applySyncPlan(cfg *config.Config, plan *SyncPlan) error {
 	// 5. 更新したorder.csvを書き込む
-	if err := parser.WriteOrderCSV(cfg.OrderCSV, newOrderItems); err != nil {
+	if err := parser.WriteOrderCSVWithColumns(cfg.OrderCSV, plan.OrderAfter, plan.Columns); err != nil {
 		return fmt.Errorf("order.csvの書き込みに失敗しました: %w", err)
 	}
 
-	fmt.Printf("同期完了: %d件のIssueがorder.csvに保存されました\n", len(newOrderItems))
+	for _, change := range plan.Refreshed {
+		logger.Info("order.csvの行をIssueファイルの内容で更新しました", "id", change.After.ID, "changes", strings.Join(change.Fields(), ", "))
+	}
+	for _, conflict := range plan.Conflicts {
+		logger.Warn("order.csvとIssueファイルの両方で変更されているため同期しませんでした", "id", conflict.ID, "conflicts", strings.Join(conflict.Fields, ", "))
+	}
+
+	// order.csvで変更された値をIssueファイルに書き戻す
+	for _, wb := range plan.WriteBacks {
+		if err := fileops.UpdateIssueWithStatuses(cfg.IssuesDir, cfg.StatusSet(), wb.Issue); err != nil {
+			return fmt.Errorf("Issueファイルへの書き戻しに失敗しました（ID=%d）: %w", wb.Issue.ID, err)
+		}
+		logger.Info("order.csvの変更をIssueファイルに書き戻しました", "id", wb.Issue.ID, "changes", strings.Join(wb.Change.Fields(), ", "))
+	}
+
+	// 並び順に合わせて割り当てたrankをIssueファイルに書き込む
+	for _, change := range plan.RankChanges {
+		if err := fileops.UpdateIssueWithStatuses(cfg.IssuesDir, cfg.StatusSet(), change.Issue); err != nil {
+			return fmt.Errorf("rankの書き込みに失敗しました（ID=%d）: %w", change.Issue.ID, err)
+		}
+		logger.Info("Issueのrankを設定しました", "id", change.Issue.ID, "from", change.From, "rank", change.Issue.Rank)
+	}
+
+	// 次回のsyncで変更された側を判定できるよう、同期した内容を保存する
+	// （rankで並び順を管理する場合、order.csvは生成するだけなので保存しない）
+	if cfg.OrderingMode() == config.OrderingCSV {
+		baseFile := cfg.SyncBaseFile()
+		if err := os.MkdirAll(filepath.Dir(baseFile), 0755); err != nil {
+			return fmt.Errorf("同期内容の保存に失敗しました: %w", err)
+		}
+		if err := parser.WriteOrderCSVWithColumns(baseFile, plan.NextBase, plan.Columns); err != nil {
+			return fmt.Errorf("同期内容の保存に失敗しました: %w", err)
+		}
+	}
+	if err := writeKnownIssues(cfg.KnownIssuesFile(), plan.Known); err != nil {
+		return fmt.Errorf("同期内容の保存に失敗しました: %w", err)
+	}
+	if plan.Reordered > 0 {
+		logger.Info("依存関係に基づいてorder.csvを並び替えました", "violations", plan.Reordered)
+	}
+	logger.Info("order.csvを同期しました", "issues", len(plan.OrderAfter))
 
 	// Epicステータスを関連するIssueに基づいて更新
-	if err := UpdateEpicStatusBasedOnIssues(cfg); err != nil {
+	if err := applyEpicStatusChanges(cfg, plan.EpicChanges); err != nil {
 		return fmt.Errorf("Epicステータスの更新に失敗しました: %w", err)
 	}
 
-	// Epicステータス変更後に確実にファイル名を更新する
-	if err := RenameCommand(cfg); err != nil {
-		return fmt.Errorf("ファイル名の更新に失敗しました: %w", err)
+	return nil
+}
+
+// readKnownIssues - 前回のsyncの時点で存在したIssueのIDを読み込む（記録がない場合はnil）
+func readKnownIssues(path string) (map[int]bool, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[int]bool)
+	for _, line := range strings.Fields(string(data)) {
+		id, err := strconv.Atoi(line)
+		if err != nil {
+			return nil, fmt.Errorf("不正なIssue ID: %s", line)
+		}
+		known[id] = true
 	}
+	return known, nil
+}
 
-	fmt.Println("===== order.csvの同期が完了しました ====")
+// writeKnownIssues - 同期した時点で存在するIssueのIDを1行に1つずつ保存する
+func writeKnownIssues(path string, ids []int) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	var b strings.Builder
+	for _, id := range ids {
+		fmt.Fprintf(&b, "%d\n", id)
+	}
+	return utils.WriteFileAtomic(path, []byte(b.String()), 0644)
+}
 
-	return nil
+// HasChanges - 計画に変更が含まれるかどうかを返す
+func (p *SyncPlan) HasChanges() bool {
+	if len(p.EpicChanges) > 0 || len(p.Renames) > 0 || len(p.WriteBacks) > 0 || len(p.Conflicts) > 0 || len(p.RankChanges) > 0 || len(p.OrderBefore) != len(p.OrderAfter) {
+		return true
+	}
+	for i := range p.OrderBefore {
+		if !p.OrderBefore[i].Equal(p.OrderAfter[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// writeSyncPlan - syncの計画を出力する
+func writeSyncPlan(w io.Writer, cfg *config.Config, plan *SyncPlan) error {
+	if !plan.HasChanges() {
+		_, err := fmt.Fprintln(w, "変更はありません")
+		return err
+	}
+	fmt.Fprintln(w, "dry-run: 以下の変更は適用されていません")
+
+	// order.csvの差分（削除・追加・並び替え）
+	before := make(map[int]bool, len(plan.OrderBefore))
+	for _, item := range plan.OrderBefore {
+		before[item.ID] = true
+	}
+	after := make(map[int]bool, len(plan.OrderAfter))
+	for _, item := range plan.OrderAfter {
+		after[item.ID] = true
+	}
+
+	var lines []string
+	for _, item := range plan.OrderBefore {
+		if !after[item.ID] {
+			lines = append(lines, fmt.Sprintf("  - %d %s", item.ID, item.Title))
+		}
+	}
+	for _, item := range plan.OrderAfter {
+		if !before[item.ID] {
+			lines = append(lines, fmt.Sprintf("  + %d %s", item.ID, item.Title))
+		}
+	}
+	for _, change := range plan.Refreshed {
+		lines = append(lines, fmt.Sprintf("  ~ %d %s（%s）", change.After.ID, change.After.Title, strings.Join(change.Fields(), ", ")))
+	}
+	for _, wb := range plan.WriteBacks {
+		lines = append(lines, fmt.Sprintf("  → %d %s: Issueファイルに書き戻します（%s）", wb.Issue.ID, wb.Issue.Title, strings.Join(wb.Change.Fields(), ", ")))
+	}
+	for _, conflict := range plan.Conflicts {
+		lines = append(lines, fmt.Sprintf("  ! %d: 両方で変更されているため同期しません（%s）", conflict.ID, strings.Join(conflict.Fields, ", ")))
+	}
+	for _, change := range plan.RankChanges {
+		from := change.From
+		if from == "" {
+			from = "未設定"
+		}
+		lines = append(lines, fmt.Sprintf("  → %d %s: rankを設定します（%s → %s）", change.Issue.ID, change.Issue.Title, from, change.Issue.Rank))
+	}
+	if plan.Reordered > 0 {
+		lines = append(lines, fmt.Sprintf("  依存関係に基づいて並び替えます（%d件の違反を解消）", plan.Reordered))
+	}
+	if len(lines) > 0 {
+		fmt.Fprintf(w, "%s:\n", relativePath(cfg.ProjectsDir, cfg.OrderCSV))
+		for _, line := range lines {
+			fmt.Fprintln(w, line)
+		}
+	}
+
+	if len(plan.EpicChanges) > 0 {
+		fmt.Fprintln(w, "Epicのステータス:")
+		for _, change := range plan.EpicChanges {
+			epic := change.Epic
+			fmt.Fprintf(w, "  %d %s: %s → %s\n", epic.ID, epic.Title, change.From, epic.Status)
+		}
+	}
+
+	return writeRenamePlans(w, cfg, plan.Renames)
+}
+
+// checkDependencyOrder - 未完了の依存先より上位にあるIssueを警告し、必要に応じて並び替える
+// 並び替えた場合は解消した違反の数を返す
+func checkDependencyOrder(issues []*models.Issue, orderItems []models.OrderCSVItem, statuses models.StatusSet, sortItems bool) ([]models.OrderCSVItem, int) {
+	// 未完了のIssueのみを依存先として扱う
+	openIDs := make(map[int]bool)
+	for _, issue := range issues {
+		if !statuses.IsDone(issue.Status) {
+			openIDs[issue.ID] = true
+		}
+	}
+	blockedBy := make(map[int][]int)
+	for _, issue := range issues {
+		for _, dep := range issue.BlockedBy {
+			if openIDs[dep] {
+				blockedBy[issue.ID] = append(blockedBy[issue.ID], dep)
+			}
+		}
+	}
+
+	order := make([]int, 0, len(orderItems))
+	itemByID := make(map[int]models.OrderCSVItem, len(orderItems))
+	for _, item := range orderItems {
+		order = append(order, item.ID)
+		itemByID[item.ID] = item
+	}
+
+	violations := utils.FindOrderViolations(order, blockedBy)
+	if len(violations) == 0 {
+		return orderItems, 0
+	}
+
+	if !sortItems {
+		for _, v := range violations {
+			logger.Warn("依存先のIssueより上位に配置されています", "id", v.IssueID, "blocked_by", v.BlockedByID)
+		}
+		return orderItems, 0
+	}
+
+	sorted := make([]models.OrderCSVItem, 0, len(orderItems))
+	for _, id := range utils.SortByDependencies(order, blockedBy) {
+		sorted = append(sorted, itemByID[id])
+	}
+	return sorted, len(violations)
+}
+
+// orderReconciler - order.csvの行とIssueファイルの値が異なる場合に、どちらの値を採用するかを判定する
+//
+// 前回のsyncで書き込んだ内容（SyncBaseFile）と比較して変更された側の値を採用し、
+// 両方で変更されている場合は衝突として報告する。前回の内容がない行はIssueファイルの値を採用する
+// （前回の内容はgitで共有しないため、クローン直後などに古いorder.csvの値で上書きしないようにする）。
+// タイトルはファイル名に影響するため、常にIssueファイルの値を採用する
+type orderReconciler struct {
+	cfg       *config.Config
+	fromCSV   bool
+	base      map[int]models.OrderCSVItem
+	conflicts map[int]bool
+}
+
+// newOrderReconciler - 前回の同期内容を読み込む
+func newOrderReconciler(cfg *config.Config, fromCSV bool) (*orderReconciler, error) {
+	baseItems, err := parser.ReadOrderCSV(cfg.SyncBaseFile())
+	if err != nil {
+		return nil, fmt.Errorf("前回の同期内容の読み込みに失敗しました: %w", err)
+	}
+
+	r := &orderReconciler{
+		cfg:       cfg,
+		fromCSV:   fromCSV,
+		base:      make(map[int]models.OrderCSVItem, len(baseItems)),
+		conflicts: make(map[int]bool),
+	}
+	for _, item := range baseItems {
+		r.base[item.ID] = item
+	}
+	return r, nil
+}
+
+// reconcile - order.csvの行とIssueの値の違いを同期し、order.csvに書き込む行を返す
+// order.csvの値を採用する場合はIssueを更新して書き戻しの予定に追加する
+func (r *orderReconciler) reconcile(plan *SyncPlan, item models.OrderCSVItem, issue *models.Issue) models.OrderCSVItem {
+	fromFile := models.OrderCSVItem{ID: issue.ID, Title: issue.Title, Epic: issue.Epic, Estimate: issue.Estimate}
+	row := item
+	row.Title = fromFile.Title
+	row.Extra = withOrderColumns(r.cfg, issue, item.Extra)
+	written := fromFile // Issueファイルに書き戻した後の値
+
+	base, hasBase := r.base[item.ID]
+	var conflicts []string
+	for _, field := range []struct {
+		name          string
+		csv, file     int
+		base          int
+		setRow, setWB func(int)
+	}{
+		{"epic", item.Epic, fromFile.Epic, base.Epic, func(v int) { row.Epic = v }, func(v int) { written.Epic = v }},
+		{"estimate", item.Estimate, fromFile.Estimate, base.Estimate, func(v int) { row.Estimate = v }, func(v int) { written.Estimate = v }},
+	} {
+		if field.csv == field.file {
+			continue
+		}
+
+		switch r.changedSide(hasBase, field.csv, field.file, field.base) {
+		case sideCSV:
+			field.setWB(field.csv)
+		case sideFile:
+			field.setRow(field.file)
+		default:
+			// 両方で変更されている場合はどちらも変更しない
+			conflicts = append(conflicts, fmt.Sprintf("%s: order.csv=%d, ファイル=%d", field.name, field.csv, field.file))
+		}
+	}
+
+	if len(conflicts) > 0 {
+		r.conflicts[item.ID] = true
+		plan.Conflicts = append(plan.Conflicts, OrderConflict{ID: item.ID, Fields: conflicts})
+	}
+	if !row.Equal(item) {
+		plan.Refreshed = append(plan.Refreshed, OrderRowChange{Before: item, After: row})
+	}
+	if !written.Equal(fromFile) {
+		issue.Epic, issue.Estimate = written.Epic, written.Estimate
+		plan.WriteBacks = append(plan.WriteBacks, IssueWriteBack{Issue: issue, Change: OrderRowChange{Before: fromFile, After: written}})
+	}
+	return row
+}
+
+// 値が変更された側
+const (
+	sideFile = iota
+	sideCSV
+	sideBoth
+)
+
+// changedSide - order.csvとIssueファイルのどちらの値が変更されたかを判定する
+// 前回の同期内容がない場合は、Issueファイルを正とする（--from-csvの場合を除く）
+func (r *orderReconciler) changedSide(hasBase bool, csv, file, base int) int {
+	if r.fromCSV {
+		return sideCSV
+	}
+	if !hasBase {
+		return sideFile
+	}
+
+	csvChanged, fileChanged := csv != base, file != base
+	switch {
+	case csvChanged && fileChanged:
+		return sideBoth
+	case csvChanged:
+		return sideCSV
+	default:
+		return sideFile
+	}
+}
+
+// nextBase - 同期後に保存する前回の同期内容を返す
+// 衝突した行は解消されるまで報告し続けるよう、前回の内容を維持する
+func (r *orderReconciler) nextBase(plan *SyncPlan) []models.OrderCSVItem {
+	items := make([]models.OrderCSVItem, 0, len(plan.OrderAfter))
+	for _, item := range plan.OrderAfter {
+		if base, ok := r.base[item.ID]; ok && r.conflicts[item.ID] {
+			item = base
+		}
+		items = append(items, item)
+	}
+	return items
 }
@@ -1,28 +1,37 @@
 package commands
 
 import (
+	"errors"
 	"fmt"
-	"os"
-	"path/filepath"
 
 	"github.com/moai/instant-backlog/internal/config"
+	"github.com/moai/instant-backlog/internal/logger"
 	"github.com/moai/instant-backlog/internal/watcher"
+	"github.com/moai/instant-backlog/pkg/utils"
 )
 
 // UnwatchCommand - 指定したプロジェクトパスの監視を停止
+// 別プロセスで監視が起動している場合は制御ソケット経由で停止する
 func UnwatchCommand(cfg *config.Config, projectPath string) error {
 	// プロジェクトパスが指定されていない場合はcfgから取得
 	if projectPath == "" {
 		projectPath = cfg.ProjectsDir
 	}
 
-	// パスが相対パスの場合は絶対パスに変換
-	if !filepath.IsAbs(projectPath) {
-		currentDir, err := os.Getwd()
+	// 同じプロジェクトを同じキーで扱えるよう絶対パスに正規化
+	projectPath = utils.AbsPath(projectPath)
+
+	// 起動中の監視プロセスに停止を依頼
+	resp, err := watcher.SendControlRequest(stateDirFor(cfg), watcher.ControlRequest{
+		Action:  watcher.ActionUnwatch,
+		Project: projectPath,
+	})
+	if !errors.Is(err, watcher.ErrWatcherNotRunning) {
 		if err != nil {
-			return fmt.Errorf("カレントディレクトリの取得に失敗しました: %w", err)
+			return fmt.Errorf("監視の停止に失敗しました: %w", err)
 		}
-		projectPath = filepath.Join(currentDir, projectPath)
+		logger.Info("プロジェクトの監視を停止しました", "project", projectPath, "pid", resp.PID)
+		return nil
 	}
 
 	// ウォッチマネージャーの取得
@@ -34,30 +43,45 @@ func UnwatchCommand(cfg *config.Config, projectPath string) error {
 	}
 
 	// 監視の停止
-	err := manager.StopWatching(projectPath)
+	err = manager.StopWatching(projectPath)
 	if err != nil {
 		return fmt.Errorf("監視の停止に失敗しました: %w", err)
 	}
 
-	fmt.Printf("===== プロジェクト '%s' の監視を停止しました =====\n", projectPath)
 	return nil
 }
 
 // UnwatchAllCommand - すべてのプロジェクトの監視を停止
-func UnwatchAllCommand(_ *config.Config) error {
+// 別プロセスで監視が起動している場合は制御ソケット経由で停止する
+func UnwatchAllCommand(cfg *config.Config) error {
+	stateDir := stateDirFor(cfg)
+
+	// 起動中の監視プロセスに停止を依頼
+	resp, err := watcher.SendControlRequest(stateDir, watcher.ControlRequest{Action: watcher.ActionList})
+	if !errors.Is(err, watcher.ErrWatcherNotRunning) {
+		if err != nil {
+			return fmt.Errorf("監視状態の取得に失敗しました: %w", err)
+		}
+		if _, err := watcher.SendControlRequest(stateDir, watcher.ControlRequest{Action: watcher.ActionUnwatchAll}); err != nil {
+			return fmt.Errorf("監視の停止に失敗しました: %w", err)
+		}
+		logger.Info("すべてのプロジェクトの監視を停止しました", "projects", len(resp.Projects), "pid", resp.PID)
+		return nil
+	}
+
 	// ウォッチマネージャーの取得
 	manager := watcher.GetManager()
 
 	// 現在監視中のプロジェクトを取得
 	projects := manager.GetWatchingProjects()
 	if len(projects) == 0 {
-		fmt.Println("===== 現在監視中のプロジェクトはありません =====")
+		logger.Info("現在監視中のプロジェクトはありません")
 		return nil
 	}
 
 	// すべての監視を停止
 	manager.StopAll()
 
-	fmt.Printf("===== すべてのプロジェクト（%d個）の監視を停止しました =====\n", len(projects))
+	logger.Info("すべてのプロジェクトの監視を停止しました", "projects", len(projects))
 	return nil
 }
@@ -1,45 +1,32 @@
 package commands
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
-	"path/filepath"
 	"syscall"
 	"time"
 
 	"github.com/moai/instant-backlog/internal/config"
+	"github.com/moai/instant-backlog/internal/logger"
 	"github.com/moai/instant-backlog/internal/watcher"
+	"github.com/moai/instant-backlog/pkg/utils"
 )
 
-// デフォルトのデバウンス時間（ミリ秒）
-const defaultDebounceTime = 500 * time.Millisecond
-
 // WatchCommand - 指定したプロジェクトパスの監視を開始
+// 既に別の監視プロセスが起動している場合は、そのプロセスに監視を依頼する
 func WatchCommand(cfg *config.Config, projectPath string) error {
-	// プロジェクトパスが指定されていない場合はcfgから取得
-	if projectPath == "" {
-		projectPath = cfg.ProjectsDir
-	}
-
-	// パスが相対パスの場合は絶対パスに変換
-	if !filepath.IsAbs(projectPath) {
-		currentDir, err := os.Getwd()
-		if err != nil {
-			return fmt.Errorf("カレントディレクトリの取得に失敗しました: %w", err)
-		}
-		projectPath = filepath.Join(currentDir, projectPath)
-	}
-
-	// プロジェクトパスの検証
-	if _, err := os.Stat(projectPath); os.IsNotExist(err) {
-		return fmt.Errorf("指定されたプロジェクトパスが存在しません: %s", projectPath)
+	projectPath, err := resolveWatchPath(cfg, projectPath)
+	if err != nil {
+		return err
 	}
 
-	// issuesディレクトリの検証
-	issuesDir := filepath.Join(projectPath, "issues")
-	if _, err := os.Stat(issuesDir); os.IsNotExist(err) {
-		return fmt.Errorf("issuesディレクトリが存在しません: %s", issuesDir)
+	// 起動中の監視プロセスがあればそちらに監視を依頼
+	stateDir := stateDirFor(cfg)
+	if delegated, err := delegateWatch(stateDir, projectPath, cfg.DebounceTime()); delegated || err != nil {
+		return err
 	}
 
 	// ウォッチマネージャーの取得
@@ -51,26 +38,142 @@ func WatchCommand(cfg *config.Config, projectPath string) error {
 	}
 
 	// 監視の開始
-	err := manager.StartWatching(projectPath, defaultDebounceTime)
+	err = manager.StartWatching(projectPath, cfg.DebounceTime())
 	if err != nil {
 		return fmt.Errorf("監視の開始に失敗しました: %w", err)
 	}
 
-	fmt.Printf("\nプロジェクト '%s' の監視を開始しました\n", projectPath)
-	fmt.Println("監視を停止するには Ctrl+C を押すか、別のターミナルで 'ib unwatch' を実行してください")
+	// 別のターミナルから操作できるよう制御ソケットを開く
+	var idle <-chan struct{}
+	server, err := watcher.StartControlServer(stateDir, manager)
+	if err != nil {
+		logger.Warn("制御ソケットを開けないため、別のターミナルからの操作はできません", "error", err)
+	} else {
+		defer server.Close()
+		idle = server.Idle()
+	}
+
+	logger.Info("監視を停止するには Ctrl+C を押すか、別のターミナルで 'ib unwatch' を実行してください")
 
 	// シグナルハンドリング（Ctrl+Cでの終了）
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
+
+	// ブロッキング処理（シグナルまたはunwatchによる全プロジェクトの停止を待つ）
+	select {
+	case <-sigChan:
+		logger.Info("終了シグナルを受信しました")
+	case <-idle:
+		logger.Info("すべてのプロジェクトの監視が停止されました")
+	}
+
+	// 監視の停止（制御ソケット経由で追加されたプロジェクトも含む）
+	logger.Debug("監視を停止しています")
+	manager.StopAll()
+
+	return nil
+}
+
+// WatchDaemonCommand - バックグラウンドの監視デーモンでプロジェクトの監視を開始
+func WatchDaemonCommand(cfg *config.Config, projectPath string) error {
+	projectPath, err := resolveWatchPath(cfg, projectPath)
+	if err != nil {
+		return err
+	}
+
+	// 起動中の監視プロセスがあればそちらに監視を依頼
+	stateDir := stateDirFor(cfg)
+	if delegated, err := delegateWatch(stateDir, projectPath, cfg.DebounceTime()); delegated || err != nil {
+		return err
+	}
+
+	// デーモンでも同じ設定ファイルとログ設定が使われるように引き継ぐ
+	args := append([]string{"--project", cfg.BaseDir}, logger.Flags()...)
+	pid, err := watcher.SpawnDaemon(stateDir, append(args, "watch", projectPath))
+	if err != nil {
+		return err
+	}
+
+	logger.Info("監視デーモンを起動しました", "pid", pid, "project", projectPath, "log", watcher.LogPath(stateDir))
+	logger.Info("監視を停止するには 'ib unwatch' を実行してください")
+	return nil
+}
 
-	// ブロッキング処理
-	<-sigChan
+// WatchListCommand - 起動中の監視プロセスが監視しているプロジェクトを表示
+func WatchListCommand(cfg *config.Config, w io.Writer) error {
+	resp, err := watcher.SendControlRequest(stateDirFor(cfg), watcher.ControlRequest{Action: watcher.ActionList})
+	if errors.Is(err, watcher.ErrWatcherNotRunning) {
+		fmt.Fprintln(w, "監視プロセスは起動していません")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("監視状態の取得に失敗しました: %w", err)
+	}
 
-	// 監視の停止
-	fmt.Println("\n===== 監視を停止しています... =====")
-	if err := manager.StopWatching(projectPath); err != nil {
-		return fmt.Errorf("監視の停止に失敗しました: %w", err)
+	if len(resp.Projects) == 0 {
+		fmt.Fprintf(w, "監視プロセス (PID=%d) で監視中のプロジェクトはありません\n", resp.PID)
+		return nil
 	}
 
+	fmt.Fprintf(w, "監視プロセス (PID=%d) で監視中のプロジェクト:\n", resp.PID)
+	for _, project := range resp.Projects {
+		fmt.Fprintf(w, "  %s\n", project)
+	}
 	return nil
 }
+
+// delegateWatch - 起動中の監視プロセスにプロジェクトの監視を依頼する
+// 監視プロセスが起動していない場合は delegated=false を返す
+func delegateWatch(stateDir, projectPath string, debounce time.Duration) (bool, error) {
+	resp, err := watcher.SendControlRequest(stateDir, watcher.ControlRequest{
+		Action:     watcher.ActionWatch,
+		Project:    projectPath,
+		DebounceMS: debounce.Milliseconds(),
+	})
+	if errors.Is(err, watcher.ErrWatcherNotRunning) {
+		return false, nil
+	}
+	if err != nil {
+		return true, fmt.Errorf("監視の開始に失敗しました: %w", err)
+	}
+
+	logger.Info("起動中の監視プロセスでプロジェクトの監視を開始しました", "pid", resp.PID, "project", projectPath)
+	return true, nil
+}
+
+// resolveWatchPath - 監視対象のプロジェクトパスを絶対パスにして検証する
+func resolveWatchPath(cfg *config.Config, projectPath string) (string, error) {
+	// プロジェクトパスが指定されていない場合はcfgから取得
+	if projectPath == "" {
+		projectPath = cfg.ProjectsDir
+	}
+
+	// 同じプロジェクトを同じキーで扱えるよう絶対パスに正規化
+	projectPath = utils.AbsPath(projectPath)
+
+	// プロジェクトパスの検証
+	if _, err := os.Stat(projectPath); os.IsNotExist(err) {
+		return "", fmt.Errorf("指定されたプロジェクトパスが存在しません: %s", projectPath)
+	}
+
+	// issuesディレクトリの検証
+	projectCfg, err := config.ForProject(projectPath)
+	if err != nil {
+		return "", fmt.Errorf("設定の読み込みに失敗しました: %w", err)
+	}
+	issuesDir := projectCfg.IssuesDir
+	if _, err := os.Stat(issuesDir); os.IsNotExist(err) {
+		return "", fmt.Errorf("issuesディレクトリが存在しません: %s", issuesDir)
+	}
+
+	return projectPath, nil
+}
+
+// stateDirFor - 監視プロセスの状態ディレクトリを返す（設定で指定されていればそれを使用）
+func stateDirFor(cfg *config.Config) string {
+	if cfg != nil {
+		return cfg.StateDirectory()
+	}
+	return watcher.StateDir()
+}
@@ -0,0 +1,124 @@
+package commands
+
+import (
+	"path/filepath"
+	"slices"
+
+	"github.com/moai/instant-backlog/internal/config"
+	"github.com/moai/instant-backlog/internal/logger"
+	"github.com/moai/instant-backlog/internal/models"
+	"github.com/moai/instant-backlog/internal/watcher"
+	"github.com/moai/instant-backlog/internal/webhooks"
+	"github.com/moai/instant-backlog/pkg/utils"
+)
+
+// updatedIssues - order.csvの行をIssueファイルの内容で更新した、またはorder.csvの値を書き戻したIssueを返す
+func updatedIssues(plan *SyncPlan, issuesByID map[int]*models.Issue) []*models.Issue {
+	seen := make(map[int]bool)
+	for _, issue := range plan.Created {
+		seen[issue.ID] = true
+	}
+
+	var updated []*models.Issue
+	for _, change := range plan.Refreshed {
+		if issue, ok := issuesByID[change.After.ID]; ok && !seen[issue.ID] {
+			updated = append(updated, issue)
+			seen[issue.ID] = true
+		}
+	}
+	for _, wb := range plan.WriteBacks {
+		if !seen[wb.Issue.ID] {
+			updated = append(updated, wb.Issue)
+			seen[wb.Issue.ID] = true
+		}
+	}
+	return updated
+}
+
+// enqueueSyncWebhooks - syncで発生したイベントをWebhookの送信待ちに追加する
+// 追加に失敗してもsyncは失敗させない
+func enqueueSyncWebhooks(cfg *config.Config, plan *SyncPlan) {
+	if len(cfg.Webhooks) == 0 {
+		return
+	}
+
+	var events []webhooks.Event
+	for _, issue := range plan.Created {
+		events = append(events, webhooks.Event{Name: config.WebhookIssueCreated, Issue: webhookIssue(issue)})
+	}
+	for _, issue := range plan.Updated {
+		events = append(events, webhooks.Event{Name: config.WebhookIssueUpdated, Issue: webhookIssue(issue)})
+	}
+	for _, issue := range plan.Closed {
+		events = append(events, webhooks.Event{Name: config.WebhookIssueClosed, Issue: webhookIssue(issue)})
+	}
+	events = append(events, epicWebhookEvents(plan.EpicChanges)...)
+	if order := itemIDs(plan.OrderAfter); !slices.Equal(itemIDs(plan.OrderBefore), order) {
+		events = append(events, webhooks.Event{Name: config.WebhookOrderChanged, Order: order})
+	}
+
+	enqueueWebhooks(cfg, events)
+}
+
+// epicWebhookEvents - 完了にしたEpicごとのepic.closedのイベントを作成する
+func epicWebhookEvents(changes []EpicStatusChange) []webhooks.Event {
+	var events []webhooks.Event
+	for _, change := range changes {
+		epic := change.Epic
+		events = append(events, webhooks.Event{
+			Name: config.WebhookEpicClosed,
+			Epic: &webhooks.Epic{ID: epic.ID, Title: epic.Title, Status: epic.Status, From: change.From},
+		})
+	}
+	return events
+}
+
+// enqueueWebhooks - イベントをWebhookの送信待ちに追加し、失敗した場合は警告を記録する
+func enqueueWebhooks(cfg *config.Config, events []webhooks.Event) {
+	if len(events) == 0 || !webhooksDelivered(cfg) {
+		return
+	}
+	if err := webhooks.Enqueue(cfg, events); err != nil {
+		logger.Warn("Webhookの送信待ちへの追加に失敗しました", "error", err)
+	}
+}
+
+// webhooksDelivered - Webhookを設定していて、プロジェクトを監視して送信するプロセスがあるかどうかを返す
+// 送信するプロセスがない場合は送信待ちに追加しない（送信されない配信が溜まり続けないようにする）
+func webhooksDelivered(cfg *config.Config) bool {
+	if len(cfg.Webhooks) == 0 {
+		return false
+	}
+
+	projectsDir := utils.AbsPath(cfg.ProjectsDir)
+	watching := func(projects []string) bool {
+		for _, project := range projects {
+			if filepath.Clean(project) == projectsDir {
+				return true
+			}
+		}
+		return false
+	}
+
+	// このプロセスで監視している場合（監視中のsync）
+	if watching(watcher.GetManager().GetWatchingProjects()) {
+		return true
+	}
+	// 別の監視プロセスで監視している場合
+	resp, err := watcher.SendControlRequest(stateDirFor(cfg), watcher.ControlRequest{Action: watcher.ActionList})
+	return err == nil && watching(resp.Projects)
+}
+
+// webhookIssue - Webhookで送信するIssueの内容
+func webhookIssue(issue *models.Issue) *webhooks.Issue {
+	return &webhooks.Issue{ID: issue.ID, Title: issue.Title, Status: issue.Status, Epic: issue.Epic, Estimate: issue.Estimate}
+}
+
+// itemIDs - order.csvの行のIDを並び順に返す
+func itemIDs(items []models.OrderCSVItem) []int {
+	ids := make([]int, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+	return ids
+}
@@ -1,34 +1,698 @@
 package config
 
 import (
+	"crypto/sha256"
+	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/moai/instant-backlog/internal/logger"
+	"github.com/moai/instant-backlog/internal/models"
+	"github.com/moai/instant-backlog/pkg/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// FileName - プロジェクト設定ファイルの名前
+const FileName = ".instant-backlog.yaml"
+
+// DefaultDebounce - ファイル監視のデフォルトのデバウンス時間
+const DefaultDebounce = 500 * time.Millisecond
+
+// DefaultHookTimeout - フックの実行時間のデフォルトの上限
+const DefaultHookTimeout = 30 * time.Second
+
+// 設定を上書きする環境変数
+const (
+	EnvProject      = "IB_PROJECT"       // 設定ファイルの探索を開始するディレクトリ（--project と同じ）
+	EnvConfig       = "IB_CONFIG"        // 設定ファイルのパス（探索せずにこのファイルを使用）
+	EnvProjectsDir  = "IB_PROJECTS_DIR"  // projectsディレクトリ
+	EnvEpicDir      = "IB_EPIC_DIR"      // epicディレクトリ
+	EnvIssuesDir    = "IB_ISSUES_DIR"    // issuesディレクトリ
+	EnvOrderCSV     = "IB_ORDER_CSV"     // order.csvのパス
+	EnvTemplatePath = "IB_TEMPLATE_PATH" // テンプレートディレクトリ
+	EnvDebounce     = "IB_DEBOUNCE"      // ファイル監視のデバウンス時間
+	EnvStateDir     = "IB_STATE_DIR"     // 監視プロセスの状態ディレクトリ
+	EnvOnConflict   = "IB_ON_CONFLICT"   // ファイル名が衝突した場合の処理方法
+	EnvPlacement    = "IB_PLACEMENT"     // 新しいIssueをorder.csvに追加する位置
+	EnvOrdering     = "IB_ORDERING"      // Issueの並び順の管理方法
+	EnvHookTimeout  = "IB_HOOK_TIMEOUT"  // フックの実行時間の上限
+)
+
+// ConflictPolicy - リネーム先のファイルが既に存在する場合の処理方法
+type ConflictPolicy string
+
+const (
+	// ConflictSkip - リネームせずにエラーとして報告する（デフォルト）
+	ConflictSkip ConflictPolicy = "skip"
+	// ConflictMove - リネーム元のファイルをconflictsディレクトリに退避する
+	ConflictMove ConflictPolicy = "conflicts"
+	// ConflictMerge - リネーム元の本文をリネーム先に追記してまとめる
+	ConflictMerge ConflictPolicy = "merge"
+)
+
+// ConflictsDirName - 衝突したファイルを退避するディレクトリの名前（projectsディレクトリ配下）
+const ConflictsDirName = "conflicts"
+
+// ParseConflictPolicy - 文字列から衝突時の処理方法を取得する
+func ParseConflictPolicy(value string) (ConflictPolicy, error) {
+	switch policy := ConflictPolicy(strings.ToLower(strings.TrimSpace(value))); policy {
+	case ConflictSkip, ConflictMove, ConflictMerge:
+		return policy, nil
+	}
+	return "", fmt.Errorf("不明な衝突時の処理方法です: %s（skip, conflicts, merge のいずれかを指定してください）", value)
+}
+
+// PlacementPolicy - 新しいIssueをorder.csvに追加する位置
+type PlacementPolicy string
+
+const (
+	// PlacementBottom - 末尾に追加する（デフォルト）
+	PlacementBottom PlacementPolicy = "bottom"
+	// PlacementTop - 先頭に追加する
+	PlacementTop PlacementPolicy = "top"
+	// PlacementEpic - 同じEpicの最後のIssueの直後に追加する（同じEpicがなければ末尾）
+	PlacementEpic PlacementPolicy = "epic"
+	// PlacementPriority - Front Matterのpriority（小さいほど優先）の順になる位置に追加する
+	PlacementPriority PlacementPolicy = "priority"
+)
+
+// ParsePlacementPolicy - 文字列から新しいIssueを追加する位置を取得する
+func ParsePlacementPolicy(value string) (PlacementPolicy, error) {
+	switch policy := PlacementPolicy(strings.ToLower(strings.TrimSpace(value))); policy {
+	case PlacementBottom, PlacementTop, PlacementEpic, PlacementPriority:
+		return policy, nil
+	}
+	return "", fmt.Errorf("不明な追加位置です: %s（bottom, top, epic, priority のいずれかを指定してください）", value)
+}
+
+// OrderingMode - Issueの並び順の管理方法
+type OrderingMode string
+
+const (
+	// OrderingCSV - order.csvの行の順序で管理する（デフォルト）
+	OrderingCSV OrderingMode = "csv"
+	// OrderingRank - 各IssueのFront Matterのrankで管理し、order.csvはsyncで生成する
+	OrderingRank OrderingMode = "rank"
 )
 
+// ParseOrderingMode - 文字列から並び順の管理方法を取得する
+func ParseOrderingMode(value string) (OrderingMode, error) {
+	switch mode := OrderingMode(strings.ToLower(strings.TrimSpace(value))); mode {
+	case OrderingCSV, OrderingRank:
+		return mode, nil
+	}
+	return "", fmt.Errorf("不明な並び順の管理方法です: %s（csv, rank のいずれかを指定してください）", value)
+}
+
+// フックを実行するイベント
+const (
+	HookIssueCreated = "on_issue_created" // 未完了のIssueがorder.csvに追加された
+	HookIssueClosed  = "on_issue_closed"  // order.csvにあったIssueが完了扱いになった
+	HookEpicClosed   = "on_epic_closed"   // 紐づくIssueがすべて完了してEpicが完了になった
+	HookAfterSync    = "after_sync"       // syncが完了した
+)
+
+// HookEvents - フックを設定できるイベントの一覧
+var HookEvents = []string{HookIssueCreated, HookIssueClosed, HookEpicClosed, HookAfterSync}
+
+// Webhookで送信するイベント
+const (
+	WebhookIssueCreated = "issue.created" // 未完了のIssueがorder.csvに追加された
+	WebhookIssueUpdated = "issue.updated" // Issueの内容がorder.csvに反映された、またはorder.csvの変更がIssueに書き戻された
+	WebhookIssueClosed  = "issue.closed"  // order.csvにあったIssueが完了扱いになった
+	WebhookEpicClosed   = "epic.closed"   // 紐づくIssueがすべて完了してEpicが完了になった
+	WebhookOrderChanged = "order.changed" // order.csvのIssueの並び順が変わった
+)
+
+// WebhookEvents - Webhookで送信できるイベントの一覧
+var WebhookEvents = []string{WebhookIssueCreated, WebhookIssueUpdated, WebhookIssueClosed, WebhookEpicClosed, WebhookOrderChanged}
+
+// WebhookQueueDirName - 送信待ちのWebhookを保存するディレクトリの名前（状態ディレクトリ配下）
+const WebhookQueueDirName = "webhooks"
+
+// SyncBaseDirName - 前回のsyncの内容を保存するディレクトリの名前（状態ディレクトリ配下）
+const SyncBaseDirName = "sync"
+
+// Webhook - イベントを送信するHTTPエンドポイントの設定
+type Webhook struct {
+	URL string `yaml:"url"`
+	// 署名（X-Backlog-Signature-256）に使用する秘密鍵
+	Secret string `yaml:"secret"`
+	// 秘密鍵を読み込む環境変数の名前（設定ファイルに秘密鍵を書かない場合に使用）
+	SecretEnv string `yaml:"secret_env"`
+	// 送信するイベント（未指定の場合はすべて）
+	Events []string `yaml:"events"`
+}
+
+// SigningSecret - 署名に使用する秘密鍵を返す（secret_envの場合は送信時に環境変数から読み込む）
+// secret_envの環境変数が設定されていない場合はfalseを返す
+func (w Webhook) SigningSecret() (string, bool) {
+	if w.SecretEnv == "" {
+		return w.Secret, true
+	}
+	secret := os.Getenv(w.SecretEnv)
+	return secret, secret != ""
+}
+
+// Accepts - イベントを送信する対象かどうかを返す
+func (w Webhook) Accepts(event string) bool {
+	return len(w.Events) == 0 || slices.Contains(w.Events, event)
+}
+
+// HookCommands - フックで実行するコマンドの一覧（設定ファイルでは1つのコマンドを文字列でも指定できる）
+type HookCommands []string
+
+// UnmarshalYAML - 文字列またはリストからコマンドの一覧を読み込む
+func (h *HookCommands) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*h = HookCommands{node.Value}
+		return nil
+	}
+	var commands []string
+	if err := node.Decode(&commands); err != nil {
+		return err
+	}
+	*h = commands
+	return nil
+}
+
 // Config - アプリケーション設定を表す構造体
 type Config struct {
+	// プロジェクトのルートディレクトリ（設定ファイルのあるディレクトリ、なければカレントディレクトリ）
+	BaseDir string
+	// 読み込んだ設定ファイルのパス（設定ファイルがない場合は空）
+	ConfigFile string
+
 	ProjectsDir string
 	EpicDir     string
 	IssuesDir   string
 	OrderCSV    string
 	// テンプレートディレクトリのパス
 	TemplatePath string
+	// ファイル監視のデバウンス時間（未設定の場合は500ミリ秒）
+	Debounce time.Duration
+	// 監視プロセスの状態ディレクトリ（未設定の場合は既定の場所）
+	StateDir string
+	// リネーム先のファイルが既に存在する場合の処理方法（未設定の場合はskip）
+	OnConflict ConflictPolicy
+	// 新しいIssueをorder.csvに追加する位置（未設定の場合はbottom）
+	Placement PlacementPolicy
+	// Issueの並び順の管理方法（未設定の場合はcsv）
+	Ordering OrderingMode
+	// ワークフローで使用するステータスの一覧（未設定の場合はOpen/Close）
+	Statuses models.StatusSet
+	// order.csvに追加するFront Matterのフィールド（status, assignee, labels, sprint など）
+	OrderColumns []string
+	// イベントごとに実行するフックのコマンド
+	Hooks map[string]HookCommands
+	// フックの実行時間の上限（未設定の場合は30秒）
+	HookTimeout time.Duration
+	// イベントを送信するWebhook
+	Webhooks []Webhook
+}
+
+// fileConfig - 設定ファイルの内容を表す構造体
+// パスは相対パスの場合、projects_dir と template_path, state_dir はベースディレクトリ、
+// epic_dir, issues_dir, order_csv はprojectsディレクトリからの相対パスとして扱う
+type fileConfig struct {
+	ProjectsDir  string                  `yaml:"projects_dir"`
+	EpicDir      string                  `yaml:"epic_dir"`
+	IssuesDir    string                  `yaml:"issues_dir"`
+	OrderCSV     string                  `yaml:"order_csv"`
+	TemplatePath string                  `yaml:"template_path"`
+	Debounce     string                  `yaml:"debounce"`
+	StateDir     string                  `yaml:"state_dir"`
+	OnConflict   string                  `yaml:"on_conflict"`
+	Placement    string                  `yaml:"placement"`
+	Ordering     string                  `yaml:"ordering"`
+	Statuses     models.StatusSet        `yaml:"statuses"`
+	OrderColumns []string                `yaml:"order_columns"`
+	Hooks        map[string]HookCommands `yaml:"hooks"`
+	HookTimeout  string                  `yaml:"hook_timeout"`
+	Webhooks     []Webhook               `yaml:"webhooks"`
+}
+
+// LoadOptions - 設定の読み込みオプション
+type LoadOptions struct {
+	// 設定ファイルの探索を開始するディレクトリ（空の場合は IB_PROJECT → カレントディレクトリ）
+	ProjectDir string
 }
 
-// NewConfig - デフォルト設定で設定構造体を作成
+// NewConfig - カレントディレクトリから設定を読み込んで設定構造体を作成
+// 読み込みに失敗した場合はデフォルト設定を使用する
 func NewConfig() *Config {
-	// ベースディレクトリの取得
-	baseDir, err := os.Getwd()
+	cfg, err := Load(LoadOptions{})
+	if err != nil {
+		logger.Warn("設定ファイルの読み込みに失敗しました。デフォルト設定を使用します", "error", err)
+		baseDir, err := os.Getwd()
+		if err != nil {
+			baseDir = "."
+		}
+		return defaultConfig(baseDir)
+	}
+
+	return cfg
+}
+
+// Load - 設定ファイルを探索して設定構造体を作成
+// 探索開始ディレクトリから親ディレクトリへ遡って設定ファイルを探し、
+// 見つかったディレクトリをベースディレクトリとする。設定は
+// デフォルト値 → 設定ファイル → 環境変数(IB_*) の順に上書きされる
+func Load(opts LoadOptions) (*Config, error) {
+	startDir := opts.ProjectDir
+	if startDir == "" {
+		startDir = os.Getenv(EnvProject)
+	}
+	if startDir == "" {
+		currentDir, err := os.Getwd()
+		if err != nil {
+			return nil, fmt.Errorf("カレントディレクトリの取得に失敗しました: %w", err)
+		}
+		startDir = currentDir
+	}
+
+	startDir, err := filepath.Abs(startDir)
+	if err != nil {
+		return nil, fmt.Errorf("プロジェクトパスの解決に失敗しました: %w", err)
+	}
+	if opts.ProjectDir != "" {
+		if info, err := os.Stat(startDir); err != nil || !info.IsDir() {
+			return nil, fmt.Errorf("指定されたプロジェクトディレクトリが存在しません: %s", startDir)
+		}
+	}
+
+	// 設定ファイルの決定（IB_CONFIG が指定されていればそれを使用）
+	configFile := os.Getenv(EnvConfig)
+	if configFile != "" {
+		if configFile, err = filepath.Abs(configFile); err != nil {
+			return nil, fmt.Errorf("設定ファイルのパスの解決に失敗しました: %w", err)
+		}
+		if _, err := os.Stat(configFile); err != nil {
+			return nil, fmt.Errorf("%s で指定された設定ファイルが見つかりません: %s", EnvConfig, configFile)
+		}
+	} else {
+		configFile = FindConfigFile(startDir)
+	}
+
+	baseDir := startDir
+	if configFile != "" {
+		baseDir = filepath.Dir(configFile)
+	}
+	return load(baseDir, configFile)
+}
+
+// LoadConfig - 指定ディレクトリの設定ファイルを読み込んで設定構造体を作成
+// 設定ファイルが存在しない場合はデフォルト設定に環境変数を反映して返す
+func LoadConfig(baseDir string) (*Config, error) {
+	configFile := filepath.Join(baseDir, FileName)
+	if _, err := os.Stat(configFile); err != nil {
+		configFile = ""
+	}
+	return load(baseDir, configFile)
+}
+
+// ForProject - 指定したprojectsディレクトリ用の設定を作成
+// 設定ファイルはprojectsディレクトリの親ディレクトリから遡って探索する
+func ForProject(projectsDir string) (*Config, error) {
+	projectsDir, err := filepath.Abs(projectsDir)
+	if err != nil {
+		return nil, fmt.Errorf("プロジェクトパスの解決に失敗しました: %w", err)
+	}
+
+	baseDir := filepath.Dir(projectsDir)
+	configFile := FindConfigFile(baseDir)
+	if configFile != "" {
+		baseDir = filepath.Dir(configFile)
+	}
+
+	cfg, err := load(baseDir, configFile)
+	if err != nil {
+		return nil, err
+	}
+
+	// projectsディレクトリ配下のパスは指定されたディレクトリに付け替える
+	cfg.EpicDir = rebase(cfg.EpicDir, cfg.ProjectsDir, projectsDir)
+	cfg.IssuesDir = rebase(cfg.IssuesDir, cfg.ProjectsDir, projectsDir)
+	cfg.OrderCSV = rebase(cfg.OrderCSV, cfg.ProjectsDir, projectsDir)
+	cfg.ProjectsDir = projectsDir
+	return cfg, nil
+}
+
+// FindConfigFile - 指定ディレクトリから親ディレクトリへ遡って設定ファイルを探す
+// 見つからない場合は空文字列を返す
+func FindConfigFile(startDir string) string {
+	dir := startDir
+	for {
+		configPath := filepath.Join(dir, FileName)
+		if info, err := os.Stat(configPath); err == nil && !info.IsDir() {
+			return configPath
+		}
+
+		// 親ディレクトリに移動
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			// これ以上遡れない
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// StatusSet - 使用するステータス一覧を返す（未設定の場合はデフォルト）
+func (c *Config) StatusSet() models.StatusSet {
+	if len(c.Statuses) == 0 {
+		return models.DefaultStatusSet()
+	}
+	return c.Statuses
+}
+
+// DebounceTime - ファイル監視のデバウンス時間を返す（未設定の場合はデフォルト）
+func (c *Config) DebounceTime() time.Duration {
+	if c.Debounce <= 0 {
+		return DefaultDebounce
+	}
+	return c.Debounce
+}
+
+// ConflictPolicy - ファイル名が衝突した場合の処理方法を返す（未設定の場合はskip）
+func (c *Config) ConflictPolicy() ConflictPolicy {
+	if c.OnConflict == "" {
+		return ConflictSkip
+	}
+	return c.OnConflict
+}
+
+// PlacementPolicy - 新しいIssueをorder.csvに追加する位置を返す（未設定の場合はbottom）
+func (c *Config) PlacementPolicy() PlacementPolicy {
+	if c.Placement == "" {
+		return PlacementBottom
+	}
+	return c.Placement
+}
+
+// OrderingMode - Issueの並び順の管理方法を返す（未設定の場合はcsv）
+func (c *Config) OrderingMode() OrderingMode {
+	if c.Ordering == "" {
+		return OrderingCSV
+	}
+	return c.Ordering
+}
+
+// HookTimeLimit - フックの実行時間の上限を返す（未設定の場合はデフォルト）
+func (c *Config) HookTimeLimit() time.Duration {
+	if c.HookTimeout <= 0 {
+		return DefaultHookTimeout
+	}
+	return c.HookTimeout
+}
+
+// SyncBaseFile - 前回のsyncでorder.csvに書き込んだ内容を保存するファイルを返す
+// order.csvとIssueファイルのどちらが変更されたかを判定するために使用する。
+// 作業環境ごとの状態のため、gitで管理するprojectsディレクトリではなく状態ディレクトリ配下に保存する
+func (c *Config) SyncBaseFile() string {
+	return filepath.Join(c.projectStateDir(SyncBaseDirName), filepath.Base(c.OrderCSV)+".base")
+}
+
+// KnownIssuesFile - 前回のsyncの時点で存在したIssueのIDを保存するファイルを返す
+// 再オープンしたIssueや手でorder.csvから削除した行を、新しいIssueとして扱わないために使用する
+func (c *Config) KnownIssuesFile() string {
+	return filepath.Join(c.projectStateDir(SyncBaseDirName), "known_issues")
+}
+
+// ConflictsDir - 衝突したファイルを退避するディレクトリを返す
+func (c *Config) ConflictsDir() string {
+	return filepath.Join(c.ProjectsDir, ConflictsDirName)
+}
+
+// StateDirectory - 監視プロセスの状態ディレクトリを返す（未設定の場合はデフォルト）
+func (c *Config) StateDirectory() string {
+	if c.StateDir != "" {
+		return c.StateDir
+	}
+	return DefaultStateDir()
+}
+
+// WebhookQueueDir - 送信待ちのWebhookを保存するディレクトリを返す
+// gitで管理するprojectsディレクトリに置かないよう、状態ディレクトリ配下にプロジェクトごとに作成する
+func (c *Config) WebhookQueueDir() string {
+	return c.projectStateDir(WebhookQueueDirName)
+}
+
+// projectStateDir - 状態ディレクトリのkind配下に、プロジェクトごとのディレクトリのパスを返す
+// ディレクトリ名は「プロジェクトのディレクトリ名-projectsディレクトリの絶対パスのハッシュ」
+func (c *Config) projectStateDir(kind string) string {
+	projectsDir := utils.AbsPath(c.ProjectsDir)
+	sum := sha256.Sum256([]byte(projectsDir))
+	name := fmt.Sprintf("%s-%x", filepath.Base(filepath.Dir(projectsDir)), sum[:4])
+	return filepath.Join(c.StateDirectory(), kind, name)
+}
+
+// DefaultStateDir - 監視プロセスの状態ディレクトリのデフォルトを返す
+// 優先順位: IB_STATE_DIR → $XDG_STATE_HOME/instant-backlog → ~/.local/state/instant-backlog
+func DefaultStateDir() string {
+	if dir := os.Getenv(EnvStateDir); dir != "" {
+		return dir
+	}
+	if dir := os.Getenv("XDG_STATE_HOME"); dir != "" {
+		return filepath.Join(dir, "instant-backlog")
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		return filepath.Join(home, ".local", "state", "instant-backlog")
+	}
+	return filepath.Join(os.TempDir(), fmt.Sprintf("instant-backlog-%d", os.Getuid()))
+}
+
+// load - 設定ファイルと環境変数を反映した設定構造体を作成
+func load(baseDir, configFile string) (*Config, error) {
+	var fc fileConfig
+	if configFile != "" {
+		data, err := os.ReadFile(configFile)
+		if err != nil {
+			return nil, err
+		}
+		if err := yaml.Unmarshal(data, &fc); err != nil {
+			return nil, fmt.Errorf("%s の解析に失敗しました: %w", configFile, err)
+		}
+	}
+
+	overrideFromEnv(&fc)
+
+	cfg := defaultConfig(baseDir)
+	cfg.ConfigFile = configFile
+
+	if fc.ProjectsDir != "" {
+		cfg.ProjectsDir = resolvePath(baseDir, fc.ProjectsDir)
+	}
+	cfg.EpicDir = resolvePath(cfg.ProjectsDir, orDefault(fc.EpicDir, "epic"))
+	cfg.IssuesDir = resolvePath(cfg.ProjectsDir, orDefault(fc.IssuesDir, "issues"))
+	cfg.OrderCSV = resolvePath(cfg.ProjectsDir, orDefault(fc.OrderCSV, "order.csv"))
+	if fc.TemplatePath != "" {
+		cfg.TemplatePath = resolvePath(baseDir, fc.TemplatePath)
+	}
+	if fc.StateDir != "" {
+		cfg.StateDir = resolvePath(baseDir, fc.StateDir)
+	}
+
+	if fc.Debounce != "" {
+		debounce, err := parseDuration(fc.Debounce)
+		if err != nil {
+			return nil, fmt.Errorf("debounce の値が不正です: %w", err)
+		}
+		cfg.Debounce = debounce
+	}
+
+	if fc.OnConflict != "" {
+		policy, err := ParseConflictPolicy(fc.OnConflict)
+		if err != nil {
+			return nil, fmt.Errorf("on_conflict の値が不正です: %w", err)
+		}
+		cfg.OnConflict = policy
+	}
+
+	if fc.Placement != "" {
+		policy, err := ParsePlacementPolicy(fc.Placement)
+		if err != nil {
+			return nil, fmt.Errorf("placement の値が不正です: %w", err)
+		}
+		cfg.Placement = policy
+	}
+
+	if fc.Ordering != "" {
+		mode, err := ParseOrderingMode(fc.Ordering)
+		if err != nil {
+			return nil, fmt.Errorf("ordering の値が不正です: %w", err)
+		}
+		cfg.Ordering = mode
+	}
+
+	if len(fc.Statuses) > 0 {
+		if err := fc.Statuses.Validate(); err != nil {
+			return nil, fmt.Errorf("ステータス定義が不正です: %w", err)
+		}
+		cfg.Statuses = fc.Statuses
+	}
+
+	if err := validateOrderColumns(fc.OrderColumns); err != nil {
+		return nil, fmt.Errorf("order_columns の値が不正です: %w", err)
+	}
+	cfg.OrderColumns = fc.OrderColumns
+
+	for event, commands := range fc.Hooks {
+		if !slices.Contains(HookEvents, event) {
+			return nil, fmt.Errorf("hooks の値が不正です: 不明なイベントです: %s（%s のいずれかを指定してください）", event, strings.Join(HookEvents, ", "))
+		}
+		for _, command := range commands {
+			if strings.TrimSpace(command) == "" {
+				return nil, fmt.Errorf("hooks の値が不正です: %s のコマンドが空です", event)
+			}
+		}
+	}
+	cfg.Hooks = fc.Hooks
+
+	if fc.HookTimeout != "" {
+		timeout, err := parseDuration(fc.HookTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("hook_timeout の値が不正です: %w", err)
+		}
+		cfg.HookTimeout = timeout
+	}
+
+	for i, webhook := range fc.Webhooks {
+		if err := validateWebhook(webhook); err != nil {
+			return nil, fmt.Errorf("webhooks[%d] の値が不正です: %w", i, err)
+		}
+	}
+	cfg.Webhooks = fc.Webhooks
+
+	return cfg, nil
+}
+
+// validateOrderColumns - order.csvに追加する列の設定を検証する
+func validateOrderColumns(columns []string) error {
+	seen := make(map[string]bool)
+	for _, column := range models.OrderCSVColumns {
+		seen[column] = true
+	}
+	for _, column := range columns {
+		if strings.TrimSpace(column) == "" {
+			return fmt.Errorf("列名は必須です")
+		}
+		if seen[column] {
+			return fmt.Errorf("列 '%s' が重複しています", column)
+		}
+		seen[column] = true
+	}
+	return nil
+}
+
+// validateWebhook - Webhookの設定を検証する
+func validateWebhook(webhook Webhook) error {
+	parsed, err := url.Parse(webhook.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("url はhttpまたはhttpsのURLを指定してください: %q", webhook.URL)
+	}
+	for _, event := range webhook.Events {
+		if !slices.Contains(WebhookEvents, event) {
+			return fmt.Errorf("不明なイベントです: %s（%s のいずれかを指定してください）", event, strings.Join(WebhookEvents, ", "))
+		}
+	}
+	if webhook.Secret != "" && webhook.SecretEnv != "" {
+		return fmt.Errorf("secret と secret_env は同時に指定できません")
+	}
+	return nil
+}
+
+// overrideFromEnv - 環境変数(IB_*)で設定ファイルの値を上書きする
+func overrideFromEnv(fc *fileConfig) {
+	overrides := []struct {
+		name  string
+		value *string
+	}{
+		{EnvProjectsDir, &fc.ProjectsDir},
+		{EnvEpicDir, &fc.EpicDir},
+		{EnvIssuesDir, &fc.IssuesDir},
+		{EnvOrderCSV, &fc.OrderCSV},
+		{EnvTemplatePath, &fc.TemplatePath},
+		{EnvDebounce, &fc.Debounce},
+		{EnvStateDir, &fc.StateDir},
+		{EnvOnConflict, &fc.OnConflict},
+		{EnvPlacement, &fc.Placement},
+		{EnvOrdering, &fc.Ordering},
+		{EnvHookTimeout, &fc.HookTimeout},
+	}
+
+	for _, o := range overrides {
+		if value := os.Getenv(o.name); value != "" {
+			*o.value = value
+		}
+	}
+}
+
+// parseDuration - 時間を解析する（"500ms" などの形式、または数値のみの場合はミリ秒）
+func parseDuration(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if ms, err := strconv.Atoi(value); err == nil {
+		if ms < 0 {
+			return 0, fmt.Errorf("負の値は指定できません: %s", value)
+		}
+		return time.Duration(ms) * time.Millisecond, nil
+	}
+
+	d, err := time.ParseDuration(value)
 	if err != nil {
-		baseDir = "."
+		return 0, err
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("負の値は指定できません: %s", value)
 	}
+	return d, nil
+}
+
+// resolvePath - 相対パスを基準ディレクトリからの絶対パスに変換する
+func resolvePath(baseDir, path string) string {
+	if strings.HasPrefix(path, "~"+string(filepath.Separator)) || path == "~" {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, path[1:])
+		}
+	}
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path)
+	}
+	return filepath.Join(baseDir, path)
+}
+
+// rebase - oldBase配下のパスをnewBase配下に付け替える（配下でない場合はそのまま）
+func rebase(path, oldBase, newBase string) string {
+	rel, err := filepath.Rel(oldBase, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return path
+	}
+	return filepath.Join(newBase, rel)
+}
+
+// orDefault - 値が空の場合はデフォルト値を返す
+func orDefault(value, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
 
+// defaultConfig - ベースディレクトリからデフォルト設定を作成
+func defaultConfig(baseDir string) *Config {
 	projectsDir := filepath.Join(baseDir, "projects")
 
 	return &Config{
+		BaseDir:     baseDir,
 		ProjectsDir: projectsDir,
 		EpicDir:     filepath.Join(projectsDir, "epic"),
 		IssuesDir:   filepath.Join(projectsDir, "issues"),
 		OrderCSV:    filepath.Join(projectsDir, "order.csv"),
+		Statuses:    models.DefaultStatusSet(),
 	}
 }
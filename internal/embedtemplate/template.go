@@ -8,6 +8,8 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+
+	"github.com/moai/instant-backlog/pkg/utils"
 )
 
 //go:embed "template/projects"
@@ -47,6 +49,6 @@ func ExtractTemplate(targetDir string) error {
 		}
 
 		// ファイルを書き込み
-		return os.WriteFile(targetPath, data, 0644)
+		return utils.WriteFileAtomic(targetPath, data, 0644)
 	})
 }
@@ -0,0 +1,167 @@
+package fileops
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/moai/instant-backlog/internal/config"
+	"github.com/moai/instant-backlog/internal/logger"
+	"github.com/moai/instant-backlog/internal/parser"
+	"github.com/moai/instant-backlog/pkg/utils"
+)
+
+// CollisionError - リネーム先のファイルが既に存在するためリネームしなかったことを表すエラー
+type CollisionError struct {
+	Source string // リネーム元のファイル
+	Target string // 既に存在するリネーム先のファイル
+}
+
+func (e *CollisionError) Error() string {
+	return fmt.Sprintf("リネーム先のファイルが既に存在するためスキップしました: %s → %s", filepath.Base(e.Source), filepath.Base(e.Target))
+}
+
+// RenameWithPolicy - ファイルをリネームする。リネーム先が既に存在する場合は方針に従って処理する
+// どの方針でもファイルの内容が失われることはない
+//   - 内容が同一の場合: リネーム元は重複ファイルとして削除する
+//   - skip: 何もせず CollisionError を返す
+//   - conflicts: リネーム元を conflictsDir に退避する
+//   - merge: リネーム元の本文をリネーム先に追記する（Front Matterが異なる場合はリネーム元も退避する）
+//
+// 大文字・小文字を区別しないファイルシステムでの大文字・小文字のみの変更など、
+// リネーム先がリネーム元と同じファイルを指す場合は衝突として扱わずにそのままリネームする
+func RenameWithPolicy(source, target string, policy config.ConflictPolicy, conflictsDir string) error {
+	if isSameFile(source, target) {
+		utils.RecordSelfRename(source, target)
+		if err := os.Rename(source, target); err != nil {
+			return err
+		}
+		logger.Info("ファイル名を変更しました", "from", filepath.Base(source), "to", filepath.Base(target))
+		return nil
+	}
+
+	if _, err := os.Stat(target); os.IsNotExist(err) {
+		utils.RecordSelfRename(source, target)
+		err := utils.RenameNoReplace(source, target)
+		if err == nil {
+			logger.Info("ファイル名を変更しました", "from", filepath.Base(source), "to", filepath.Base(target))
+			return nil
+		}
+		// 確認した後に他のプロセスがリネーム先を作成した場合は衝突として処理する
+		if !errors.Is(err, fs.ErrExist) {
+			return err
+		}
+	}
+
+	sourceData, err := os.ReadFile(source)
+	if err != nil {
+		return err
+	}
+	targetData, err := os.ReadFile(target)
+	if err != nil {
+		return err
+	}
+
+	// 内容が同一なら重複ファイルなので削除しても失われるものはない
+	if bytes.Equal(sourceData, targetData) {
+		logger.Info("同じ内容のファイルが既に存在するため重複ファイルを削除しました", "file", filepath.Base(source), "target", filepath.Base(target))
+		utils.RecordSelfRemoval(source)
+		return os.Remove(source)
+	}
+
+	switch policy {
+	case config.ConflictMove:
+		moved, err := moveToConflicts(source, conflictsDir)
+		if err != nil {
+			return err
+		}
+		logger.Warn("リネーム先のファイルが既に存在するため退避しました", "file", filepath.Base(source), "target", filepath.Base(target), "moved_to", moved)
+		return nil
+
+	case config.ConflictMerge:
+		return mergeInto(source, target, sourceData, targetData, conflictsDir)
+
+	default:
+		return &CollisionError{Source: source, Target: target}
+	}
+}
+
+// mergeInto - リネーム元の本文をリネーム先に追記し、リネーム元を片付ける
+func mergeInto(source, target string, sourceData, targetData []byte, conflictsDir string) error {
+	sourceFM, sourceBody, sourceOK := parser.SplitFrontMatter(sourceData)
+	targetFM, targetBody, targetOK := parser.SplitFrontMatter(targetData)
+	if !sourceOK || !targetOK {
+		return fmt.Errorf("Front Matterを解析できないため統合できません: %s → %s", filepath.Base(source), filepath.Base(target))
+	}
+
+	// 本文が異なる場合のみ追記する
+	sourceText := strings.TrimSpace(string(sourceBody))
+	if sourceText != "" && !strings.Contains(string(targetBody), sourceText) {
+		var merged bytes.Buffer
+		merged.WriteString(strings.TrimRight(string(targetData), "\n"))
+		fmt.Fprintf(&merged, "\n\n<!-- %s から統合 -->\n\n", filepath.Base(source))
+		merged.WriteString(sourceText)
+		merged.WriteString("\n")
+
+		if err := utils.WriteFileAtomic(target, merged.Bytes(), 0644); err != nil {
+			return fmt.Errorf("ファイルの統合に失敗しました: %w", err)
+		}
+	}
+
+	// Front Matterが異なる場合は、その内容が失われないようリネーム元を退避する
+	if !bytes.Equal(bytes.TrimSpace(sourceFM), bytes.TrimSpace(targetFM)) {
+		moved, err := moveToConflicts(source, conflictsDir)
+		if err != nil {
+			return err
+		}
+		logger.Warn("本文を統合しました。Front Matterが異なるため統合元を退避しました", "file", filepath.Base(source), "target", filepath.Base(target), "moved_to", moved)
+		return nil
+	}
+
+	logger.Info("本文を統合しました", "file", filepath.Base(source), "target", filepath.Base(target))
+	utils.RecordSelfRemoval(source)
+	return os.Remove(source)
+}
+
+// moveToConflicts - ファイルをconflictsディレクトリ配下（元のディレクトリ名のサブディレクトリ）に移動する
+// 同名のファイルが既にある場合は連番を付ける
+func moveToConflicts(source, conflictsDir string) (string, error) {
+	dir := filepath.Join(conflictsDir, filepath.Base(filepath.Dir(source)))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("conflictsディレクトリの作成に失敗しました: %w", err)
+	}
+
+	name := filepath.Base(source)
+	ext := filepath.Ext(name)
+	stem := strings.TrimSuffix(name, ext)
+
+	utils.RecordSelfRemoval(source)
+	dest := filepath.Join(dir, name)
+	for i := 1; ; i++ {
+		err := utils.RenameNoReplace(source, dest)
+		if err == nil {
+			return dest, nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return "", fmt.Errorf("ファイルの退避に失敗しました: %w", err)
+		}
+		dest = filepath.Join(dir, fmt.Sprintf("%s.%d%s", stem, i, ext))
+	}
+}
+
+// isSameFile - 2つのパスが同じファイルを指しているかどうかを返す
+func isSameFile(a, b string) bool {
+	aInfo, err := os.Stat(a)
+	if err != nil {
+		return false
+	}
+	bInfo, err := os.Stat(b)
+	if err != nil {
+		return false
+	}
+	return os.SameFile(aInfo, bInfo)
+}
@@ -1,24 +1,32 @@
 package fileops
 
 import (
-	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 
+	"github.com/moai/instant-backlog/internal/logger"
 	"github.com/moai/instant-backlog/internal/models"
 	"github.com/moai/instant-backlog/internal/parser"
+	"github.com/moai/instant-backlog/pkg/utils"
 )
 
 // ReadAllIssues - 指定ディレクトリからすべてのIssueを読み込む
+// ステータスはデフォルトのOpen/Closeとして扱う
 func ReadAllIssues(directory string) ([]*models.Issue, error) {
+	return ReadAllIssuesWithStatuses(directory, models.DefaultStatusSet())
+}
+
+// ReadAllIssuesWithStatuses - ステータス定義に従って指定ディレクトリからすべてのIssueを読み込む
+func ReadAllIssuesWithStatuses(directory string, statuses models.StatusSet) ([]*models.Issue, error) {
 	files, err := os.ReadDir(directory)
 	if err != nil {
 		return nil, err
 	}
 
-	// ID別の最新Issueを管理するマップ
-	issueMap := make(map[int]*models.Issue)
-
+	var parsed []*models.Issue
 	for _, file := range files {
 		if file.IsDir() || filepath.Ext(file.Name()) != ".md" {
 			continue
@@ -27,36 +35,98 @@ func ReadAllIssues(directory string) ([]*models.Issue, error) {
 		filePath := filepath.Join(directory, file.Name())
 		issue, err := parser.ParseIssueFile(filePath)
 		if err != nil {
-			// エラーログを出力して続行することも可能
-			fmt.Printf("警告: Issueファイルの解析に失敗しました %s: %v\n", file.Name(), err)
+			// 警告を出力して続行
+			logger.Warn("Issueファイルの解析に失敗しました", "file", file.Name(), "error", err)
 			continue
 		}
+		parsed = append(parsed, issue)
+	}
+
+	return UniqueIssuesWithStatuses(parsed, statuses), nil
+}
+
+// DependencyProblem - blocked_byの問題（存在しない依存先への参照または循環参照）
+type DependencyProblem struct {
+	IssueID   int   // 問題のあるIssueのID（循環参照の場合は循環の先頭のIssue）
+	MissingID int   // 存在しない依存先のID（循環参照の場合は0）
+	Cycle     []int // 循環しているIssueのID（存在しない依存先の場合はnil）
+}
+
+// ReadAllIssuesWithProblems - ステータス定義に従ってすべてのIssueを読み込み、blocked_byの問題も返す
+// 読み込みでは警告を出力しないため、問題は呼び出し側で出力する（WarnDependencyProblems）
+func ReadAllIssuesWithProblems(directory string, statuses models.StatusSet) ([]*models.Issue, []DependencyProblem, error) {
+	issues, err := ReadAllIssuesWithStatuses(directory, statuses)
+	if err != nil {
+		return nil, nil, err
+	}
+	return issues, FindDependencyProblems(issues), nil
+}
 
+// FindDependencyProblems - Issueのblocked_byの存在しない依存先と循環参照を検出する
+func FindDependencyProblems(issues []*models.Issue) []DependencyProblem {
+	var problems []DependencyProblem
+	for _, dangling := range utils.FindDanglingDependencies(issues) {
+		problems = append(problems, DependencyProblem{IssueID: dangling.IssueID, MissingID: dangling.MissingID})
+	}
+	for _, cycle := range utils.FindDependencyCycles(issues) {
+		problems = append(problems, DependencyProblem{IssueID: cycle[0], Cycle: cycle})
+	}
+	return problems
+}
+
+// WarnDependencyProblems - blocked_byの問題を警告として出力する
+func WarnDependencyProblems(problems []DependencyProblem) {
+	for _, problem := range problems {
+		if problem.Cycle != nil {
+			logger.Warn("Issueの依存関係が循環しています", "cycle", FormatCycle(problem.Cycle))
+		} else {
+			logger.Warn("blocked_byに存在しないIssueが指定されています", "id", problem.IssueID, "blocked_by", problem.MissingID)
+		}
+	}
+}
+
+// UniqueIssuesWithStatuses - ファイル名順に読み込んだIssueから、IDごとに採用するIssueを選んでID順に返す
+func UniqueIssuesWithStatuses(parsed []*models.Issue, statuses models.StatusSet) []*models.Issue {
+	// ID別の最新Issueを管理するマップ
+	issueMap := make(map[int]*models.Issue)
+
+	for _, issue := range parsed {
 		// 既に同じIDのIssueが存在する場合は、最新のステータスを持つほうを採用
 		existingIssue, exists := issueMap[issue.ID]
 		if !exists {
 			issueMap[issue.ID] = issue
-			fmt.Printf("Issue ID=%d を登録しました (Status=%s)\n", issue.ID, issue.Status)
+			logger.Debug("Issueを登録しました", "id", issue.ID, "status", issue.Status)
 		} else {
 			// すでに同一IDが存在する場合は警告を出す
-			fmt.Printf("警告: Issue ID=%d が重複しています。現在: Status=%s, 新規: Status=%s\n",
-				issue.ID, existingIssue.Status, issue.Status)
-			// ファイル名からCloseが優先されるようにすると、より安全
-			if issue.Status == "Close" {
+			logger.Warn("Issue IDが重複しています", "id", issue.ID, "current", existingIssue.Status, "new", issue.Status)
+			// 完了扱いのステータスが優先されるようにすると、より安全
+			if statuses.IsDone(issue.Status) {
 				issueMap[issue.ID] = issue
-				fmt.Printf("更新: Issue ID=%d の最新ステータスとして %s を採用しました\n",
-					issue.ID, issue.Status)
+				logger.Debug("重複したIssueのうち完了扱いのステータスを採用しました", "id", issue.ID, "status", issue.Status)
 			}
 		}
 	}
 
-	// マップから配列に変換
+	// マップから配列に変換（読み込むたびに順序が変わらないようID順に並べる）
 	var issues []*models.Issue
 	for _, issue := range issueMap {
 		issues = append(issues, issue)
 	}
+	sort.Slice(issues, func(i, j int) bool { return issues[i].ID < issues[j].ID })
 
-	return issues, nil
+	return issues
+}
+
+// FormatCycle - 循環しているIssue IDを「1 → 2 → 1」の形式で表す
+func FormatCycle(cycle []int) string {
+	parts := make([]string, 0, len(cycle)+1)
+	for _, id := range cycle {
+		parts = append(parts, strconv.Itoa(id))
+	}
+	if len(cycle) > 0 {
+		parts = append(parts, strconv.Itoa(cycle[0]))
+	}
+	return strings.Join(parts, " → ")
 }
 
 // ReadAllEpics - 指定ディレクトリからすべてのEpicを読み込む
@@ -1,17 +1,27 @@
 package fileops
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 
+	"github.com/moai/instant-backlog/internal/config"
+	"github.com/moai/instant-backlog/internal/logger"
 	"github.com/moai/instant-backlog/internal/models"
 	"github.com/moai/instant-backlog/internal/parser"
 	"github.com/moai/instant-backlog/pkg/utils"
 )
 
 // WriteIssue - 指定されたIssueをマークダウンファイルに書き込む
+// ファイル名の頭文字はデフォルトのOpen/Closeとして生成する
 func WriteIssue(directory string, issue *models.Issue) error {
+	return WriteIssueWithStatuses(directory, models.DefaultStatusSet(), issue)
+}
+
+// WriteIssueWithStatuses - ステータス定義に従ってIssueをマークダウンファイルに書き込む
+func WriteIssueWithStatuses(directory string, statuses models.StatusSet, issue *models.Issue) error {
 	// マークダウンを生成
 	mdContent, err := parser.GenerateMarkdown(issue, issue.Content)
 	if err != nil {
@@ -19,18 +29,64 @@ func WriteIssue(directory string, issue *models.Issue) error {
 	}
 
 	// ファイル名を生成
-	filename := utils.GenerateFilename(issue.ID, issue.Status, issue.Title)
+	filename := utils.GenerateFilenameWithStatuses(statuses, issue.ID, issue.Status, issue.Title)
 	filePath := filepath.Join(directory, filename)
 
 	// ファイルに書き込み
-	err = os.WriteFile(filePath, mdContent, 0644)
-	fmt.Printf("WriteEpic: ファイル書き込み結果=%v\n", err)
-	return err
+	logger.Debug("Issueファイルを書き込みます", "file", filePath)
+	return utils.WriteFileAtomic(filePath, mdContent, 0644)
+}
+
+// UpdateIssueWithStatuses - 既存のIssueファイルをIssueの内容で更新する
+// ファイル名が規則どおりの場合は WriteIssueWithStatuses で書き込む。
+// ファイル名が規則どおりでない場合は同じIDのファイルを探して内容のみ更新し、ファイル名の変更はrenameに任せる
+func UpdateIssueWithStatuses(directory string, statuses models.StatusSet, issue *models.Issue) error {
+	path := FindIssueFile(directory, statuses, issue.ID, issue.Status, issue.Title)
+	expected := filepath.Join(directory, utils.GenerateFilenameWithStatuses(statuses, issue.ID, issue.Status, issue.Title))
+	if path == "" || path == expected {
+		return WriteIssueWithStatuses(directory, statuses, issue)
+	}
+
+	mdContent, err := parser.GenerateMarkdown(issue, issue.Content)
+	if err != nil {
+		return err
+	}
+	logger.Debug("Issueファイルを書き込みます", "file", path)
+	return utils.WriteFileAtomic(path, mdContent, 0644)
+}
+
+// FindIssueFile - Issueファイルのパスを探す（見つからない場合は空文字列）
+// 規則どおりのファイル名のファイルがあればそれを、なければ同じIDのファイルを返す
+func FindIssueFile(directory string, statuses models.StatusSet, id int, status, title string) string {
+	expected := filepath.Join(directory, utils.GenerateFilenameWithStatuses(statuses, id, status, title))
+	if _, err := os.Stat(expected); err == nil {
+		return expected
+	}
+
+	files, err := os.ReadDir(directory)
+	if err != nil {
+		return ""
+	}
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != ".md" {
+			continue
+		}
+		path := filepath.Join(directory, file.Name())
+		if issue, err := parser.ParseIssueFile(path); err == nil && issue.ID == id {
+			return path
+		}
+	}
+	return ""
 }
 
 // WriteEpic - 指定されたEpicをマークダウンファイルに書き込む
+// ファイル名の頭文字はデフォルトのOpen/Closeとして生成する
 func WriteEpic(directory string, epic *models.Epic) error {
-	fmt.Printf("WriteEpic: Epic ID=%d, Title=%s, Status=%s をファイルに書き込みます\n", epic.ID, epic.Title, epic.Status)
+	return WriteEpicWithStatuses(directory, models.DefaultStatusSet(), epic)
+}
+
+// WriteEpicWithStatuses - ステータス定義に従ってEpicをマークダウンファイルに書き込む
+func WriteEpicWithStatuses(directory string, statuses models.StatusSet, epic *models.Epic) error {
 	// マークダウンを生成
 	mdContent, err := parser.GenerateMarkdown(epic, epic.Content)
 	if err != nil {
@@ -38,17 +94,76 @@ func WriteEpic(directory string, epic *models.Epic) error {
 	}
 
 	// ファイル名を生成
-	filename := utils.GenerateFilename(epic.ID, epic.Status, epic.Title)
+	filename := utils.GenerateFilenameWithStatuses(statuses, epic.ID, epic.Status, epic.Title)
 	filePath := filepath.Join(directory, filename)
-	fmt.Printf("WriteEpic: 生成したファイル名=%s, ファイルパス=%s\n", filename, filePath)
 
 	// ファイルに書き込み
-	err = os.WriteFile(filePath, mdContent, 0644)
-	fmt.Printf("WriteEpic: ファイル書き込み結果=%v\n", err)
+	logger.Debug("Epicファイルを書き込みます", "file", filePath)
+	return utils.WriteFileAtomic(filePath, mdContent, 0644)
+}
+
+// ReplaceEpicWithStatuses - 既存のEpicファイルを更新後の内容とファイル名に置き換える
+// 内容の書き換えとファイル名の変更をそれぞれ原子的に行うため、
+// 同じEpicのファイルが一時的に2つ存在したり失われたりしない。
+// 新しいファイル名のファイルが既に存在する場合は内容のみ更新し、
+// ファイル名の変更はrenameの衝突処理に任せる
+func ReplaceEpicWithStatuses(directory string, statuses models.StatusSet, epic *models.Epic, oldFilename string) error {
+	mdContent, err := parser.GenerateMarkdown(epic, epic.Content)
+	if err != nil {
+		return err
+	}
+
+	oldPath := filepath.Join(directory, oldFilename)
+	newPath := filepath.Join(directory, utils.GenerateFilenameWithStatuses(statuses, epic.ID, epic.Status, epic.Title))
+
+	// 旧ファイル名が規則どおりでない場合はIDで既存ファイルを探す
+	if _, err := os.Stat(oldPath); os.IsNotExist(err) {
+		oldPath = findEpicFile(directory, epic.ID)
+	}
+
+	_, statErr := os.Stat(newPath)
+	newExists := statErr == nil
+	switch {
+	case oldPath == "" && newExists:
+		return fmt.Errorf("Epic ID=%d のファイルが見つからず、%s は別のファイルとして存在します", epic.ID, filepath.Base(newPath))
+	case oldPath == "":
+		logger.Debug("Epicファイルを書き込みます", "file", newPath)
+		return utils.WriteFileAtomic(newPath, mdContent, 0644)
+	case newExists && oldPath != newPath:
+		logger.Debug("リネーム先が既に存在するため内容のみ更新します", "file", oldPath, "target", newPath)
+		return utils.WriteFileAtomic(oldPath, mdContent, 0644)
+	}
+
+	logger.Debug("Epicファイルを置き換えます", "from", oldPath, "to", newPath)
+	err = utils.ReplaceFileAtomic(oldPath, newPath, mdContent, 0644)
+	if errors.Is(err, fs.ErrExist) {
+		// 確認した後にリネーム先が作成された場合も、内容のみ更新してrenameの衝突処理に任せる
+		logger.Debug("リネーム先が既に存在するため内容のみ更新します", "file", oldPath, "target", newPath)
+		return nil
+	}
 	return err
 }
 
+// findEpicFile - 指定したIDのEpicファイルのパスを探す（見つからない場合は空文字列）
+func findEpicFile(directory string, id int) string {
+	files, err := os.ReadDir(directory)
+	if err != nil {
+		return ""
+	}
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != ".md" {
+			continue
+		}
+		path := filepath.Join(directory, file.Name())
+		if epic, err := parser.ParseEpicFile(path); err == nil && epic.ID == id {
+			return path
+		}
+	}
+	return ""
+}
+
 // RenameFile - ファイル名を変更する（新しいファイル名が必要な場合）
+// 新しいファイル名のファイルが既に存在する場合は上書きせず CollisionError を返す
 func RenameFile(directory, oldFilename, newFilename string) error {
 	if oldFilename == newFilename {
 		return nil // 変更不要
@@ -57,5 +172,5 @@ func RenameFile(directory, oldFilename, newFilename string) error {
 	oldPath := filepath.Join(directory, oldFilename)
 	newPath := filepath.Join(directory, newFilename)
 
-	return os.Rename(oldPath, newPath)
+	return RenameWithPolicy(oldPath, newPath, config.ConflictSkip, "")
 }
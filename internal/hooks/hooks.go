@@ -0,0 +1,146 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/moai/instant-backlog/internal/config"
+	"github.com/moai/instant-backlog/internal/logger"
+	"github.com/moai/instant-backlog/pkg/utils"
+)
+
+// maxOutputLog - ログに出力するフックの出力の最大バイト数
+const maxOutputLog = 2000
+
+// waitDelay - タイムアウトで停止した後、出力の読み取りを待つ時間
+const waitDelay = time.Second
+
+// Event - フックに標準入力のJSONとして渡すイベント
+type Event struct {
+	Name    string       `json:"event"`
+	Project string       `json:"project"` // projectsディレクトリの絶対パス
+	Time    time.Time    `json:"time"`
+	Issue   *Issue       `json:"issue,omitempty"`
+	Epic    *Epic        `json:"epic,omitempty"`
+	Sync    *SyncSummary `json:"sync,omitempty"`
+}
+
+// Issue - イベントの対象のIssue
+type Issue struct {
+	ID       int    `json:"id"`
+	Title    string `json:"title"`
+	Status   string `json:"status"`
+	Epic     int    `json:"epic"`
+	Estimate int    `json:"estimate"`
+}
+
+// Epic - イベントの対象のEpic
+type Epic struct {
+	ID     int    `json:"id"`
+	Title  string `json:"title"`
+	Status string `json:"status"`
+	From   string `json:"from,omitempty"` // 変更前のステータス
+}
+
+// SyncSummary - after_syncで渡すsyncの結果
+type SyncSummary struct {
+	Issues      int   `json:"issues"`       // 同期後のorder.csvのIssueの数
+	Created     []int `json:"created"`      // order.csvに追加したIssueのID
+	Closed      []int `json:"closed"`       // 完了扱いになりorder.csvから削除したIssueのID
+	EpicsClosed []int `json:"epics_closed"` // 完了にしたEpicのID
+}
+
+// Run - イベントに設定されたフックを順に実行する
+// フックの失敗やタイムアウトは警告として記録し、呼び出し元の処理は失敗させない
+func Run(cfg *config.Config, event Event) {
+	commands := cfg.Hooks[event.Name]
+	if len(commands) == 0 {
+		return
+	}
+
+	if event.Project == "" {
+		event.Project = utils.AbsPath(cfg.ProjectsDir)
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	input, err := json.Marshal(event)
+	if err != nil {
+		logger.Warn("フックに渡すイベントの作成に失敗しました", "event", event.Name, "error", err)
+		return
+	}
+	input = append(input, '\n')
+	env := append(os.Environ(), eventEnv(event)...)
+
+	for _, command := range commands {
+		run(cfg, event.Name, command, input, env)
+	}
+}
+
+// run - フックのコマンドを1つ実行し、結果を記録する
+func run(cfg *config.Config, name, command string, input []byte, env []string) {
+	timeout := cfg.HookTimeLimit()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := shellCommand(ctx, command)
+	cmd.Dir = cfg.BaseDir
+	cmd.Env = env
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.WaitDelay = waitDelay
+
+	started := time.Now()
+	err := cmd.Run()
+	elapsed := time.Since(started).Round(time.Millisecond)
+
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		logger.Warn("フックがタイムアウトしたため停止しました", "event", name, "command", command, "timeout", timeout, "stderr", tail(stderr.String()))
+	case err != nil:
+		logger.Warn("フックの実行に失敗しました", "event", name, "command", command, "error", err, "stderr", tail(stderr.String()))
+	default:
+		logger.Debug("フックを実行しました", "event", name, "command", command, "elapsed", elapsed, "stdout", tail(stdout.String()))
+	}
+}
+
+// eventEnv - フックに渡すイベントの環境変数
+func eventEnv(event Event) []string {
+	env := []string{
+		"IB_HOOK_EVENT=" + event.Name,
+		"IB_HOOK_PROJECT=" + event.Project,
+	}
+	if issue := event.Issue; issue != nil {
+		env = append(env,
+			"IB_HOOK_ISSUE_ID="+strconv.Itoa(issue.ID),
+			"IB_HOOK_ISSUE_TITLE="+issue.Title,
+			"IB_HOOK_ISSUE_STATUS="+issue.Status,
+			"IB_HOOK_ISSUE_EPIC="+strconv.Itoa(issue.Epic),
+		)
+	}
+	if epic := event.Epic; epic != nil {
+		env = append(env,
+			"IB_HOOK_EPIC_ID="+strconv.Itoa(epic.ID),
+			"IB_HOOK_EPIC_TITLE="+epic.Title,
+			"IB_HOOK_EPIC_STATUS="+epic.Status,
+		)
+	}
+	return env
+}
+
+// tail - ログに出力するためにフックの出力の末尾を返す
+func tail(output string) string {
+	output = strings.TrimSpace(output)
+	if len(output) > maxOutputLog {
+		output = "…" + strings.ToValidUTF8(output[len(output)-maxOutputLog:], "")
+	}
+	return output
+}
@@ -0,0 +1,13 @@
+//go:build windows
+
+package hooks
+
+import (
+	"context"
+	"os/exec"
+)
+
+// shellCommand - フックのコマンドをcmd.exeで実行するコマンドを作成する
+func shellCommand(ctx context.Context, command string) *exec.Cmd {
+	return exec.CommandContext(ctx, "cmd", "/C", command)
+}
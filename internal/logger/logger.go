@@ -0,0 +1,124 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sync/atomic"
+)
+
+// ログの出力形式
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Options - ロガーの設定
+type Options struct {
+	Verbose bool      // デバッグログも出力する
+	Quiet   bool      // 警告とエラーのみ出力する
+	Format  string    // 出力形式（text, json）
+	Writer  io.Writer // 出力先（未指定の場合は標準エラー出力）
+}
+
+// current - 現在のロガー
+var current atomic.Pointer[slog.Logger]
+
+// currentOptions - Setupで指定されたオプション（子プロセスへの引き継ぎ用）
+var currentOptions atomic.Pointer[Options]
+
+func init() {
+	current.Store(slog.New(newTextHandler(os.Stderr, slog.LevelInfo)))
+}
+
+// Setup - オプションに従ってロガーを設定する
+func Setup(opts Options) error {
+	if opts.Verbose && opts.Quiet {
+		return fmt.Errorf("--verbose と --quiet は同時に指定できません")
+	}
+
+	level := slog.LevelInfo
+	switch {
+	case opts.Verbose:
+		level = slog.LevelDebug
+	case opts.Quiet:
+		level = slog.LevelWarn
+	}
+
+	w := opts.Writer
+	if w == nil {
+		w = os.Stderr
+	}
+
+	var handler slog.Handler
+	switch opts.Format {
+	case "", FormatText:
+		handler = newTextHandler(w, level)
+	case FormatJSON:
+		handler = slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level})
+	default:
+		return fmt.Errorf("不明なログ形式です: %s（text または json を指定してください）", opts.Format)
+	}
+
+	SetLogger(slog.New(handler))
+	currentOptions.Store(&opts)
+	return nil
+}
+
+// Flags - 現在のログ設定を再現するコマンドラインフラグを返す
+// バックグラウンドで起動する子プロセスに同じログ設定を引き継ぐために使用する
+func Flags() []string {
+	opts := currentOptions.Load()
+	if opts == nil {
+		return nil
+	}
+
+	var flags []string
+	switch {
+	case opts.Verbose:
+		flags = append(flags, "--verbose")
+	case opts.Quiet:
+		flags = append(flags, "--quiet")
+	}
+	if opts.Format != "" && opts.Format != FormatText {
+		flags = append(flags, "--log-format", opts.Format)
+	}
+	return flags
+}
+
+// SetLogger - 使用するロガーを差し替える
+func SetLogger(l *slog.Logger) {
+	current.Store(l)
+}
+
+// L - 現在のロガーを返す
+func L() *slog.Logger {
+	return current.Load()
+}
+
+// Debug - 詳細な処理内容を出力する（--verbose 指定時のみ）
+func Debug(msg string, args ...any) {
+	L().Debug(msg, args...)
+}
+
+// Info - 処理結果の概要を出力する
+func Info(msg string, args ...any) {
+	L().Info(msg, args...)
+}
+
+// Warn - 処理は継続できる問題を出力する
+func Warn(msg string, args ...any) {
+	L().Warn(msg, args...)
+}
+
+// Error - 処理に失敗したことを出力する
+func Error(msg string, args ...any) {
+	L().Error(msg, args...)
+}
+
+// Enabled - 指定したレベルのログが出力されるかを返す
+func Enabled(level slog.Level) bool {
+	return L().Enabled(context.Background(), level)
+}
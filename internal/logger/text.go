@@ -0,0 +1,118 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// textHandler - 端末向けの簡潔なテキスト形式でログを出力するハンドラー
+// 例: "警告: Issueファイルの解析に失敗しました file=1_O_task.md error=..."
+type textHandler struct {
+	w     io.Writer
+	mu    *sync.Mutex
+	level slog.Leveler
+	attrs []slog.Attr
+	group string
+}
+
+// newTextHandler - テキスト形式のハンドラーを作成
+func newTextHandler(w io.Writer, level slog.Leveler) *textHandler {
+	return &textHandler{w: w, mu: &sync.Mutex{}, level: level}
+}
+
+// Enabled - 指定したレベルのログを出力するかを返す
+func (h *textHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+// Handle - 1件のログを出力する
+func (h *textHandler) Handle(_ context.Context, r slog.Record) error {
+	var buf bytes.Buffer
+
+	// デバッグ出力時は時刻を付けてイベントの前後関係を追えるようにする
+	if h.level.Level() <= slog.LevelDebug && !r.Time.IsZero() {
+		buf.WriteString(r.Time.Format(time.TimeOnly))
+		buf.WriteByte(' ')
+	}
+
+	switch {
+	case r.Level >= slog.LevelError:
+		buf.WriteString("エラー: ")
+	case r.Level >= slog.LevelWarn:
+		buf.WriteString("警告: ")
+	case r.Level < slog.LevelInfo:
+		buf.WriteString("[debug] ")
+	}
+	buf.WriteString(r.Message)
+
+	for _, a := range h.attrs {
+		writeAttr(&buf, h.group, a)
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		writeAttr(&buf, h.group, a)
+		return true
+	})
+	buf.WriteByte('\n')
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := h.w.Write(buf.Bytes())
+	return err
+}
+
+// WithAttrs - 属性を追加したハンドラーを返す
+func (h *textHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.attrs = append(append([]slog.Attr{}, h.attrs...), attrs...)
+	return &clone
+}
+
+// WithGroup - グループ名を付けたハンドラーを返す
+func (h *textHandler) WithGroup(name string) slog.Handler {
+	clone := *h
+	if clone.group != "" {
+		name = clone.group + "." + name
+	}
+	clone.group = name
+	return &clone
+}
+
+// writeAttr - 属性を " key=value" の形式で書き込む
+func writeAttr(buf *bytes.Buffer, group string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+
+	key := a.Key
+	if group != "" {
+		key = group + "." + key
+	}
+
+	if a.Value.Kind() == slog.KindGroup {
+		for _, ga := range a.Value.Group() {
+			writeAttr(buf, key, ga)
+		}
+		return
+	}
+
+	buf.WriteByte(' ')
+	buf.WriteString(key)
+	buf.WriteByte('=')
+	buf.WriteString(quoteIfNeeded(fmt.Sprint(a.Value.Any())))
+}
+
+// quoteIfNeeded - 空白などを含む値を引用符で囲む
+func quoteIfNeeded(s string) string {
+	if s == "" || strings.ContainsAny(s, " \t\n\"=") {
+		return strconv.Quote(s)
+	}
+	return s
+}
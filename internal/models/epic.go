@@ -1,9 +1,13 @@
 package models
 
+import "gopkg.in/yaml.v3"
+
 // Epic - スクラムバックログのエピックを表す構造体
 type Epic struct {
 	ID      int    `yaml:"id"`
 	Title   string `yaml:"title"`
-	Status  string `yaml:"status"` // "Open" または "Close"
+	Status  string `yaml:"status"` // ステータス名（既定は "Open" または "Close"）
 	Content string `yaml:"-"`      // Front Matterではない部分のコンテンツ
+	// 読み込んだFront Matterのドキュメント（未知のフィールドやコメントの保持に使用）
+	FrontMatter *yaml.Node `yaml:"-"`
 }
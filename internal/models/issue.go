@@ -1,19 +1,46 @@
 package models
 
+import "gopkg.in/yaml.v3"
+
 // Issue - スクラムバックログの課題を表す構造体
 type Issue struct {
-	ID       int    `yaml:"id"`
-	Title    string `yaml:"title"`
-	Status   string `yaml:"status"` // "Open" または "Close"
-	Epic     int    `yaml:"epic"`   // 関連するEpicのID
-	Estimate int    `yaml:"estimate"`
-	Content  string `yaml:"-"` // Front Matterではない部分のコンテンツ
+	ID        int    `yaml:"id"`
+	Title     string `yaml:"title"`
+	Status    string `yaml:"status"` // ステータス名（既定は "Open" または "Close"）
+	Epic      int    `yaml:"epic"`   // 関連するEpicのID
+	Estimate  int    `yaml:"estimate"`
+	BlockedBy []int  `yaml:"blocked_by,omitempty"` // 先に完了している必要があるIssueのID
+	Rank      string `yaml:"rank,omitempty"`       // 並び順（ordering: rank の場合に使用する辞書順の文字列）
+	Content   string `yaml:"-"`                    // Front Matterではない部分のコンテンツ
+	// 読み込んだFront Matterのドキュメント（未知のフィールドやコメントの保持に使用）
+	FrontMatter *yaml.Node `yaml:"-"`
 }
 
+// OrderCSVColumns - order.csvの固定の列
+var OrderCSVColumns = []string{"id", "title", "epic", "estimate"}
+
 // OrderCSVItem - order.csvに保存される項目
 type OrderCSVItem struct {
 	ID       int    `csv:"id"`
 	Title    string `csv:"title"`
 	Epic     int    `csv:"epic"`
 	Estimate int    `csv:"estimate"`
+	// 固定の列以外の列の値（設定したFront Matterの列や、ユーザーが追加した列の保持に使用）
+	Extra map[string]string `csv:"-"`
+}
+
+// Equal - すべての列の値が等しいかどうかを返す
+func (i OrderCSVItem) Equal(other OrderCSVItem) bool {
+	if i.ID != other.ID || i.Title != other.Title || i.Epic != other.Epic || i.Estimate != other.Estimate {
+		return false
+	}
+	if len(i.Extra) != len(other.Extra) {
+		return false
+	}
+	for column, value := range i.Extra {
+		if otherValue, ok := other.Extra[column]; !ok || otherValue != value {
+			return false
+		}
+	}
+	return true
 }
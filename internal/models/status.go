@@ -0,0 +1,147 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// StatusDef - ワークフロー上の1つのステータスを表す構造体
+type StatusDef struct {
+	Name   string `yaml:"name"`   // Front Matterに記述するステータス名
+	Prefix string `yaml:"prefix"` // ファイル名に使用する頭文字
+	Done   bool   `yaml:"done"`   // 完了扱いのステータスかどうか
+}
+
+// StatusSet - プロジェクトで使用するステータスの一覧
+type StatusSet []StatusDef
+
+// DefaultStatusSet - 従来のOpen/Closeのみのステータス一覧を返す
+func DefaultStatusSet() StatusSet {
+	return StatusSet{
+		{Name: "Open", Prefix: "O", Done: false},
+		{Name: "Close", Prefix: "C", Done: true},
+	}
+}
+
+// Find - ステータス名から定義を検索する
+func (s StatusSet) Find(name string) (StatusDef, bool) {
+	for _, def := range s {
+		if def.Name == name {
+			return def, true
+		}
+	}
+	return StatusDef{}, false
+}
+
+// FindFold - 大文字小文字を区別せずにステータス名から定義を検索する
+// 表記の誤りを指摘する場合など、正しいステータス名を推測するために使用する
+func (s StatusSet) FindFold(name string) (StatusDef, bool) {
+	for _, def := range s {
+		if strings.EqualFold(def.Name, name) {
+			return def, true
+		}
+	}
+	return StatusDef{}, false
+}
+
+// FindByPrefix - ファイル名の頭文字から定義を検索する
+func (s StatusSet) FindByPrefix(prefix string) (StatusDef, bool) {
+	for _, def := range s {
+		if def.Prefix == prefix {
+			return def, true
+		}
+	}
+	return StatusDef{}, false
+}
+
+// IsValid - 定義済みのステータスかどうかを返す
+func (s StatusSet) IsValid(name string) bool {
+	_, ok := s.Find(name)
+	return ok
+}
+
+// IsDone - 完了扱いのステータスかどうかを返す
+// 未定義のステータスは未完了として扱う
+func (s StatusSet) IsDone(name string) bool {
+	def, ok := s.Find(name)
+	return ok && def.Done
+}
+
+// Prefix - ステータスに対応するファイル名の頭文字を返す
+// 大文字小文字は区別せず、未定義のステータスは最初の未完了ステータスの頭文字を使用する
+func (s StatusSet) Prefix(name string) string {
+	if def, ok := s.FindFold(name); ok {
+		return def.Prefix
+	}
+	return s.InitialStatus().Prefix
+}
+
+// InitialStatus - 新規作成時に使用する最初の未完了ステータスを返す
+func (s StatusSet) InitialStatus() StatusDef {
+	for _, def := range s {
+		if !def.Done {
+			return def
+		}
+	}
+	return DefaultStatusSet()[0]
+}
+
+// DoneStatus - 自動クローズ時に使用する最初の完了ステータスを返す
+func (s StatusSet) DoneStatus() StatusDef {
+	for _, def := range s {
+		if def.Done {
+			return def
+		}
+	}
+	return DefaultStatusSet()[1]
+}
+
+// Names - ステータス名の一覧を返す
+func (s StatusSet) Names() []string {
+	names := make([]string, 0, len(s))
+	for _, def := range s {
+		names = append(names, def.Name)
+	}
+	return names
+}
+
+// Validate - ステータス定義の整合性を検証する
+func (s StatusSet) Validate() error {
+	if len(s) == 0 {
+		return fmt.Errorf("ステータスが1つも定義されていません")
+	}
+
+	names := make(map[string]bool)
+	prefixes := make(map[string]bool)
+	hasOpen, hasDone := false, false
+	for _, def := range s {
+		if def.Name == "" {
+			return fmt.Errorf("ステータス名は必須です")
+		}
+		if def.Prefix == "" || strings.ContainsAny(def.Prefix, "_/\\.") {
+			return fmt.Errorf("ステータス '%s' の頭文字が不正です: '%s'", def.Name, def.Prefix)
+		}
+
+		key := strings.ToLower(def.Name)
+		if names[key] {
+			return fmt.Errorf("ステータス '%s' が重複しています", def.Name)
+		}
+		if prefixes[def.Prefix] {
+			return fmt.Errorf("頭文字 '%s' が重複しています", def.Prefix)
+		}
+		names[key] = true
+		prefixes[def.Prefix] = true
+
+		if def.Done {
+			hasDone = true
+		} else {
+			hasOpen = true
+		}
+	}
+
+	if !hasOpen || !hasDone {
+		return fmt.Errorf("未完了と完了のステータスがそれぞれ1つ以上必要です")
+	}
+
+	return nil
+}
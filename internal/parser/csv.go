@@ -1,44 +1,252 @@
 package parser
 
 import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
 	"os"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/gocarina/gocsv"
 	"github.com/moai/instant-backlog/internal/models"
+	"github.com/moai/instant-backlog/pkg/utils"
 )
 
 // ReadOrderCSV - order.csvを読み込みOrderCSVItemスライスを返す
 func ReadOrderCSV(filePath string) ([]models.OrderCSVItem, error) {
+	orderItems, _, err := ReadOrderCSVWithColumns(filePath)
+	return orderItems, err
+}
+
+// ReadOrderCSVWithColumns - order.csvを読み込み、OrderCSVItemスライスと固定の列以外の列名（ファイルでの順序）を返す
+// 固定の列以外の値は各項目のExtraに保持する
+func ReadOrderCSVWithColumns(filePath string) ([]models.OrderCSVItem, []string, error) {
 	// ファイルが存在しない場合は空のスライスを返す
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		return []models.OrderCSVItem{}, nil
+	data, err := os.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return []models.OrderCSVItem{}, nil, nil
 	}
-
-	// CSVファイルを開く
-	file, err := os.Open(filePath)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	data = trimBOM(data)
+	// 空のファイル（add/addの競合でgitが渡す共通祖先など）は行のないorder.csvとして扱う
+	if len(bytes.TrimSpace(data)) == 0 {
+		return []models.OrderCSVItem{}, nil, nil
 	}
-	defer file.Close()
 
 	// CSVをパース
 	var orderItems []models.OrderCSVItem
-	if err := gocsv.UnmarshalFile(file, &orderItems); err != nil {
-		return nil, err
+	if err := gocsv.UnmarshalBytes(data, &orderItems); err != nil {
+		return nil, nil, err
+	}
+
+	// 固定の列以外の値を読み込む
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil || len(records) == 0 {
+		return orderItems, nil, err
+	}
+
+	fixed := make(map[string]bool, len(models.OrderCSVColumns))
+	for _, column := range models.OrderCSVColumns {
+		fixed[column] = true
+	}
+	var columns []string
+	var indexes []int
+	for i, column := range records[0] {
+		if !fixed[column] {
+			columns = append(columns, column)
+			indexes = append(indexes, i)
+		}
+	}
+	if len(columns) == 0 {
+		return orderItems, nil, nil
+	}
+
+	for row, record := range records[1:] {
+		if row >= len(orderItems) {
+			break
+		}
+		extra := make(map[string]string, len(columns))
+		for j, column := range columns {
+			if indexes[j] < len(record) {
+				extra[column] = record[indexes[j]]
+			}
+		}
+		orderItems[row].Extra = extra
 	}
 
-	return orderItems, nil
+	return orderItems, columns, nil
+}
+
+// OrderCSVProblem - order.csvを寛容に読み込んだときに見つかった問題
+type OrderCSVProblem struct {
+	Line    int    // 行番号（1始まり、ヘッダーが1行目）
+	ID      int    // 値が不正なためIssueファイルの値を使う行のID（行を読み飛ばした場合は0）
+	Message string // 問題の内容
+}
+
+// utf8BOM - ExcelやGoogleスプレッドシートで保存したCSVの先頭に付くBOM
+var utf8BOM = []byte("\ufeff")
+
+// trimBOM - 先頭のBOMを除く（除かないと1列目の列名が「\ufeffid」になる）
+func trimBOM(data []byte) []byte {
+	return bytes.TrimPrefix(data, utf8BOM)
+}
+
+// ReadOrderCSVWithProblems - order.csvを1行ずつ読み込み、不正な行があっても読み込みを続ける
+// 値の前後の空白は取り除く。IDが不正な行は読み飛ばし、epic/estimateが不正な行は
+// 値を0として読み込んで問題にIDを記録する（呼び出し側でIssueファイルの値を使う）
+func ReadOrderCSVWithProblems(filePath string) ([]models.OrderCSVItem, []string, []OrderCSVProblem, error) {
+	data, err := os.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return []models.OrderCSVItem{}, nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	r := csv.NewReader(bytes.NewReader(trimBOM(data)))
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	r.TrimLeadingSpace = true
+
+	header, err := r.Read()
+	if err == io.EOF {
+		return []models.OrderCSVItem{}, nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	// 列名から列の位置を求める
+	positions := make(map[string]int, len(header))
+	var columns []string
+	fixed := make(map[string]bool, len(models.OrderCSVColumns))
+	for _, column := range models.OrderCSVColumns {
+		fixed[column] = true
+	}
+	for i, column := range header {
+		column = strings.TrimSpace(column)
+		if _, ok := positions[column]; ok || column == "" {
+			continue
+		}
+		positions[column] = i
+		if !fixed[column] {
+			columns = append(columns, column)
+		}
+	}
+	if _, ok := positions["id"]; !ok {
+		return nil, nil, nil, fmt.Errorf("order.csvにid列がありません")
+	}
+
+	var items []models.OrderCSVItem
+	var problems []OrderCSVProblem
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			problems = append(problems, OrderCSVProblem{Line: parseErr.StartLine, Message: parseErr.Err.Error()})
+			continue
+		}
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		line, _ := r.FieldPos(0)
+
+		// 列がない場合は空欄として扱う
+		value := func(column string) string {
+			i, ok := positions[column]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		id, err := strconv.Atoi(value("id"))
+		if err != nil {
+			problems = append(problems, OrderCSVProblem{Line: line, Message: fmt.Sprintf("IDが整数ではありません: %q", value("id"))})
+			continue
+		}
+
+		item := models.OrderCSVItem{ID: id, Title: value("title")}
+		var invalid []string
+		for _, field := range []struct {
+			column string
+			dst    *int
+		}{{"epic", &item.Epic}, {"estimate", &item.Estimate}} {
+			number, err := strconv.Atoi(value(field.column))
+			if err != nil {
+				invalid = append(invalid, fmt.Sprintf("%s=%q", field.column, value(field.column)))
+				continue
+			}
+			*field.dst = number
+		}
+		if len(invalid) > 0 {
+			problems = append(problems, OrderCSVProblem{Line: line, ID: id, Message: "値が整数ではありません: " + strings.Join(invalid, ", ")})
+		}
+
+		if len(columns) > 0 {
+			item.Extra = make(map[string]string, len(columns))
+			for _, column := range columns {
+				item.Extra[column] = value(column)
+			}
+		}
+		items = append(items, item)
+	}
+
+	return items, columns, problems, nil
 }
 
 // WriteOrderCSV - OrderCSVItemスライスをorder.csvに書き込む
+// 固定の列以外の列は列名順に書き込む
 func WriteOrderCSV(filePath string, orderItems []models.OrderCSVItem) error {
-	// CSVファイルを作成/上書き
-	file, err := os.Create(filePath)
-	if err != nil {
+	seen := make(map[string]bool)
+	var columns []string
+	for _, item := range orderItems {
+		for column := range item.Extra {
+			if !seen[column] {
+				seen[column] = true
+				columns = append(columns, column)
+			}
+		}
+	}
+	sort.Strings(columns)
+
+	return WriteOrderCSVWithColumns(filePath, orderItems, columns)
+}
+
+// WriteOrderCSVWithColumns - 固定の列に続けて指定した列を書き込む
+// 項目のExtraにない列は空欄になる
+func WriteOrderCSVWithColumns(filePath string, orderItems []models.OrderCSVItem, columns []string) error {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	header := append(append([]string{}, models.OrderCSVColumns...), columns...)
+	if err := w.Write(header); err != nil {
+		return err
+	}
+	for _, item := range orderItems {
+		record := []string{strconv.Itoa(item.ID), item.Title, strconv.Itoa(item.Epic), strconv.Itoa(item.Estimate)}
+		for _, column := range columns {
+			record = append(record, item.Extra[column])
+		}
+		if err := w.Write(record); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
 		return err
 	}
-	defer file.Close()
 
-	// CSVにマーシャリング
-	return gocsv.MarshalFile(&orderItems, file)
+	// 書き込み途中の内容が読まれないよう一時ファイル経由で置き換える
+	return utils.WriteFileAtomic(filePath, buf.Bytes(), 0644)
 }
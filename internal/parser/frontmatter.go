@@ -0,0 +1,270 @@
+package parser
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// parseFrontMatterNode - Front MatterのYAMLをドキュメントノードとして解析する
+func parseFrontMatterNode(data []byte) (*yaml.Node, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	// 空のFront Matterは空のマッピングとして扱う
+	if doc.Kind == 0 {
+		doc = yaml.Node{
+			Kind:    yaml.DocumentNode,
+			Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}},
+		}
+	}
+
+	if mappingOf(&doc) == nil {
+		return nil, fmt.Errorf("Front Matterがマッピング形式ではありません")
+	}
+	return &doc, nil
+}
+
+// marshalFrontMatter - 構造体をFront MatterのYAMLに変換する
+// 元のドキュメントノードがある場合は、構造体が持つフィールドのみを更新し、
+// キーの順序・コメント・未知のフィールドを保持する
+func marshalFrontMatter(v interface{}, original *yaml.Node) ([]byte, error) {
+	if original == nil || mappingOf(original) == nil {
+		return encodeYAML(v, defaultIndent)
+	}
+
+	var fresh yaml.Node
+	if err := fresh.Encode(v); err != nil {
+		return nil, err
+	}
+	if fresh.Kind != yaml.MappingNode {
+		return encodeYAML(v, defaultIndent)
+	}
+
+	// 元のノードを変更しないように複製してから更新する
+	doc := cloneNode(original)
+	mergeMapping(mappingOf(doc), &fresh, ownedKeys(v))
+
+	return encodeYAML(doc, detectIndent(mappingOf(original)))
+}
+
+// defaultIndent - ネストしたYAMLのデフォルトのインデント幅
+const defaultIndent = 2
+
+// encodeYAML - 指定したインデント幅でYAMLにエンコードする
+func encodeYAML(v interface{}, indent int) ([]byte, error) {
+	var buffer bytes.Buffer
+	encoder := yaml.NewEncoder(&buffer)
+	encoder.SetIndent(indent)
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// detectIndent - 元のFront Matterで使われているインデント幅を推定する
+func detectIndent(mapping *yaml.Node) int {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		key, value := mapping.Content[i], mapping.Content[i+1]
+		if value.Style&yaml.FlowStyle != 0 || len(value.Content) == 0 {
+			continue
+		}
+		switch value.Kind {
+		case yaml.MappingNode:
+			if indent := value.Content[0].Column - key.Column; indent > 0 {
+				return indent
+			}
+		case yaml.SequenceNode:
+			// シーケンスの要素は「- 」の分だけ右にずれる
+			if indent := value.Content[0].Column - key.Column - 2; indent > 0 {
+				return indent
+			}
+		}
+	}
+	return defaultIndent
+}
+
+// mappingOf - ドキュメントノードまたはマッピングノードからマッピングノードを取得する
+func mappingOf(node *yaml.Node) *yaml.Node {
+	if node.Kind == yaml.DocumentNode {
+		if len(node.Content) == 0 {
+			return nil
+		}
+		node = node.Content[0]
+	}
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	return node
+}
+
+// mergeMapping - srcのキーと値をdstに反映する
+// owned に含まれるキーのうち src にないもの（omitemptyで省略されたもの）は dst から削除する
+func mergeMapping(dst, src *yaml.Node, owned map[string]bool) {
+	present := make(map[string]bool)
+	for i := 0; i+1 < len(src.Content); i += 2 {
+		key, value := src.Content[i], src.Content[i+1]
+		present[key.Value] = true
+
+		if existing := lookupValue(dst, key.Value); existing != nil {
+			updateValue(existing, value)
+		} else {
+			dst.Content = append(dst.Content, key, value)
+		}
+	}
+
+	content := dst.Content[:0]
+	for i := 0; i+1 < len(dst.Content); i += 2 {
+		key := dst.Content[i].Value
+		if owned[key] && !present[key] {
+			continue
+		}
+		content = append(content, dst.Content[i], dst.Content[i+1])
+	}
+	dst.Content = content
+}
+
+// lookupValue - マッピングノードから指定したキーの値ノードを取得する
+func lookupValue(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// updateValue - 値ノードを更新する（値が同じ場合はスタイルやコメントを含めて変更しない）
+func updateValue(dst, src *yaml.Node) {
+	if sameValue(dst, src) {
+		return
+	}
+
+	if dst.Kind == yaml.ScalarNode && src.Kind == yaml.ScalarNode {
+		dst.Value = src.Value
+		dst.Tag = src.Tag
+		// 文字列以外の値に引用符を付けると型が変わるため、引用符のスタイルを外す
+		if src.ShortTag() != "!!str" {
+			dst.Style &^= yaml.DoubleQuotedStyle | yaml.SingleQuotedStyle
+		}
+		return
+	}
+
+	if dst.Kind == src.Kind && dst.Kind == yaml.SequenceNode {
+		// フロースタイルなどの書式は維持して要素のみ置き換える
+		dst.Content = src.Content
+		return
+	}
+
+	// 種類が異なる場合はコメントのみ保持して置き換える
+	head, line, foot := dst.HeadComment, dst.LineComment, dst.FootComment
+	*dst = *src
+	dst.HeadComment, dst.LineComment, dst.FootComment = head, line, foot
+}
+
+// sameValue - 2つのノードがYAMLとして同じ値を表すかどうかを返す
+func sameValue(a, b *yaml.Node) bool {
+	var av, bv interface{}
+	if err := a.Decode(&av); err != nil {
+		return false
+	}
+	if err := b.Decode(&bv); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(av, bv)
+}
+
+// ownedKeys - 構造体のyamlタグからツールが管理するキーの一覧を取得する
+func ownedKeys(v interface{}) map[string]bool {
+	keys := make(map[string]bool)
+
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return keys
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		tag := t.Field(i).Tag.Get("yaml")
+		name := strings.Split(tag, ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		keys[name] = true
+	}
+	return keys
+}
+
+// cloneNode - ノードを再帰的に複製する
+func cloneNode(node *yaml.Node) *yaml.Node {
+	if node == nil {
+		return nil
+	}
+	copied := *node
+	copied.Content = make([]*yaml.Node, len(node.Content))
+	for i, child := range node.Content {
+		copied.Content[i] = cloneNode(child)
+	}
+	return &copied
+}
+
+// FieldLine - Front Matterのキーがファイルの何行目にあるかを返す（見つからない場合は0）
+// Front Matterはファイルの2行目（開始の---の次の行）から始まるものとして数える
+func FieldLine(doc *yaml.Node, key string) int {
+	if doc == nil {
+		return 0
+	}
+	mapping := mappingOf(doc)
+	if mapping == nil {
+		return 0
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i].Line + 1
+		}
+	}
+	return 0
+}
+
+// FieldValue - Front Matterのキーの値を文字列で返す（見つからない場合は空文字）
+// リストの場合は要素を「, 」で連結する
+func FieldValue(doc *yaml.Node, key string) string {
+	if doc == nil {
+		return ""
+	}
+	mapping := mappingOf(doc)
+	if mapping == nil {
+		return ""
+	}
+	value := lookupValue(mapping, key)
+	if value == nil {
+		return ""
+	}
+
+	switch value.Kind {
+	case yaml.ScalarNode:
+		if value.Tag == "!!null" {
+			return ""
+		}
+		return value.Value
+	case yaml.SequenceNode:
+		items := make([]string, 0, len(value.Content))
+		for _, item := range value.Content {
+			if item.Kind == yaml.ScalarNode {
+				items = append(items, item.Value)
+			}
+		}
+		return strings.Join(items, ", ")
+	}
+	return ""
+}
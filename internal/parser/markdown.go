@@ -2,11 +2,11 @@ package parser
 
 import (
 	"bytes"
-	"fmt"
 	"os"
 	"regexp"
 	"strings"
 
+	"github.com/moai/instant-backlog/internal/logger"
 	"github.com/moai/instant-backlog/internal/models"
 	"gopkg.in/yaml.v3"
 )
@@ -18,35 +18,29 @@ var frontMatterRegex = regexp.MustCompile(`(?s)^---\n(.*?)\n---\n(.*)`)
 
 // ParseIssueFile - Issueファイルを解析してIssue構造体を返す
 func ParseIssueFile(filePath string) (*models.Issue, error) {
-	// デバッグ: ファイルパスを表示
-	fmt.Printf("ParseIssueFile: ファイルパス %s\n", filePath)
-
 	content, err := os.ReadFile(filePath)
 	if err != nil {
-		fmt.Printf("ファイル読み込みエラー: %v\n", err)
 		return nil, err
 	}
 
-	// デバッグ: ファイル内容の先頭部分を表示
-	if len(content) > 0 {
-		fmt.Printf("ファイル内容の先頭部分: %s\n", string(content[:min(100, len(content))]))
-	}
-
 	matches := frontMatterRegex.FindSubmatch(content)
 	if len(matches) != 3 {
-		fmt.Printf("無効なFront Matter形式が検出されました\n")
 		return nil, &InvalidFrontMatterError{FilePath: filePath}
 	}
 
+	doc, err := parseFrontMatterNode(matches[1])
+	if err != nil {
+		return nil, err
+	}
+
 	var issue models.Issue
-	err = yaml.Unmarshal(matches[1], &issue)
+	err = doc.Decode(&issue)
 	if err != nil {
-		fmt.Printf("YAMLデシリアライズエラー: %v\n", err)
 		return nil, err
 	}
+	issue.FrontMatter = doc
 
-	// デバッグ: Unmarshalした値を表示
-	fmt.Printf("パースされたIssue: ID=%d, Title=%s, Status=%s\n", issue.ID, issue.Title, issue.Status)
+	logger.Debug("Issueファイルを解析しました", "file", filePath, "id", issue.ID, "status", issue.Status)
 
 	// FrontMatterではない部分のコンテンツを設定
 	issue.Content = strings.TrimSpace(string(matches[2]))
@@ -66,11 +60,17 @@ func ParseEpicFile(filePath string) (*models.Epic, error) {
 		return nil, &InvalidFrontMatterError{FilePath: filePath}
 	}
 
+	doc, err := parseFrontMatterNode(matches[1])
+	if err != nil {
+		return nil, err
+	}
+
 	var epic models.Epic
-	err = yaml.Unmarshal(matches[1], &epic)
+	err = doc.Decode(&epic)
 	if err != nil {
 		return nil, err
 	}
+	epic.FrontMatter = doc
 
 	// FrontMatterではない部分のコンテンツを設定
 	epic.Content = strings.TrimSpace(string(matches[2]))
@@ -79,9 +79,19 @@ func ParseEpicFile(filePath string) (*models.Epic, error) {
 }
 
 // GenerateMarkdown - Front Matterとコンテンツからマークダウンテキストを生成
+// 読み込み済みのIssue/Epicの場合は、元のFront Matterのキー順・コメント・未知のフィールドを保持する
 func GenerateMarkdown(frontMatter interface{}, content string) ([]byte, error) {
+	// 元のFront Matterを取得
+	var original *yaml.Node
+	switch v := frontMatter.(type) {
+	case *models.Issue:
+		original = v.FrontMatter
+	case *models.Epic:
+		original = v.FrontMatter
+	}
+
 	// Front Matterをマーシャリング
-	frontMatterBytes, err := yaml.Marshal(frontMatter)
+	frontMatterBytes, err := marshalFrontMatter(frontMatter, original)
 	if err != nil {
 		return nil, err
 	}
@@ -96,6 +106,16 @@ func GenerateMarkdown(frontMatter interface{}, content string) ([]byte, error) {
 	return buffer.Bytes(), nil
 }
 
+// SplitFrontMatter - マークダウンをFront Matterと本文に分割する
+// Front Matterがない場合は ok=false を返す
+func SplitFrontMatter(content []byte) (frontMatter, body []byte, ok bool) {
+	matches := frontMatterRegex.FindSubmatch(content)
+	if len(matches) != 3 {
+		return nil, nil, false
+	}
+	return matches[1], matches[2], true
+}
+
 // カスタムエラー型
 type InvalidFrontMatterError struct {
 	FilePath string
@@ -104,11 +124,3 @@ type InvalidFrontMatterError struct {
 func (e *InvalidFrontMatterError) Error() string {
 	return "無効なFront Matter形式: " + e.FilePath
 }
-
-// min は2つの整数の小さい方を返す
-func min(a, b int) int {
-	if a < b {
-		return a
-	}
-	return b
-}
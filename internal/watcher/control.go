@@ -0,0 +1,267 @@
+package watcher
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/moai/instant-backlog/internal/config"
+	"github.com/moai/instant-backlog/internal/logger"
+	"github.com/moai/instant-backlog/pkg/utils"
+)
+
+// 制御プロトコルのアクション
+const (
+	ActionPing       = "ping"
+	ActionWatch      = "watch"
+	ActionUnwatch    = "unwatch"
+	ActionUnwatchAll = "unwatch_all"
+	ActionList       = "list"
+)
+
+// 状態ディレクトリ内のファイル名
+const (
+	socketFileName   = "watch.sock"
+	registryFileName = "projects.json"
+	logFileName      = "watch.log"
+)
+
+// ErrWatcherNotRunning - 監視プロセスが起動していないことを表すエラー
+var ErrWatcherNotRunning = errors.New("監視プロセスは起動していません")
+
+// ControlRequest - 制御ソケットに送信するリクエスト
+type ControlRequest struct {
+	Action     string `json:"action"`
+	Project    string `json:"project,omitempty"`
+	DebounceMS int64  `json:"debounce_ms,omitempty"`
+}
+
+// ControlResponse - 制御ソケットから返されるレスポンス
+type ControlResponse struct {
+	OK       bool     `json:"ok"`
+	Error    string   `json:"error,omitempty"`
+	PID      int      `json:"pid,omitempty"`
+	Projects []string `json:"projects,omitempty"`
+}
+
+// Registry - 状態ディレクトリに保存する監視中プロジェクトの一覧
+type Registry struct {
+	PID       int       `json:"pid"`
+	Socket    string    `json:"socket"`
+	StartedAt time.Time `json:"started_at"`
+	Projects  []string  `json:"projects"`
+}
+
+// StateDir - 監視プロセスの状態ディレクトリを返す
+// 優先順位: IB_STATE_DIR → $XDG_STATE_HOME/instant-backlog → ~/.local/state/instant-backlog
+func StateDir() string {
+	return config.DefaultStateDir()
+}
+
+// SocketPath - 制御ソケットのパスを返す
+func SocketPath(stateDir string) string {
+	return filepath.Join(stateDir, socketFileName)
+}
+
+// LogPath - デーモンのログファイルのパスを返す
+func LogPath(stateDir string) string {
+	return filepath.Join(stateDir, logFileName)
+}
+
+// ReadRegistry - 状態ディレクトリから監視中プロジェクトの一覧を読み込む
+func ReadRegistry(stateDir string) (*Registry, error) {
+	data, err := os.ReadFile(filepath.Join(stateDir, registryFileName))
+	if err != nil {
+		return nil, err
+	}
+	var registry Registry
+	if err := json.Unmarshal(data, &registry); err != nil {
+		return nil, err
+	}
+	return &registry, nil
+}
+
+// SendControlRequest - 起動中の監視プロセスにリクエストを送信する
+// 監視プロセスが起動していない場合は ErrWatcherNotRunning を返す
+func SendControlRequest(stateDir string, req ControlRequest) (*ControlResponse, error) {
+	conn, err := net.DialTimeout("unix", SocketPath(stateDir), time.Second)
+	if err != nil {
+		return nil, ErrWatcherNotRunning
+	}
+	defer conn.Close()
+
+	// syncの実行中でも応答できるよう余裕を持たせる
+	conn.SetDeadline(time.Now().Add(30 * time.Second))
+
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return nil, fmt.Errorf("リクエストの送信に失敗しました: %w", err)
+	}
+
+	var resp ControlResponse
+	if err := json.NewDecoder(bufio.NewReader(conn)).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("レスポンスの受信に失敗しました: %w", err)
+	}
+	if !resp.OK {
+		return &resp, errors.New(resp.Error)
+	}
+	return &resp, nil
+}
+
+// IsControlServerRunning - 制御ソケットに応答する監視プロセスが起動しているかを返す
+func IsControlServerRunning(stateDir string) bool {
+	_, err := SendControlRequest(stateDir, ControlRequest{Action: ActionPing})
+	return err == nil
+}
+
+// ControlServer - 別プロセスからの監視操作を受け付けるUnixソケットサーバー
+type ControlServer struct {
+	stateDir  string
+	manager   *WatchManager
+	listener  net.Listener
+	startedAt time.Time
+	idle      chan struct{} // 監視中のプロジェクトがなくなったときにクローズされる
+	idleOnce  sync.Once
+	closeOnce sync.Once
+}
+
+// StartControlServer - 制御ソケットでリクエストの受付を開始する
+func StartControlServer(stateDir string, manager *WatchManager) (*ControlServer, error) {
+	if err := os.MkdirAll(stateDir, 0700); err != nil {
+		return nil, fmt.Errorf("状態ディレクトリの作成に失敗しました: %w", err)
+	}
+
+	socketPath := SocketPath(stateDir)
+	if IsControlServerRunning(stateDir) {
+		return nil, fmt.Errorf("監視プロセスは既に起動しています: %s", socketPath)
+	}
+	// 異常終了したプロセスが残したソケットを削除
+	os.Remove(socketPath)
+
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return nil, fmt.Errorf("制御ソケットの作成に失敗しました: %w", err)
+	}
+
+	server := &ControlServer{
+		stateDir:  stateDir,
+		manager:   manager,
+		listener:  listener,
+		startedAt: time.Now(),
+		idle:      make(chan struct{}),
+	}
+	if err := server.writeRegistry(); err != nil {
+		listener.Close()
+		return nil, err
+	}
+
+	go server.serve()
+	return server, nil
+}
+
+// Idle - 監視中のプロジェクトがすべて停止されたときにクローズされるチャネルを返す
+func (s *ControlServer) Idle() <-chan struct{} {
+	return s.idle
+}
+
+// Close - 制御ソケットを閉じ、状態ファイルを削除する
+func (s *ControlServer) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		err = s.listener.Close()
+		os.Remove(SocketPath(s.stateDir))
+		os.Remove(filepath.Join(s.stateDir, registryFileName))
+	})
+	return err
+}
+
+// serve - 接続を受け付けてリクエストを処理する
+func (s *ControlServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			// リスナーがクローズされた
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+// handle - 1つの接続のリクエストを処理する
+func (s *ControlServer) handle(conn net.Conn) {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(30 * time.Second))
+
+	var req ControlRequest
+	if err := json.NewDecoder(bufio.NewReader(conn)).Decode(&req); err != nil {
+		json.NewEncoder(conn).Encode(ControlResponse{Error: fmt.Sprintf("不正なリクエストです: %v", err)})
+		return
+	}
+
+	resp := s.dispatch(req)
+	resp.PID = os.Getpid()
+	json.NewEncoder(conn).Encode(resp)
+}
+
+// dispatch - アクションに応じて監視マネージャーを操作する
+func (s *ControlServer) dispatch(req ControlRequest) ControlResponse {
+	var err error
+	switch req.Action {
+	case ActionPing, ActionList:
+		// 状態の変更なし
+	case ActionWatch:
+		debounce := time.Duration(req.DebounceMS) * time.Millisecond
+		err = s.manager.StartWatching(req.Project, debounce)
+	case ActionUnwatch:
+		err = s.manager.StopWatching(req.Project)
+	case ActionUnwatchAll:
+		s.manager.StopAll()
+	default:
+		err = fmt.Errorf("不明なアクションです: %s", req.Action)
+	}
+
+	if err != nil {
+		return ControlResponse{Error: err.Error()}
+	}
+
+	if req.Action != ActionPing && req.Action != ActionList {
+		if err := s.writeRegistry(); err != nil {
+			logger.Warn("監視状態の保存に失敗しました", "error", err)
+		}
+	}
+
+	projects := s.projects()
+	if len(projects) == 0 && (req.Action == ActionUnwatch || req.Action == ActionUnwatchAll) {
+		s.idleOnce.Do(func() { close(s.idle) })
+	}
+
+	return ControlResponse{OK: true, Projects: projects}
+}
+
+// projects - 監視中のプロジェクトをパス順で返す
+func (s *ControlServer) projects() []string {
+	projects := s.manager.GetWatchingProjects()
+	sort.Strings(projects)
+	return projects
+}
+
+// writeRegistry - 監視中のプロジェクトを状態ディレクトリに書き込む
+func (s *ControlServer) writeRegistry() error {
+	registry := Registry{
+		PID:       os.Getpid(),
+		Socket:    SocketPath(s.stateDir),
+		StartedAt: s.startedAt,
+		Projects:  s.projects(),
+	}
+	data, err := json.MarshalIndent(registry, "", "  ")
+	if err != nil {
+		return err
+	}
+	return utils.WriteFileAtomic(filepath.Join(s.stateDir, registryFileName), data, 0600)
+}
@@ -0,0 +1,53 @@
+package watcher
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+)
+
+// daemonStartTimeout - デーモンの制御ソケットが応答するまでの待機時間
+const daemonStartTimeout = 5 * time.Second
+
+// SpawnDaemon - 指定した引数で自身をバックグラウンドプロセスとして起動する
+// 標準出力と標準エラーは状態ディレクトリのログファイルに追記される
+func SpawnDaemon(stateDir string, args []string) (int, error) {
+	execPath, err := os.Executable()
+	if err != nil {
+		return 0, fmt.Errorf("実行ファイルのパスを取得できません: %w", err)
+	}
+
+	if err := os.MkdirAll(stateDir, 0700); err != nil {
+		return 0, fmt.Errorf("状態ディレクトリの作成に失敗しました: %w", err)
+	}
+
+	logFile, err := os.OpenFile(LogPath(stateDir), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return 0, fmt.Errorf("ログファイルを開けません: %w", err)
+	}
+	defer logFile.Close()
+
+	cmd := exec.Command(execPath, args...)
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	cmd.Env = append(os.Environ(), "IB_STATE_DIR="+stateDir)
+	cmd.SysProcAttr = detachedProcAttr()
+
+	if err := cmd.Start(); err != nil {
+		return 0, fmt.Errorf("デーモンの起動に失敗しました: %w", err)
+	}
+	pid := cmd.Process.Pid
+	cmd.Process.Release()
+
+	// 制御ソケットが応答するまで待機
+	deadline := time.Now().Add(daemonStartTimeout)
+	for time.Now().Before(deadline) {
+		if IsControlServerRunning(stateDir) {
+			return pid, nil
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	return pid, fmt.Errorf("デーモンが応答しません。ログを確認してください: %s", LogPath(stateDir))
+}
@@ -0,0 +1,10 @@
+//go:build !windows
+
+package watcher
+
+import "syscall"
+
+// detachedProcAttr - 端末から切り離して新しいセッションで起動するための属性
+func detachedProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{Setsid: true}
+}
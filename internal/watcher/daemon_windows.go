@@ -0,0 +1,16 @@
+//go:build windows
+
+package watcher
+
+import "syscall"
+
+// Windowsのプロセス作成フラグ
+const (
+	detachedProcess       = 0x00000008
+	createNewProcessGroup = 0x00000200
+)
+
+// detachedProcAttr - コンソールから切り離して起動するための属性
+func detachedProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{CreationFlags: detachedProcess | createNewProcessGroup}
+}
@@ -4,8 +4,15 @@ import (
 	"fmt"
 	"sync"
 	"time"
+
+	"github.com/moai/instant-backlog/internal/config"
+	"github.com/moai/instant-backlog/internal/logger"
+	"github.com/moai/instant-backlog/pkg/utils"
 )
 
+// DefaultDebounceTime - デフォルトのデバウンス時間
+const DefaultDebounceTime = config.DefaultDebounce
+
 // WatchManager - 複数プロジェクトの監視を管理するシングルトンマネージャー
 type WatchManager struct {
 	watchers map[string]*ProjectWatcher // プロジェクトパスをキーとしたウォッチャーマップ
@@ -30,6 +37,8 @@ func GetManager() *WatchManager {
 
 // StartWatching - 指定したプロジェクトパスの監視を開始
 func (m *WatchManager) StartWatching(projectPath string, debounceTime time.Duration) error {
+	projectPath = utils.AbsPath(projectPath)
+
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -38,6 +47,10 @@ func (m *WatchManager) StartWatching(projectPath string, debounceTime time.Durat
 		return fmt.Errorf("プロジェクト '%s' は既に監視中です", projectPath)
 	}
 
+	if debounceTime <= 0 {
+		debounceTime = DefaultDebounceTime
+	}
+
 	// 新しいプロジェクトウォッチャーを作成
 	watcher, err := NewProjectWatcher(projectPath, debounceTime)
 	if err != nil {
@@ -56,6 +69,8 @@ func (m *WatchManager) StartWatching(projectPath string, debounceTime time.Durat
 
 // StopWatching - 指定したプロジェクトパスの監視を停止
 func (m *WatchManager) StopWatching(projectPath string) error {
+	projectPath = utils.AbsPath(projectPath)
+
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -77,6 +92,8 @@ func (m *WatchManager) StopWatching(projectPath string) error {
 
 // IsWatching - 指定したプロジェクトパスが監視中かどうかを確認
 func (m *WatchManager) IsWatching(projectPath string) bool {
+	projectPath = utils.AbsPath(projectPath)
+
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -103,7 +120,7 @@ func (m *WatchManager) StopAll() {
 
 	for path, watcher := range m.watchers {
 		if err := watcher.Stop(); err != nil {
-			fmt.Printf("警告: プロジェクト '%s' の監視停止に失敗しました: %v\n", path, err)
+			logger.Warn("プロジェクトの監視停止に失敗しました", "project", path, "error", err)
 		}
 	}
 
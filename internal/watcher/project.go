@@ -4,25 +4,34 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/fsnotify/fsnotify"
 	"github.com/moai/instant-backlog/internal/config"
+	"github.com/moai/instant-backlog/internal/logger"
+	"github.com/moai/instant-backlog/internal/webhooks"
+	"github.com/moai/instant-backlog/pkg/utils"
 )
 
 // ProjectWatcher - 単一プロジェクトの監視を担当する構造体
 type ProjectWatcher struct {
-	projectPath   string            // 監視対象のプロジェクトパス
-	issuesDir     string            // issuesディレクトリのパス
-	epicDir       string            // epicディレクトリのパス
-	watcher       *fsnotify.Watcher // fsnotifyのウォッチャー
-	debounceTime  time.Duration     // デバウンス時間
-	stopChan      chan struct{}     // 停止シグナル用のチャネル
-	mutex         sync.Mutex        // 並行アクセス用のミューテックス
-	isRunning     bool              // 実行中かどうかのフラグ
-	lastEventTime time.Time         // 最後のイベント時刻（デバウンス用）
-	timer         *time.Timer       // デバウンスタイマー
+	projectPath   string               // 監視対象のプロジェクトパス
+	projectsDir   string               // projectsディレクトリのパス
+	issuesDir     string               // issuesディレクトリのパス
+	epicDir       string               // epicディレクトリのパス
+	orderCSV      string               // order.csvのパス
+	watcher       *fsnotify.Watcher    // fsnotifyのウォッチャー
+	debounceTime  time.Duration        // デバウンス時間
+	stopChan      chan struct{}        // 停止シグナル用のチャネル
+	mutex         sync.Mutex           // 並行アクセス用のミューテックス
+	isRunning     bool                 // 実行中かどうかのフラグ
+	stopped       bool                 // 停止済みかどうかのフラグ（停止後に発火したデバウンスタイマーでコマンドを実行しない）
+	lastEventTime time.Time            // 最後のイベント時刻（デバウンス用）
+	timer         *time.Timer          // デバウンスタイマー
+	changed       map[string]bool      // デバウンス中に変更されたファイルのパス
+	dispatcher    *webhooks.Dispatcher // Webhookの送信
 }
 
 // NewProjectWatcher - 新しいProjectWatcherインスタンスを作成
@@ -32,25 +41,39 @@ func NewProjectWatcher(projectPath string, debounceTime time.Duration) (*Project
 		return nil, fmt.Errorf("指定されたプロジェクトパスが存在しません: %s", projectPath)
 	}
 
+	// 設定ファイルからissues/epicディレクトリを取得
+	cfg, err := config.ForProject(projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("設定の読み込みに失敗しました: %w", err)
+	}
+
 	// issuesディレクトリの検証
-	issuesDir := filepath.Join(projectPath, "issues")
+	issuesDir := cfg.IssuesDir
 	if _, err := os.Stat(issuesDir); os.IsNotExist(err) {
 		return nil, fmt.Errorf("issuesディレクトリが存在しません: %s", issuesDir)
 	}
 
 	// epicディレクトリの検証
-	epicDir := filepath.Join(projectPath, "epic")
+	epicDir := cfg.EpicDir
 	if _, err := os.Stat(epicDir); os.IsNotExist(err) {
 		return nil, fmt.Errorf("epicディレクトリが存在しません: %s", epicDir)
 	}
 
+	// 監視中に設定ファイルでWebhookを追加・変更しても送信できるよう、送信のたびに設定を読み込む
+	dispatcher := webhooks.NewDispatcher(cfg, webhooks.Options{
+		Reload: func() (*config.Config, error) { return config.ForProject(projectPath) },
+	})
+
 	return &ProjectWatcher{
 		projectPath:  projectPath,
+		projectsDir:  cfg.ProjectsDir,
 		issuesDir:    issuesDir,
 		epicDir:      epicDir,
+		orderCSV:     filepath.Clean(cfg.OrderCSV),
 		debounceTime: debounceTime,
 		stopChan:     make(chan struct{}),
 		isRunning:    false,
+		dispatcher:   dispatcher,
 	}, nil
 }
 
@@ -83,24 +106,37 @@ func (pw *ProjectWatcher) Start() error {
 		return fmt.Errorf("epicディレクトリの監視に失敗しました: %w", err)
 	}
 
+	// order.csvの手動での編集を検知するため、order.csvのあるディレクトリも監視
+	// （エディタが一時ファイルからの置き換えで保存する場合に備えてファイルではなくディレクトリを監視する）
+	if orderDir := filepath.Dir(pw.orderCSV); orderDir != pw.issuesDir && orderDir != pw.epicDir {
+		if err := watcher.Add(orderDir); err != nil {
+			watcher.Close()
+			return fmt.Errorf("order.csvのディレクトリの監視に失敗しました: %w", err)
+		}
+	}
+
 	pw.watcher = watcher
 	pw.isRunning = true
 	pw.timer = time.NewTimer(pw.debounceTime)
 	pw.timer.Stop() // 初期状態では停止しておく
 
 	// イベント処理ゴルーチンを起動
-	go pw.processEvents()
+	// Stopでpw.watcherがnilになっても安全なように、ウォッチャーを引数で渡す
+	go pw.processEvents(watcher)
+
+	// 前回の監視で送信できなかった配信も含めてWebhookを送信する
+	pw.dispatcher.Start()
 
-	fmt.Printf("===== プロジェクト '%s' の監視を開始しました =====\n", pw.projectPath)
+	logger.Info("プロジェクトの監視を開始しました", "project", pw.projectPath)
 	return nil
 }
 
 // Stop - 監視を停止
 func (pw *ProjectWatcher) Stop() error {
 	pw.mutex.Lock()
-	defer pw.mutex.Unlock()
 
 	if !pw.isRunning {
+		pw.mutex.Unlock()
 		return fmt.Errorf("ウォッチャーは実行されていません")
 	}
 
@@ -118,35 +154,64 @@ func (pw *ProjectWatcher) Stop() error {
 	}
 
 	pw.isRunning = false
-	fmt.Printf("===== プロジェクト '%s' の監視を停止しました =====\n", pw.projectPath)
+	pw.stopped = true
+	pw.changed = nil
+
+	// 既に発火したデバウンスタイマーが送信やインデックスを使えるよう、ロックを解放してから停止する
+	// （タイマーの処理は停止済みであることを確認してから実行する）
+	pw.mutex.Unlock()
+
+	// 送信できなかった配信は送信待ちのまま残り、次の監視の開始時に送信する
+	pw.dispatcher.Stop()
+
+	// 停止中の変更はインデックスに反映されないため破棄する
+	if executor, ok := commandExecutor.(IncrementalExecutor); ok {
+		executor.ReleaseIndex(pw.projectsDir)
+	}
+	logger.Info("プロジェクトの監視を停止しました", "project", pw.projectPath)
 	return nil
 }
 
 // processEvents - ファイルシステムイベントを処理
-func (pw *ProjectWatcher) processEvents() {
+func (pw *ProjectWatcher) processEvents(watcher *fsnotify.Watcher) {
 	for {
 		select {
 		case <-pw.stopChan:
 			// 停止シグナルを受信
 			return
 
-		case event, ok := <-pw.watcher.Events:
+		case event, ok := <-watcher.Events:
 			if !ok {
 				// チャネルがクローズされた
 				return
 			}
 
-			// 拡張子が.mdでない場合は無視
-			if filepath.Ext(event.Name) != ".md" {
+			// Issue・Epicのファイルとorder.csv以外は無視
+			if !pw.isTarget(event.Name) {
 				continue
 			}
 
-			// 書き込みイベントを処理
-			if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename|fsnotify.Chmod) != 0 {
+			// syncやrenameによる自身の書き込みで発生したイベントは無視する
+			// （ファイルの状態が最後に書き込んだ内容と一致する場合のみ）
+			if utils.IsSelfWrite(event.Name) {
+				logger.Debug("自身の書き込みによるファイルイベントを無視しました", "file", event.Name, "op", event.Op.String())
+				continue
+			}
+
+			// 書き込みと削除のイベントを処理
+			// （削除されたIssue・Epicはインデックスから除き、order.csvは削除された場合も作成し直す）
+			ops := fsnotify.Write | fsnotify.Create | fsnotify.Rename | fsnotify.Chmod | fsnotify.Remove
+			if event.Op&ops != 0 {
+				logger.Debug("ファイルイベントを受信しました", "file", event.Name, "op", event.Op.String())
+
 				// デバウンス処理
 				pw.mutex.Lock()
 				now := time.Now()
 				pw.lastEventTime = now
+				if pw.changed == nil {
+					pw.changed = make(map[string]bool)
+				}
+				pw.changed[event.Name] = true
 
 				// 既存のタイマーを停止して再設定
 				if pw.timer != nil {
@@ -155,9 +220,20 @@ func (pw *ProjectWatcher) processEvents() {
 
 				pw.timer = time.AfterFunc(pw.debounceTime, func() {
 					pw.mutex.Lock()
+					// 停止済みの場合は実行しない
+					if pw.stopped {
+						pw.mutex.Unlock()
+						return
+					}
 					// デバウンス時間内に新しいイベントがなかった場合のみ実行
 					if time.Since(pw.lastEventTime) >= pw.debounceTime {
-						pw.executeCommands()
+						changed := make([]string, 0, len(pw.changed))
+						for path := range pw.changed {
+							changed = append(changed, path)
+						}
+						sort.Strings(changed)
+						pw.changed = nil
+						pw.executeCommands(changed)
 					}
 					pw.mutex.Unlock()
 				})
@@ -165,34 +241,52 @@ func (pw *ProjectWatcher) processEvents() {
 				pw.mutex.Unlock()
 			}
 
-		case err, ok := <-pw.watcher.Errors:
+		case err, ok := <-watcher.Errors:
 			if !ok {
 				// チャネルがクローズされた
 				return
 			}
-			fmt.Printf("監視エラー: %v\n", err)
+			logger.Error("ファイル監視でエラーが発生しました", "project", pw.projectPath, "error", err)
 		}
 	}
 }
 
+// isTarget - 処理の対象となるファイル（issues/epicディレクトリの.mdファイルとorder.csv）かどうかを返す
+func (pw *ProjectWatcher) isTarget(path string) bool {
+	if path == pw.orderCSV {
+		return true
+	}
+	dir := filepath.Dir(path)
+	return filepath.Ext(path) == ".md" && (dir == pw.issuesDir || dir == pw.epicDir)
+}
+
 // CommandExecutor - コマンド実行のためのインターフェース
 type CommandExecutor interface {
 	ExecuteSync(cfg *config.Config) error
 	ExecuteRename(cfg *config.Config) error
 }
 
+// IncrementalExecutor - 変更されたファイルだけを処理できるコマンド実行インスタンス
+// CommandExecutorがこのインターフェースも実装している場合、監視ではsyncとrenameの代わりに増分syncを実行する
+type IncrementalExecutor interface {
+	// ExecuteIncrementalSync - 変更されたファイルのパスを指定して増分syncを実行する
+	ExecuteIncrementalSync(cfg *config.Config, changed []string) error
+	// ReleaseIndex - 監視を停止したプロジェクトのインデックスを破棄する
+	ReleaseIndex(projectsDir string)
+}
+
 // DefaultCommandExecutor - デフォルトのコマンド実行構造体
 type DefaultCommandExecutor struct{}
 
 // ExecuteSync - デフォルトのSyncCommand実行
 func (e *DefaultCommandExecutor) ExecuteSync(cfg *config.Config) error {
-	fmt.Println("警告: 本番のコマンド実行インスタンスが登録されていません")
+	logger.Warn("本番のコマンド実行インスタンスが登録されていません")
 	return nil
 }
 
 // ExecuteRename - デフォルトのRenameCommand実行
 func (e *DefaultCommandExecutor) ExecuteRename(cfg *config.Config) error {
-	fmt.Println("警告: 本番のコマンド実行インスタンスが登録されていません")
+	logger.Warn("本番のコマンド実行インスタンスが登録されていません")
 	return nil
 }
 
@@ -205,28 +299,40 @@ func SetCommandExecutor(executor CommandExecutor) {
 }
 
 // executeCommands - 関連コマンドを実行
-func (pw *ProjectWatcher) executeCommands() {
-	fmt.Printf("===== ファイル変更を検知しました: %s =====\n", pw.projectPath)
+func (pw *ProjectWatcher) executeCommands(changed []string) {
+	logger.Info("ファイル変更を検知しました", "project", pw.projectPath)
 
-	// 設定オブジェクトの作成
-	cfg := &config.Config{
-		ProjectsDir: pw.projectPath,
-		EpicDir:     filepath.Join(pw.projectPath, "epic"),
-		IssuesDir:   pw.issuesDir,
-		OrderCSV:    filepath.Join(pw.projectPath, "order.csv"),
+	// syncで追加されたWebhookの配信をすぐに送信させる
+	defer pw.dispatcher.Notify()
+
+	// 設定オブジェクトの作成（設定ファイルのステータス定義などを反映）
+	cfg, err := config.ForProject(pw.projectPath)
+	if err != nil {
+		logger.Error("設定の読み込みに失敗しました", "project", pw.projectPath, "error", err)
+		return
+	}
+
+	// 変更されたファイルだけを処理できる場合は増分syncを実行
+	if executor, ok := commandExecutor.(IncrementalExecutor); ok {
+		logger.Debug("増分syncを実行します", "project", pw.projectPath, "files", len(changed))
+		if err := executor.ExecuteIncrementalSync(cfg, changed); err != nil {
+			logger.Error("増分syncの実行に失敗しました", "project", pw.projectPath, "error", err)
+		}
+		logger.Debug("ファイル変更の処理が完了しました", "project", pw.projectPath)
+		return
 	}
 
 	// syncコマンドを実行
-	fmt.Println("syncコマンド実行中...")
+	logger.Debug("syncコマンドを実行します", "project", pw.projectPath)
 	if err := commandExecutor.ExecuteSync(cfg); err != nil {
-		fmt.Printf("エラー: syncコマンドの実行に失敗しました: %v\n", err)
+		logger.Error("syncコマンドの実行に失敗しました", "project", pw.projectPath, "error", err)
 	}
 
 	// renameコマンドを実行
-	fmt.Println("renameコマンド実行中...")
+	logger.Debug("renameコマンドを実行します", "project", pw.projectPath)
 	if err := commandExecutor.ExecuteRename(cfg); err != nil {
-		fmt.Printf("エラー: renameコマンドの実行に失敗しました: %v\n", err)
+		logger.Error("renameコマンドの実行に失敗しました", "project", pw.projectPath, "error", err)
 	}
 
-	fmt.Println("===== ファイル変更の処理が完了しました =====")
+	logger.Debug("ファイル変更の処理が完了しました", "project", pw.projectPath)
 }
@@ -0,0 +1,268 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/moai/instant-backlog/internal/config"
+	"github.com/moai/instant-backlog/internal/logger"
+)
+
+// Webhookのリクエストヘッダー
+const (
+	HeaderEvent     = "X-Backlog-Event"
+	HeaderDelivery  = "X-Backlog-Delivery"
+	HeaderSignature = "X-Backlog-Signature-256" // "sha256=" + ボディのHMAC-SHA256（16進数）
+)
+
+// 送信と再送のデフォルト値
+const (
+	DefaultTimeout     = 10 * time.Second
+	DefaultRetryBase   = 5 * time.Second
+	DefaultRetryMax    = 10 * time.Minute
+	DefaultMaxAttempts = 10
+)
+
+// Options - 配信の送信方法の設定（ゼロ値の項目はデフォルト値を使用する）
+type Options struct {
+	Client      *http.Client  // 送信に使用するHTTPクライアント
+	RetryBase   time.Duration // 最初の再送までの待ち時間（再送のたびに2倍にする）
+	RetryMax    time.Duration // 再送までの待ち時間の上限
+	MaxAttempts int           // 送信を諦めるまでの試行回数
+	// 送信のたびに最新の設定を読み込む関数（nilの場合は作成時の設定を使い続ける）
+	// 監視中に設定ファイルのwebhooksを変更しても、再起動せずに反映するために使用する
+	Reload func() (*config.Config, error)
+}
+
+// Dispatcher - 送信待ちの配信をWebhookのエンドポイントに送信する
+// 失敗した配信は指数バックオフで再送し、同じURLへの配信は保存した順に送信する
+type Dispatcher struct {
+	cfg    *config.Config // 送信に使用する設定（Reloadがある場合は送信のたびに更新する）
+	opts   Options
+	wake   chan struct{}      // 配信が追加されたことの通知
+	cancel context.CancelFunc // バックグラウンドの送信の停止
+	done   chan struct{}      // バックグラウンドの送信が終了したことの通知
+	mu     sync.Mutex         // 送信待ちのファイルを同時に処理しないためのミューテックス
+}
+
+// NewDispatcher - プロジェクトのWebhookの設定で配信を送信するインスタンスを作成する
+func NewDispatcher(cfg *config.Config, opts Options) *Dispatcher {
+	if opts.Client == nil {
+		opts.Client = &http.Client{Timeout: DefaultTimeout}
+	}
+	if opts.RetryBase <= 0 {
+		opts.RetryBase = DefaultRetryBase
+	}
+	if opts.RetryMax <= 0 {
+		opts.RetryMax = DefaultRetryMax
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = DefaultMaxAttempts
+	}
+	return &Dispatcher{cfg: cfg, opts: opts, wake: make(chan struct{}, 1)}
+}
+
+// Start - 送信待ちの配信をバックグラウンドで送信し始める
+// 前回のプロセスで送信できなかった配信もここから送信する
+func (d *Dispatcher) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	d.cancel = cancel
+	d.done = make(chan struct{})
+
+	go func() {
+		defer close(d.done)
+		for {
+			next := d.Flush(ctx)
+
+			// 再送待ちの配信があれば、その時刻か新しい配信の追加まで待つ
+			var timer *time.Timer
+			var retry <-chan time.Time
+			if !next.IsZero() {
+				timer = time.NewTimer(time.Until(next))
+				retry = timer.C
+			}
+			select {
+			case <-ctx.Done():
+			case <-d.wake:
+			case <-retry:
+			}
+			if timer != nil {
+				timer.Stop()
+			}
+			if ctx.Err() != nil {
+				return
+			}
+		}
+	}()
+}
+
+// Notify - 配信が追加されたことを通知し、すぐに送信させる
+func (d *Dispatcher) Notify() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Stop - バックグラウンドの送信を停止する（送信中の配信は送信待ちのまま残す）
+func (d *Dispatcher) Stop() {
+	if d.cancel == nil {
+		return
+	}
+	d.cancel()
+	<-d.done
+	d.cancel = nil
+}
+
+// Flush - 送信時刻になった配信を送信し、次に再送する時刻を返す（再送待ちの配信がなければゼロ値）
+func (d *Dispatcher) Flush(ctx context.Context) time.Time {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.opts.Reload != nil {
+		cfg, err := d.opts.Reload()
+		if err != nil {
+			// 古い設定で送信・破棄しないよう、設定を読み込めるまで送信を待つ
+			logger.Error("Webhookの設定の読み込みに失敗したため、送信を延期します", "error", err)
+			return time.Now().Add(d.opts.RetryBase)
+		}
+		d.cfg = cfg
+	}
+
+	deliveries, err := Pending(d.cfg)
+	if err != nil {
+		logger.Error("Webhookの送信待ちの読み込みに失敗しました", "error", err)
+		return time.Time{}
+	}
+
+	var next time.Time
+	waitUntil := func(at time.Time) {
+		if next.IsZero() || at.Before(next) {
+			next = at
+		}
+	}
+
+	// 送信できなかったURLへの後続の配信は、順序を保つため次の送信まで待たせる
+	blocked := make(map[string]bool)
+	for _, delivery := range deliveries {
+		if blocked[delivery.URL] {
+			continue
+		}
+		if delivery.NextAttempt.After(time.Now()) {
+			blocked[delivery.URL] = true
+			waitUntil(delivery.NextAttempt)
+			continue
+		}
+
+		webhook, ok := d.webhook(delivery.URL)
+		if !ok {
+			// 送信の直前に読み込んだ設定にないURLは、設定から削除されたWebhookとして扱う
+			logger.Warn("設定にないWebhookへの配信を破棄しました", "url", delivery.URL, "event", delivery.Event, "delivery", delivery.ID)
+			if err := delivery.remove(); err != nil {
+				logger.Error("Webhookの配信の削除に失敗しました", "error", err)
+			}
+			continue
+		}
+
+		err := d.send(ctx, webhook, delivery)
+		if ctx.Err() != nil {
+			// 停止した場合は失敗した回数に数えない
+			return time.Time{}
+		}
+		if err == nil {
+			logger.Info("Webhookを送信しました", "event", delivery.Event, "url", delivery.URL, "delivery", delivery.ID)
+			if err := delivery.remove(); err != nil {
+				logger.Error("Webhookの配信の削除に失敗しました", "error", err)
+			}
+			continue
+		}
+
+		delivery.Attempts++
+		delivery.LastError = err.Error()
+		if delivery.Attempts >= d.opts.MaxAttempts {
+			logger.Error("Webhookの送信を諦めました", "event", delivery.Event, "url", delivery.URL, "delivery", delivery.ID, "attempts", delivery.Attempts, "error", err)
+			if err := delivery.fail(); err != nil {
+				logger.Error("送信を諦めたWebhookの配信の移動に失敗しました", "error", err)
+			}
+			continue
+		}
+
+		blocked[delivery.URL] = true
+		delay := d.backoff(delivery.Attempts)
+		delivery.NextAttempt = time.Now().Add(delay)
+		logger.Warn("Webhookの送信に失敗したため再送します", "event", delivery.Event, "url", delivery.URL, "delivery", delivery.ID, "attempts", delivery.Attempts, "retry_in", delay, "error", err)
+		if err := delivery.save(); err != nil {
+			logger.Error("Webhookの配信の保存に失敗しました", "error", err)
+		}
+		waitUntil(delivery.NextAttempt)
+	}
+	return next
+}
+
+// send - 配信を1回送信する（2xx以外の応答は失敗として扱う）
+func (d *Dispatcher) send(ctx context.Context, webhook config.Webhook, delivery *Delivery) error {
+	secret, ok := webhook.SigningSecret()
+	if !ok {
+		return fmt.Errorf("secret_env の環境変数が設定されていません: %s", webhook.SecretEnv)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "instant-backlog")
+	req.Header.Set(HeaderEvent, delivery.Event)
+	req.Header.Set(HeaderDelivery, delivery.ID)
+	if secret != "" {
+		req.Header.Set(HeaderSignature, Sign(secret, delivery.Payload))
+	}
+
+	resp, err := d.opts.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// 接続を再利用できるよう応答を読み捨てる
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("エンドポイントがエラーを返しました: %s", resp.Status)
+	}
+	return nil
+}
+
+// backoff - 失敗した回数に応じた再送までの待ち時間を返す
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	delay := d.opts.RetryBase
+	for i := 1; i < attempts && delay < d.opts.RetryMax; i++ {
+		delay *= 2
+	}
+	return min(delay, d.opts.RetryMax)
+}
+
+// webhook - URLに対応するWebhookの設定を返す
+func (d *Dispatcher) webhook(url string) (config.Webhook, bool) {
+	for _, webhook := range d.cfg.Webhooks {
+		if webhook.URL == url {
+			return webhook, true
+		}
+	}
+	return config.Webhook{}, false
+}
+
+// Sign - ボディの署名（X-Backlog-Signature-256ヘッダーの値）を返す
+// 受信側は同じ秘密鍵で計算した値とhmac.Equalで比較して検証する
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
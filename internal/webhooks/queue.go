@@ -0,0 +1,184 @@
+package webhooks
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/moai/instant-backlog/internal/config"
+	"github.com/moai/instant-backlog/internal/logger"
+	"github.com/moai/instant-backlog/pkg/utils"
+)
+
+// failedDirName - 送信を諦めた配信を移動するディレクトリの名前（送信待ちのディレクトリ配下）
+const failedDirName = "failed"
+
+// Event - Webhookで送信するイベント（リクエストのボディ）
+type Event struct {
+	ID      string    `json:"id"` // イベントのID（再送しても変わらない）
+	Name    string    `json:"event"`
+	Project string    `json:"project"` // projectsディレクトリの絶対パス
+	Time    time.Time `json:"time"`
+	Issue   *Issue    `json:"issue,omitempty"`
+	Epic    *Epic     `json:"epic,omitempty"`
+	Order   []int     `json:"order,omitempty"` // order.changedでの変更後の並び順
+}
+
+// Issue - イベントの対象のIssue
+type Issue struct {
+	ID       int    `json:"id"`
+	Title    string `json:"title"`
+	Status   string `json:"status"`
+	Epic     int    `json:"epic"`
+	Estimate int    `json:"estimate"`
+}
+
+// Epic - イベントの対象のEpic
+type Epic struct {
+	ID     int    `json:"id"`
+	Title  string `json:"title"`
+	Status string `json:"status"`
+	From   string `json:"from,omitempty"` // 変更前のステータス
+}
+
+// Delivery - 1つのエンドポイントへのイベントの配信（送信待ちのディレクトリに1件1ファイルで保存する）
+type Delivery struct {
+	ID          string          `json:"id"` // 配信のID（X-Backlog-Deliveryヘッダーで送信する）
+	URL         string          `json:"url"`
+	Event       string          `json:"event"`
+	Payload     json.RawMessage `json:"payload"`
+	Attempts    int             `json:"attempts"`     // 失敗した送信の回数
+	NextAttempt time.Time       `json:"next_attempt"` // 次に送信する時刻
+	LastError   string          `json:"last_error,omitempty"`
+
+	path string // 保存したファイルのパス
+}
+
+// Enqueue - イベントを送信する対象のWebhookごとに配信を送信待ちのディレクトリに保存する
+// 保存した配信は監視中のDispatcherが送信する（プロセスを再起動しても失われない）
+func Enqueue(cfg *config.Config, events []Event) error {
+	if len(cfg.Webhooks) == 0 || len(events) == 0 {
+		return nil
+	}
+
+	dir := cfg.WebhookQueueDir()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("Webhookの送信待ちディレクトリの作成に失敗しました: %w", err)
+	}
+
+	project := utils.AbsPath(cfg.ProjectsDir)
+	now := time.Now()
+	for _, event := range events {
+		event.ID = newID()
+		event.Project = project
+		event.Time = now
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("Webhookのイベントの作成に失敗しました: %w", err)
+		}
+
+		for _, webhook := range cfg.Webhooks {
+			if !webhook.Accepts(event.Name) {
+				continue
+			}
+			delivery := &Delivery{ID: newID(), URL: webhook.URL, Event: event.Name, Payload: payload, NextAttempt: now}
+			// ファイル名の順に送信するため、保存した時刻をファイル名の先頭にする
+			delivery.path = filepath.Join(dir, fmt.Sprintf("%020d-%s.json", time.Now().UnixNano(), delivery.ID))
+			if err := delivery.save(); err != nil {
+				return err
+			}
+			logger.Debug("Webhookの配信を追加しました", "event", event.Name, "url", webhook.URL, "delivery", delivery.ID)
+		}
+	}
+	return nil
+}
+
+// Pending - 送信待ちの配信を保存した順に返す
+func Pending(cfg *config.Config) ([]*Delivery, error) {
+	dir := cfg.WebhookQueueDir()
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Webhookの送信待ちディレクトリの読み込みに失敗しました: %w", err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		// 書き込み途中の一時ファイル（.で始まる）は除く
+		if !entry.IsDir() && filepath.Ext(entry.Name()) == ".json" && !strings.HasPrefix(entry.Name(), ".") {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+
+	deliveries := make([]*Delivery, 0, len(names))
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Webhookの配信の読み込みに失敗しました: %w", err)
+		}
+		delivery := &Delivery{path: path}
+		if err := json.Unmarshal(data, delivery); err != nil {
+			logger.Warn("解析できないWebhookの配信を読み飛ばしました", "file", name, "error", err)
+			continue
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, nil
+}
+
+// save - 配信をファイルに保存する
+func (d *Delivery) save() error {
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return fmt.Errorf("Webhookの配信の保存に失敗しました: %w", err)
+	}
+	if err := utils.WriteFileAtomic(d.path, append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("Webhookの配信の保存に失敗しました: %w", err)
+	}
+	return nil
+}
+
+// remove - 送信した配信を送信待ちから削除する
+func (d *Delivery) remove() error {
+	if err := os.Remove(d.path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("Webhookの配信の削除に失敗しました: %w", err)
+	}
+	return nil
+}
+
+// fail - 送信を諦めた配信を送信待ちのディレクトリのfailedに移動する
+func (d *Delivery) fail() error {
+	dir := filepath.Join(filepath.Dir(d.path), failedDirName)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("送信を諦めたWebhookの配信の移動に失敗しました: %w", err)
+	}
+	if err := d.save(); err != nil {
+		return err
+	}
+	target := filepath.Join(dir, filepath.Base(d.path))
+	if err := os.Rename(d.path, target); err != nil {
+		return fmt.Errorf("送信を諦めたWebhookの配信の移動に失敗しました: %w", err)
+	}
+	d.path = target
+	return nil
+}
+
+// newID - 配信とイベントのIDを生成する
+func newID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,113 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// WriteFileAtomic - ファイルを原子的に書き込む
+// 同じディレクトリの一時ファイルに書き込んでfsyncした後、リネームで置き換えるため、
+// 書き込み途中の内容が読まれたり、異常終了で中途半端なファイルが残ったりしない。
+// 既存のファイルがある場合はそのパーミッションを引き継ぐ
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+
+	if info, err := os.Stat(path); err == nil {
+		perm = info.Mode().Perm()
+	}
+
+	// 一時ファイルは監視対象の拡張子(.md)にならないよう末尾を.tmpにする
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("一時ファイルの作成に失敗しました: %w", err)
+	}
+	tmpPath := tmp.Name()
+
+	// 失敗した場合は一時ファイルを残さない
+	committed := false
+	defer func() {
+		if !committed {
+			tmp.Close()
+			os.Remove(tmpPath)
+		}
+	}()
+
+	if _, err := tmp.Write(data); err != nil {
+		return fmt.Errorf("一時ファイルへの書き込みに失敗しました: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		return fmt.Errorf("一時ファイルの同期に失敗しました: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("一時ファイルのクローズに失敗しました: %w", err)
+	}
+	if err := os.Chmod(tmpPath, perm); err != nil {
+		return fmt.Errorf("一時ファイルのパーミッション設定に失敗しました: %w", err)
+	}
+
+	RecordSelfWrite(path, data)
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("ファイルの置き換えに失敗しました: %w", err)
+	}
+	committed = true
+
+	syncDir(dir)
+	return nil
+}
+
+// ReplaceFileAtomic - oldPathのファイルを新しい内容で置き換え、newPathに移動する
+// 内容の置き換えとファイル名の変更はそれぞれ原子的に行われるため、
+// 途中で中断しても同じファイルが2つ存在したり、ファイルが失われたりしない。
+// oldPathが存在しない場合はnewPathに書き込む。
+// newPathが既に存在する場合は上書きせず、oldPathの内容のみ更新して fs.ErrExist のエラーを返す
+func ReplaceFileAtomic(oldPath, newPath string, data []byte, perm os.FileMode) error {
+	if oldPath == newPath {
+		return WriteFileAtomic(newPath, data, perm)
+	}
+	if _, err := os.Stat(oldPath); os.IsNotExist(err) {
+		return WriteFileAtomic(newPath, data, perm)
+	}
+
+	if err := WriteFileAtomic(oldPath, data, perm); err != nil {
+		return err
+	}
+	RecordSelfRemoval(oldPath)
+	RecordSelfWrite(newPath, data)
+	if err := RenameNoReplace(oldPath, newPath); err != nil {
+		return fmt.Errorf("ファイル名の変更に失敗しました: %w", err)
+	}
+
+	syncDir(filepath.Dir(newPath))
+	return nil
+}
+
+// RenameNoReplace - リネーム先が既に存在する場合は置き換えずに fs.ErrExist を返すリネーム
+// ハードリンクを作成してからリネーム元を削除するため、確認とリネームの間に作成されたファイルを上書きしない
+func RenameNoReplace(source, target string) error {
+	err := os.Link(source, target)
+	if err == nil {
+		return os.Remove(source)
+	}
+	if errors.Is(err, fs.ErrExist) {
+		return err
+	}
+
+	// ハードリンクに対応していないファイルシステムでは確認してからリネームする
+	if _, statErr := os.Lstat(target); statErr == nil {
+		return &os.LinkError{Op: "rename", Old: source, New: target, Err: fs.ErrExist}
+	}
+	return os.Rename(source, target)
+}
+
+// syncDir - ディレクトリエントリの変更を永続化する（対応していない環境では何もしない）
+func syncDir(dir string) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	defer d.Close()
+	d.Sync()
+}
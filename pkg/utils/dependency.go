@@ -0,0 +1,155 @@
+package utils
+
+import (
+	"sort"
+
+	"github.com/moai/instant-backlog/internal/models"
+)
+
+// DanglingDependency - 存在しないIssueへの依存を表す構造体
+type DanglingDependency struct {
+	IssueID   int // 依存元のIssueのID
+	MissingID int // 存在しない依存先のID
+}
+
+// OrderViolation - order.csvで依存先より上位に配置されているIssueを表す構造体
+type OrderViolation struct {
+	IssueID     int // 上位に配置されているIssueのID
+	BlockedByID int // 下位に配置されている依存先のIssueのID
+}
+
+// FindDanglingDependencies - 存在しないIssueを参照しているblocked_byを検出する
+func FindDanglingDependencies(issues []*models.Issue) []DanglingDependency {
+	known := make(map[int]bool, len(issues))
+	for _, issue := range issues {
+		known[issue.ID] = true
+	}
+
+	var dangling []DanglingDependency
+	for _, issue := range sortedByID(issues) {
+		for _, dep := range issue.BlockedBy {
+			if !known[dep] {
+				dangling = append(dangling, DanglingDependency{IssueID: issue.ID, MissingID: dep})
+			}
+		}
+	}
+	return dangling
+}
+
+// FindDependencyCycles - blocked_byの循環参照を検出する
+// 戻り値は循環ごとのIssue IDの列（先頭のIDに戻る循環）
+func FindDependencyCycles(issues []*models.Issue) [][]int {
+	graph := make(map[int][]int, len(issues))
+	for _, issue := range issues {
+		graph[issue.ID] = issue.BlockedBy
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[int]int, len(issues))
+	var stack []int
+	var cycles [][]int
+
+	var visit func(id int)
+	visit = func(id int) {
+		state[id] = visiting
+		stack = append(stack, id)
+
+		for _, dep := range graph[id] {
+			if _, ok := graph[dep]; !ok {
+				// 存在しないIssueは循環検出の対象外
+				continue
+			}
+			switch state[dep] {
+			case unvisited:
+				visit(dep)
+			case visiting:
+				// スタック上の依存先から現在位置までが循環
+				for i, stackID := range stack {
+					if stackID == dep {
+						cycle := append([]int{}, stack[i:]...)
+						cycles = append(cycles, cycle)
+						break
+					}
+				}
+			}
+		}
+
+		stack = stack[:len(stack)-1]
+		state[id] = visited
+	}
+
+	for _, issue := range sortedByID(issues) {
+		if state[issue.ID] == unvisited {
+			visit(issue.ID)
+		}
+	}
+
+	return cycles
+}
+
+// FindOrderViolations - 並び順の中で未完了の依存先より上位にあるIssueを検出する
+// blockedByにはIssue IDごとの未完了の依存先IDを渡す
+func FindOrderViolations(order []int, blockedBy map[int][]int) []OrderViolation {
+	position := make(map[int]int, len(order))
+	for i, id := range order {
+		position[id] = i
+	}
+
+	var violations []OrderViolation
+	for i, id := range order {
+		for _, dep := range blockedBy[id] {
+			if depPos, ok := position[dep]; ok && depPos > i {
+				violations = append(violations, OrderViolation{IssueID: id, BlockedByID: dep})
+			}
+		}
+	}
+	return violations
+}
+
+// SortByDependencies - 依存先が依存元より上位になるように並び替える
+// 既存の並び順はできるだけ維持し、依存先を依存元の直前に移動する
+// 循環している依存関係は無視する
+func SortByDependencies(order []int, blockedBy map[int][]int) []int {
+	inOrder := make(map[int]bool, len(order))
+	for _, id := range order {
+		inOrder[id] = true
+	}
+
+	placed := make(map[int]bool, len(order))
+	visiting := make(map[int]bool)
+	sorted := make([]int, 0, len(order))
+
+	var visit func(id int)
+	visit = func(id int) {
+		if placed[id] || visiting[id] {
+			return
+		}
+		visiting[id] = true
+		for _, dep := range blockedBy[id] {
+			if inOrder[dep] {
+				visit(dep)
+			}
+		}
+		visiting[id] = false
+		placed[id] = true
+		sorted = append(sorted, id)
+	}
+
+	for _, id := range order {
+		visit(id)
+	}
+	return sorted
+}
+
+// sortedByID - ID順に並べたIssueのスライスを返す
+func sortedByID(issues []*models.Issue) []*models.Issue {
+	sorted := append([]*models.Issue{}, issues...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].ID < sorted[j].ID
+	})
+	return sorted
+}
@@ -3,16 +3,22 @@ package utils
 import (
 	"fmt"
 	"strings"
+
+	"github.com/moai/instant-backlog/internal/models"
 )
 
 // GenerateFilename - 指定されたパラメータからファイル名を生成する
 // 形式: {ID}_{O/C}_{タイトル}.md
+// ステータスはデフォルトのOpen/Closeとして扱う
 func GenerateFilename(id int, status, title string) string {
+	return GenerateFilenameWithStatuses(models.DefaultStatusSet(), id, status, title)
+}
+
+// GenerateFilenameWithStatuses - ステータス定義に従ってファイル名を生成する
+// 形式: {ID}_{頭文字}_{タイトル}.md
+func GenerateFilenameWithStatuses(statuses models.StatusSet, id int, status, title string) string {
 	// ステータスの頭文字を取得
-	statusChar := "O"
-	if strings.ToLower(status) == "close" {
-		statusChar = "C"
-	}
+	statusChar := statuses.Prefix(status)
 
 	// タイトルをスペースからアンダースコアに変換
 	safeTitle := strings.ReplaceAll(title, " ", "_")
@@ -23,7 +29,20 @@ func GenerateFilename(id int, status, title string) string {
 }
 
 // ParseFilename - ファイル名からID、ステータス、タイトルを抽出
+// ステータスはデフォルトのOpen/Closeとして扱い、C以外の頭文字は従来どおりOpenとみなす
 func ParseFilename(filename string) (int, string, string, error) {
+	return parseFilename(models.DefaultStatusSet(), filename, false)
+}
+
+// ParseFilenameWithStatuses - ステータス定義に従ってファイル名からID、ステータス、タイトルを抽出
+// 定義されていない頭文字はエラーになる
+func ParseFilenameWithStatuses(statuses models.StatusSet, filename string) (int, string, string, error) {
+	return parseFilename(statuses, filename, true)
+}
+
+// parseFilename - ファイル名からID、ステータス、タイトルを抽出
+// strictがfalseの場合、定義されていない頭文字は最初の未完了ステータスとして扱う
+func parseFilename(statuses models.StatusSet, filename string, strict bool) (int, string, string, error) {
 	// 拡張子を除去
 	base := strings.TrimSuffix(filename, ".md")
 
@@ -41,15 +60,18 @@ func ParseFilename(filename string) (int, string, string, error) {
 	}
 
 	// ステータスを解析
-	status := "Open"
-	if parts[1] == "C" {
-		status = "Close"
+	def, ok := statuses.FindByPrefix(parts[1])
+	if !ok {
+		if strict {
+			return 0, "", "", fmt.Errorf("無効なステータス頭文字: %s", parts[1])
+		}
+		def = statuses.InitialStatus()
 	}
 
 	// タイトルを取得
 	title := strings.ReplaceAll(parts[2], "_", " ")
 
-	return id, status, title, nil
+	return id, def.Name, title, nil
 }
 
 // sanitizeString - 特殊文字を除去して安全な文字列にする
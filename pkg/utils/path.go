@@ -0,0 +1,13 @@
+package utils
+
+import (
+	"path/filepath"
+)
+
+// AbsPath - パスを比較やキーに使えるよう絶対パスにして返す（解決できない場合は正規化したパス）
+func AbsPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return filepath.Clean(path)
+}
@@ -0,0 +1,120 @@
+package utils
+
+import (
+	"sort"
+	"strings"
+)
+
+// rankDigits - rankに使用する文字（辞書順と値の順が一致する36進数）
+const rankDigits = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+// RankBetween - 辞書順でprevとnextの間になるrankを返す
+// prevが空の場合は先頭、nextが空の場合は末尾として扱う。
+// rankは0から1の間の36進数の小数として扱い、末尾が"0"にならないように生成する
+func RankBetween(prev, next string) string {
+	if next != "" && prev >= next {
+		// 順序が不正な場合はprevの後ろに配置する
+		next = ""
+	}
+	return rankMidpoint(prev, next)
+}
+
+// rankMidpoint - prev < next を満たす2つのrankの中間の値を返す（nextが空の場合は1として扱う）
+func rankMidpoint(prev, next string) string {
+	if next != "" {
+		// 共通の接頭辞を取り除く（prevが短い場合は"0"で補う）
+		n := 0
+		for n < len(next) && rankDigitAt(prev, n) == next[n] {
+			n++
+		}
+		if n > 0 {
+			return next[:n] + rankMidpoint(trimPrefix(prev, n), next[n:])
+		}
+	}
+
+	// 最初の桁が異なる
+	digitPrev := 0
+	if prev != "" {
+		digitPrev = rankDigitIndex(prev[0])
+	}
+	digitNext := len(rankDigits)
+	if next != "" {
+		digitNext = rankDigitIndex(next[0])
+	}
+	if digitNext-digitPrev > 1 {
+		return string(rankDigits[(digitPrev+digitNext+1)/2])
+	}
+
+	// 最初の桁が連続している
+	if len(next) > 1 {
+		return next[:1]
+	}
+	return string(rankDigits[digitPrev]) + rankMidpoint(trimPrefix(prev, 1), "")
+}
+
+// rankDigitIndex - rankの文字の値を返す
+// rankに使用しない文字（大文字や記号）は、文字コードが最も近いrankの文字の値として扱う
+func rankDigitIndex(c byte) int {
+	if i := strings.IndexByte(rankDigits, c); i >= 0 {
+		return i
+	}
+	i := sort.Search(len(rankDigits), func(i int) bool { return rankDigits[i] >= c })
+	return min(i, len(rankDigits)-1)
+}
+
+// rankDigitAt - rankのi桁目の文字を返す（桁がない場合は"0"）
+func rankDigitAt(rank string, i int) byte {
+	if i < len(rank) {
+		return rank[i]
+	}
+	return rankDigits[0]
+}
+
+// trimPrefix - 先頭のn文字を取り除く（短い場合は空文字）
+func trimPrefix(rank string, n int) string {
+	if n >= len(rank) {
+		return ""
+	}
+	return rank[n:]
+}
+
+// EvenRanks - n件の項目に等間隔のrankを割り当てる
+// 後から間に挿入できるよう、すべての項目が同じ桁数の範囲で均等に分散するようにする
+func EvenRanks(n int) []string {
+	if n <= 0 {
+		return nil
+	}
+
+	base := len(rankDigits)
+	width, capacity := 1, base
+	for capacity <= n*2 {
+		width++
+		capacity *= base
+	}
+	step := capacity / (n + 1)
+
+	ranks := make([]string, n)
+	for i := range ranks {
+		value := (i + 1) * step
+		digits := make([]byte, width)
+		for j := width - 1; j >= 0; j-- {
+			digits[j] = rankDigits[value%base]
+			value /= base
+		}
+		ranks[i] = strings.TrimRight(string(digits), rankDigits[:1])
+	}
+	return ranks
+}
+
+// IsValidRank - rankとして使用できる文字列かどうかを返す
+func IsValidRank(rank string) bool {
+	if rank == "" || strings.HasSuffix(rank, rankDigits[:1]) {
+		return false
+	}
+	for i := 0; i < len(rank); i++ {
+		if strings.IndexByte(rankDigits, rank[i]) < 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,121 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"os"
+	"sync"
+	"time"
+)
+
+// SelfWriteTTL - 自身の書き込みの記録を保持する時間
+// ファイル監視のイベントは書き込みの直後に届くため、これを過ぎた記録は破棄する
+// （監視していないファイルの記録が長時間動作する監視プロセスに溜まらないようにする）
+var SelfWriteTTL = time.Minute
+
+// selfWrites - このプロセス自身が書き込んだファイルの記録
+// ファイル監視で、自身の書き込みによって発生したイベントを無視するために使用する
+var selfWrites = struct {
+	sync.Mutex
+	entries   map[string]selfWrite
+	journals  map[*[]string]bool // TrackSelfWritesで書き込んだパスを集めている一覧
+	lastPrune time.Time          // 保持する時間を過ぎた記録を最後に破棄した時刻
+}{entries: make(map[string]selfWrite), journals: make(map[*[]string]bool)}
+
+// selfWrite - 自身が最後に行った書き込みの内容
+type selfWrite struct {
+	removed bool      // ファイルを削除（リネーム元を含む）したかどうか
+	hash    [32]byte  // 書き込んだ内容のハッシュ
+	at      time.Time // 記録した時刻
+}
+
+// RecordSelfWrite - 自身がファイルに書き込む内容を記録する
+// イベントより先に記録されるよう、書き込む前に呼び出す
+func RecordSelfWrite(path string, data []byte) {
+	recordSelfWrite(path, selfWrite{hash: sha256.Sum256(data)})
+}
+
+// RecordSelfRemoval - 自身がファイルを削除することを記録する
+func RecordSelfRemoval(path string) {
+	recordSelfWrite(path, selfWrite{removed: true})
+}
+
+// RecordSelfRename - 自身がファイルをリネームすることを記録する（リネーム前に呼び出す）
+func RecordSelfRename(source, target string) {
+	data, err := os.ReadFile(source)
+	if err != nil {
+		return
+	}
+	RecordSelfRemoval(source)
+	RecordSelfWrite(target, data)
+}
+
+// IsSelfWrite - ファイルの現在の状態が自身の最後の書き込みと一致するかどうかを返す
+// 一致しない場合は他のプロセスによる変更とみなし、記録を破棄する（SelfWriteTTLを過ぎた記録も一致しないものとして扱う）
+func IsSelfWrite(path string) bool {
+	path = AbsPath(path)
+
+	selfWrites.Lock()
+	entry, ok := selfWrites.entries[path]
+	selfWrites.Unlock()
+	if !ok {
+		return false
+	}
+
+	data, err := os.ReadFile(path)
+	matched := false
+	switch {
+	case time.Since(entry.at) > SelfWriteTTL:
+	case os.IsNotExist(err):
+		matched = entry.removed
+	case err == nil:
+		matched = !entry.removed && sha256.Sum256(data) == entry.hash
+	}
+
+	if !matched {
+		selfWrites.Lock()
+		if current, ok := selfWrites.entries[path]; ok && current == entry {
+			delete(selfWrites.entries, path)
+		}
+		selfWrites.Unlock()
+	}
+	return matched
+}
+
+// TrackSelfWrites - 自身が書き込んだパスを集め始める
+// 返した関数を呼び出すと集めるのをやめ、それまでに書き込んだ（削除を含む）パスを返す
+func TrackSelfWrites() func() []string {
+	journal := new([]string)
+
+	selfWrites.Lock()
+	selfWrites.journals[journal] = true
+	selfWrites.Unlock()
+
+	return func() []string {
+		selfWrites.Lock()
+		defer selfWrites.Unlock()
+		delete(selfWrites.journals, journal)
+		return *journal
+	}
+}
+
+// recordSelfWrite - 書き込みの記録を更新し、保持する時間を過ぎた記録を破棄する
+func recordSelfWrite(path string, entry selfWrite) {
+	path = AbsPath(path)
+	entry.at = time.Now()
+
+	selfWrites.Lock()
+	defer selfWrites.Unlock()
+	// 書き込みのたびに走査しないよう、破棄はSelfWriteTTLごとに1回だけ行う
+	if entry.at.Sub(selfWrites.lastPrune) > SelfWriteTTL {
+		for recorded, old := range selfWrites.entries {
+			if entry.at.Sub(old.at) > SelfWriteTTL {
+				delete(selfWrites.entries, recorded)
+			}
+		}
+		selfWrites.lastPrune = entry.at
+	}
+	selfWrites.entries[path] = entry
+	for journal := range selfWrites.journals {
+		*journal = append(*journal, path)
+	}
+}
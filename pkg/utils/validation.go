@@ -2,48 +2,94 @@ package utils
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/moai/instant-backlog/internal/models"
 )
 
+// ValidationError - バリデーションエラー（どのFront Matterのフィールドが不正かを保持する）
+type ValidationError struct {
+	Field   string // 不正なフィールドのキー（id, title, status, epic, estimate, rank）
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
 // ValidateIssue - Issueのバリデーションを行う
+// ステータスはデフォルトのOpen/Closeとして扱う
 func ValidateIssue(issue *models.Issue) error {
+	return ValidateIssueWithStatuses(models.DefaultStatusSet(), issue)
+}
+
+// ValidateIssueWithStatuses - ステータス定義に従ってIssueのバリデーションを行う
+func ValidateIssueWithStatuses(statuses models.StatusSet, issue *models.Issue) error {
 	if issue.ID <= 0 {
-		return fmt.Errorf("ID は正の整数でなければなりません")
+		return &ValidationError{Field: "id", Message: "ID は正の整数でなければなりません"}
 	}
 
 	if issue.Title == "" {
-		return fmt.Errorf("タイトルは必須です")
+		return &ValidationError{Field: "title", Message: "タイトルは必須です"}
 	}
 
-	if issue.Status != "Open" && issue.Status != "Close" {
-		return fmt.Errorf("ステータスは 'Open' または 'Close' でなければなりません")
+	if !statuses.IsValid(issue.Status) {
+		return statusError(statuses, issue.Status)
 	}
 
 	if issue.Epic <= 0 {
-		return fmt.Errorf("Epic ID は正の整数でなければなりません")
+		return &ValidationError{Field: "epic", Message: "Epic ID は正の整数でなければなりません"}
 	}
 
 	if issue.Estimate < 0 {
-		return fmt.Errorf("見積もりポイントは非負の整数でなければなりません")
+		return &ValidationError{Field: "estimate", Message: "見積もりポイントは非負の整数でなければなりません"}
+	}
+
+	if issue.Rank != "" && !IsValidRank(issue.Rank) {
+		return &ValidationError{Field: "rank", Message: "rank は英小文字と数字のみで、末尾が 0 でない文字列でなければなりません"}
 	}
 
 	return nil
 }
 
 // ValidateEpic - Epicのバリデーションを行う
+// ステータスはデフォルトのOpen/Closeとして扱う
 func ValidateEpic(epic *models.Epic) error {
+	return ValidateEpicWithStatuses(models.DefaultStatusSet(), epic)
+}
+
+// ValidateEpicWithStatuses - ステータス定義に従ってEpicのバリデーションを行う
+func ValidateEpicWithStatuses(statuses models.StatusSet, epic *models.Epic) error {
 	if epic.ID <= 0 {
-		return fmt.Errorf("ID は正の整数でなければなりません")
+		return &ValidationError{Field: "id", Message: "ID は正の整数でなければなりません"}
 	}
 
 	if epic.Title == "" {
-		return fmt.Errorf("タイトルは必須です")
+		return &ValidationError{Field: "title", Message: "タイトルは必須です"}
 	}
 
-	if epic.Status != "Open" && epic.Status != "Close" {
-		return fmt.Errorf("ステータスは 'Open' または 'Close' でなければなりません")
+	if !statuses.IsValid(epic.Status) {
+		return statusError(statuses, epic.Status)
 	}
 
 	return nil
 }
+
+// statusError - 未定義のステータスのエラーを返す
+// 大文字小文字のみが異なる場合は、正しいステータス名を提案する
+func statusError(statuses models.StatusSet, status string) error {
+	message := fmt.Sprintf("ステータスは %s のいずれかでなければなりません", quoteNames(statuses))
+	if def, ok := statuses.FindFold(status); ok {
+		message += fmt.Sprintf("（'%s' ではなく '%s' と記述してください）", status, def.Name)
+	}
+	return &ValidationError{Field: "status", Message: message}
+}
+
+// quoteNames - ステータス名を引用符付きで列挙した文字列を返す
+func quoteNames(statuses models.StatusSet) string {
+	quoted := make([]string, 0, len(statuses))
+	for _, name := range statuses.Names() {
+		quoted = append(quoted, "'"+name+"'")
+	}
+	return strings.Join(quoted, ", ")
+}
@@ -0,0 +1,194 @@
+// test/atomic_write_test.go
+package test
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/moai/instant-backlog/internal/commands"
+	"github.com/moai/instant-backlog/internal/models"
+	"github.com/moai/instant-backlog/internal/parser"
+	"github.com/moai/instant-backlog/pkg/utils"
+)
+
+// ディレクトリに一時ファイルが残っていないことを確認
+func assertNoTempFiles(t *testing.T, dir string) {
+	t.Helper()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ディレクトリの読み込みに失敗しました: %v", err)
+	}
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), ".tmp") {
+			t.Errorf("一時ファイルが残っています: %s", entry.Name())
+		}
+	}
+}
+
+/**
+ * ファイルを一時ファイル経由で原子的に書き込めること
+ *
+ * 既存ファイルのパーミッションを引き継ぎ、一時ファイルが残らないことを確認します。
+ */
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "1_O_test.md")
+
+	if err := utils.WriteFileAtomic(path, []byte("初回"), 0644); err != nil {
+		t.Fatalf("ファイルの書き込みに失敗しました: %v", err)
+	}
+	if err := os.Chmod(path, 0600); err != nil {
+		t.Fatalf("パーミッションの変更に失敗しました: %v", err)
+	}
+	if err := utils.WriteFileAtomic(path, []byte("更新"), 0644); err != nil {
+		t.Fatalf("ファイルの書き込みに失敗しました: %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if string(data) != "更新" {
+		t.Errorf("ファイルの内容が更新されていません: %s", data)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0600 {
+		t.Errorf("既存ファイルのパーミッションが引き継がれていません: %v", info.Mode().Perm())
+	}
+	assertNoTempFiles(t, dir)
+
+	// 書き込めない場合はエラー
+	if err := utils.WriteFileAtomic(filepath.Join(dir, "missing", "x.md"), []byte("x"), 0644); err == nil {
+		t.Errorf("存在しないディレクトリへの書き込みがエラーになりません")
+	}
+}
+
+/**
+ * order.csvの書き込み中に読み込んでも、書き込み途中の内容が見えないこと
+ */
+func TestOrderCSVWriteIsAtomic(t *testing.T) {
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	makeItems := func(n int) []models.OrderCSVItem {
+		items := make([]models.OrderCSVItem, n)
+		for i := range items {
+			items[i] = models.OrderCSVItem{ID: i + 1, Title: strings.Repeat("長いタイトル", 10), Epic: 1, Estimate: 3}
+		}
+		return items
+	}
+	small, large := makeItems(100), makeItems(300)
+	if err := parser.WriteOrderCSV(cfg.OrderCSV, small); err != nil {
+		t.Fatalf("order.csvの書き込みに失敗しました: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer close(done)
+		for i := 0; i < 50; i++ {
+			items := small
+			if i%2 == 0 {
+				items = large
+			}
+			if err := parser.WriteOrderCSV(cfg.OrderCSV, items); err != nil {
+				t.Errorf("order.csvの書き込みに失敗しました: %v", err)
+				return
+			}
+		}
+	}()
+
+	// 書き込み中に読み込んでも、常に完全な内容が見えること
+	for reading := true; reading; {
+		select {
+		case <-done:
+			reading = false
+		default:
+		}
+		items, err := parser.ReadOrderCSV(cfg.OrderCSV)
+		if err != nil {
+			t.Fatalf("order.csvの読み込みに失敗しました: %v", err)
+		}
+		if len(items) != len(small) && len(items) != len(large) {
+			t.Fatalf("書き込み途中のorder.csvが読み込まれました: %d件", len(items))
+		}
+	}
+	wg.Wait()
+
+	assertNoTempFiles(t, cfg.ProjectsDir)
+}
+
+/**
+ * Epicのステータス更新で、同じEpicのファイルが重複したり失われたりしないこと
+ */
+func TestEpicStatusUpdateReplacesFile(t *testing.T) {
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	// 本文を編集済みのEpic（更新後も本文が保持されること）
+	epic := &models.Epic{ID: 1, Title: "完了するEpic", Status: "Open", Content: "編集済みの本文"}
+	if err := os.WriteFile(filepath.Join(cfg.EpicDir, "1_O_完了するEpic.md"),
+		[]byte("---\nid: 1\ntitle: 完了するEpic\nstatus: Open\n---\n\n"+epic.Content+"\n"), 0644); err != nil {
+		t.Fatalf("テスト用Epicの作成に失敗しました: %v", err)
+	}
+	createTestOrderCSV(t, cfg, []models.OrderCSVItem{})
+	createTestIssue(t, cfg, 1, "完了したIssue", "Close", 1, 1)
+
+	if err := commands.SyncCommand(cfg); err != nil {
+		t.Fatalf("syncコマンドの実行に失敗しました: %v", err)
+	}
+
+	entries, err := os.ReadDir(cfg.EpicDir)
+	if err != nil {
+		t.Fatalf("epicディレクトリの読み込みに失敗しました: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "1_C_完了するEpic.md" {
+		var names []string
+		for _, e := range entries {
+			names = append(names, e.Name())
+		}
+		t.Fatalf("Epicファイルが正しく置き換えられていません: %v", names)
+	}
+
+	updated, err := parser.ParseEpicFile(filepath.Join(cfg.EpicDir, "1_C_完了するEpic.md"))
+	if err != nil {
+		t.Fatalf("Epicファイルの解析に失敗しました: %v", err)
+	}
+	if updated.Status != "Close" || updated.Content != "編集済みの本文" {
+		t.Errorf("Epicの内容が正しく更新されていません: status=%s, content=%s", updated.Status, updated.Content)
+	}
+}
+
+/**
+ * 置き換え後のファイル名のファイルが既に存在する場合、上書きせずに内容のみ更新すること
+ *
+ * 存在を確認した後に他のプロセスがファイルを作成した場合と同じ状況で、
+ * 既存のファイルが残り、元のファイルに新しい内容が書き込まれることを確認します。
+ */
+func TestReplaceFileAtomicDoesNotOverwrite(t *testing.T) {
+	dir := t.TempDir()
+	oldPath := filepath.Join(dir, "1_O_Epic.md")
+	newPath := filepath.Join(dir, "1_C_Epic.md")
+	if err := os.WriteFile(oldPath, []byte("古い内容"), 0644); err != nil {
+		t.Fatalf("ファイルの作成に失敗しました: %v", err)
+	}
+	if err := os.WriteFile(newPath, []byte("後から作成されたファイル"), 0644); err != nil {
+		t.Fatalf("ファイルの作成に失敗しました: %v", err)
+	}
+
+	err := utils.ReplaceFileAtomic(oldPath, newPath, []byte("新しい内容"), 0644)
+	if !errors.Is(err, fs.ErrExist) {
+		t.Fatalf("既存のファイルがあるのに fs.ErrExist が返されません: %v", err)
+	}
+	if got := readFile(t, newPath); got != "後から作成されたファイル" {
+		t.Errorf("既存のファイルが上書きされています: %s", got)
+	}
+	if got := readFile(t, oldPath); got != "新しい内容" {
+		t.Errorf("元のファイルの内容が更新されていません: %s", got)
+	}
+	assertNoTempFiles(t, dir)
+}
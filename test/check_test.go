@@ -0,0 +1,180 @@
+// test/check_test.go
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/moai/instant-backlog/internal/commands"
+	"github.com/moai/instant-backlog/internal/config"
+	"github.com/moai/instant-backlog/internal/models"
+)
+
+// ディレクトリ配下のすべてのファイルの内容を取得
+func snapshotFiles(t *testing.T, dir string) map[string]string {
+	t.Helper()
+
+	files := make(map[string]string)
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		files[path] = string(data)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("ファイルの読み込みに失敗しました: %v", err)
+	}
+	return files
+}
+
+// ファイル内で指定した文字列で始まる行の行番号を返す
+func lineOf(t *testing.T, path, prefix string) int {
+	t.Helper()
+
+	for i, line := range strings.Split(readFile(t, path), "\n") {
+		if strings.HasPrefix(line, prefix) {
+			return i + 1
+		}
+	}
+	t.Fatalf("%s に %s で始まる行がありません", path, prefix)
+	return 0
+}
+
+// 検証エラーのあるバックログを作成し、カレントディレクトリをプロジェクトのルートにする
+func setupCheckProject(t *testing.T, cfg *config.Config) {
+	t.Helper()
+
+	createTestEpic(t, cfg, 1, "検証", "Open")
+	createTestOrderCSV(t, cfg, []models.OrderCSVItem{
+		{ID: 1, Title: "見積もり不正", Epic: 1, Estimate: -1},
+		{ID: 2, Title: "Epic参照切れ", Epic: 9, Estimate: 1},
+		{ID: 99, Title: "削除済み", Epic: 1, Estimate: 1},
+	})
+	createTestIssue(t, cfg, 1, "見積もり不正", "Open", 1, -1)
+	createTestIssue(t, cfg, 2, "Epic参照切れ", "Open", 9, 1)
+	t.Chdir(filepath.Dir(cfg.ProjectsDir))
+}
+
+/**
+ * checkコマンドでエラーをファイルと行番号付きで出力し、エラーとして終了すること
+ *
+ * 検証のみを行い、ファイルは一切変更しないことを確認します。
+ */
+func TestCheckReportsErrorsWithPositions(t *testing.T) {
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	setupCheckProject(t, cfg)
+
+	before := snapshotFiles(t, cfg.ProjectsDir)
+
+	var out bytes.Buffer
+	if err := commands.CheckCommand(cfg, commands.CheckOptions{}, &out); err == nil {
+		t.Errorf("エラーがあるのにcheckコマンドが成功しました")
+	}
+
+	estimateLine := lineOf(t, filepath.Join(cfg.IssuesDir, "1_O_見積もり不正.md"), "estimate:")
+	epicLine := lineOf(t, filepath.Join(cfg.IssuesDir, "2_O_Epic参照切れ.md"), "epic:")
+	expected := []string{
+		"projects/issues/1_O_見積もり不正.md:" + strconv.Itoa(estimateLine) + ": error: 見積もりポイントは非負の整数でなければなりません [invalid_field]",
+		"projects/issues/2_O_Epic参照切れ.md:" + strconv.Itoa(epicLine) + ": error: 存在しないEpic（ID=9）を参照しています [orphan_epic]",
+		"projects/order.csv:4: warning: ID=99: 対応するIssueファイルがありません [stale_order]",
+		"エラー 2件、警告 1件",
+	}
+	for _, line := range expected {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("診断結果に %q が含まれていません:\n%s", line, out.String())
+		}
+	}
+
+	// ファイルは変更されない
+	after := snapshotFiles(t, cfg.ProjectsDir)
+	if len(before) != len(after) {
+		t.Errorf("ファイルが追加または削除されています: %d → %d", len(before), len(after))
+	}
+	for path, content := range before {
+		if after[path] != content {
+			t.Errorf("ファイルが変更されています: %s", path)
+		}
+	}
+}
+
+/**
+ * JSONとGitHub Actionsのアノテーション形式で出力できること
+ */
+func TestCheckMachineReadableFormats(t *testing.T) {
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	setupCheckProject(t, cfg)
+
+	// JSON
+	var out bytes.Buffer
+	commands.CheckCommand(cfg, commands.CheckOptions{Format: commands.CheckFormatJSON}, &out)
+
+	var result struct {
+		Diagnostics []commands.DoctorProblem `json:"diagnostics"`
+		Errors      int                      `json:"errors"`
+		Warnings    int                      `json:"warnings"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+		t.Fatalf("JSONとして解析できません: %v\n%s", err, out.String())
+	}
+	if result.Errors != 2 || result.Warnings != 1 || len(result.Diagnostics) != 3 {
+		t.Errorf("JSONの診断結果が不正です: %+v", result)
+	}
+	for _, d := range result.Diagnostics {
+		if d.Kind == commands.ProblemOrphanEpic && (d.File != "projects/issues/2_O_Epic参照切れ.md" || d.Line == 0 || d.ID != 2) {
+			t.Errorf("JSONの診断結果の位置が不正です: %+v", d)
+		}
+	}
+
+	// GitHub Actionsのアノテーション
+	out.Reset()
+	commands.CheckCommand(cfg, commands.CheckOptions{Format: commands.CheckFormatGitHub}, &out)
+
+	epicLine := lineOf(t, filepath.Join(cfg.IssuesDir, "2_O_Epic参照切れ.md"), "epic:")
+	annotation := "::error file=projects/issues/2_O_Epic参照切れ.md,line=" + strconv.Itoa(epicLine) + ",title=Epic参照切れ::存在しないEpic（ID=9）を参照しています"
+	if !strings.Contains(out.String(), annotation) {
+		t.Errorf("アノテーションが出力されていません:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "::warning file=projects/order.csv,line=4,") {
+		t.Errorf("警告のアノテーションが出力されていません:\n%s", out.String())
+	}
+
+	// 不明な形式はエラー
+	if err := commands.CheckCommand(cfg, commands.CheckOptions{Format: "xml"}, &out); err == nil {
+		t.Errorf("不明な出力形式がエラーになりません")
+	}
+}
+
+/**
+ * 警告のみの場合は成功し、--strict では警告もエラーとして扱われること
+ */
+func TestCheckWarningsAndStrict(t *testing.T) {
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	createTestEpic(t, cfg, 1, "検証", "Open")
+	createTestOrderCSV(t, cfg, []models.OrderCSVItem{})
+	createTestIssue(t, cfg, 1, "order.csvにないIssue", "Open", 1, 1)
+	t.Chdir(filepath.Dir(cfg.ProjectsDir))
+
+	var out bytes.Buffer
+	if err := commands.CheckCommand(cfg, commands.CheckOptions{}, &out); err != nil {
+		t.Errorf("警告のみでcheckコマンドが失敗しました: %v\n%s", err, out.String())
+	}
+	if !strings.Contains(out.String(), "エラー 0件、警告 1件") {
+		t.Errorf("診断結果が不正です:\n%s", out.String())
+	}
+
+	out.Reset()
+	if err := commands.CheckCommand(cfg, commands.CheckOptions{Strict: true}, &out); err == nil {
+		t.Errorf("--strictで警告がエラーになりません")
+	}
+}
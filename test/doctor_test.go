@@ -0,0 +1,180 @@
+// test/doctor_test.go
+package test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/moai/instant-backlog/internal/commands"
+	"github.com/moai/instant-backlog/internal/config"
+	"github.com/moai/instant-backlog/internal/models"
+)
+
+// 問題のあるバックログを作成
+func setupBrokenBacklog(t *testing.T, cfg *config.Config) {
+	t.Helper()
+
+	createTestEpic(t, cfg, 1, "正常なEpic", "Open")
+	createTestIssue(t, cfg, 1, "正常なIssue", "Open", 1, 3)
+
+	// 内容が同一の重複ファイル（修正可能）
+	createTestIssue(t, cfg, 2, "重複するIssue", "Open", 1, 2)
+	data, err := os.ReadFile(filepath.Join(cfg.IssuesDir, "2_O_重複するIssue.md"))
+	if err != nil {
+		t.Fatalf("テスト用Issueの読み込みに失敗しました: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(cfg.IssuesDir, "2_O_重複するIssue_copy.md"), data, 0644); err != nil {
+		t.Fatalf("テスト用Issueの作成に失敗しました: %v", err)
+	}
+
+	// ファイル名がFront Matterと一致しない（修正可能）
+	createTestIssue(t, cfg, 3, "ステータス変更済み", "Close", 1, 1)
+	if err := os.Rename(filepath.Join(cfg.IssuesDir, "3_C_ステータス変更済み.md"), filepath.Join(cfg.IssuesDir, "3_O_ステータス変更済み.md")); err != nil {
+		t.Fatalf("テスト用Issueのリネームに失敗しました: %v", err)
+	}
+
+	// 存在しないEpicを参照（手動で修正が必要）
+	createTestIssue(t, cfg, 4, "Epic参照切れ", "Open", 9, 1)
+
+	// 不正な値（手動で修正が必要）
+	createTestIssue(t, cfg, 5, "見積もり不正", "Open", 1, -1)
+
+	// order.csv: ファイルのない行・完了済みの行・重複行（修正可能）、Issue 4, 5 が不足（修正可能）
+	createTestOrderCSV(t, cfg, []models.OrderCSVItem{
+		{ID: 1, Title: "正常なIssue", Epic: 1, Estimate: 3},
+		{ID: 2, Title: "重複するIssue", Epic: 1, Estimate: 2},
+		{ID: 3, Title: "ステータス変更済み", Epic: 1, Estimate: 1},
+		{ID: 1, Title: "正常なIssue", Epic: 1, Estimate: 3},
+		{ID: 99, Title: "削除済み", Epic: 1, Estimate: 1},
+	})
+}
+
+// 指定した種類の問題の数を数える
+func countProblems(report *commands.DoctorReport, kind string) int {
+	count := 0
+	for _, p := range report.Problems {
+		if p.Kind == kind {
+			count++
+		}
+	}
+	return count
+}
+
+/**
+ * doctorコマンドでバックログの不整合を検出できること
+ *
+ * ID重複、ファイル名の不一致、存在しないEpicへの参照、不正な値、
+ * order.csvの不要な行と不足を報告し、ファイルは変更しないことを確認します。
+ */
+func TestDoctorReportsProblems(t *testing.T) {
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	setupBrokenBacklog(t, cfg)
+
+	report, err := commands.Diagnose(cfg, commands.DoctorOptions{})
+	if err != nil {
+		t.Fatalf("診断に失敗しました: %v", err)
+	}
+
+	expected := map[string]int{
+		commands.ProblemDuplicateID:      1,
+		commands.ProblemFilenameMismatch: 1,
+		commands.ProblemOrphanEpic:       1,
+		commands.ProblemInvalidField:     1,
+		commands.ProblemStaleOrder:       3,
+		commands.ProblemMissingOrder:     2,
+	}
+	for kind, count := range expected {
+		if got := countProblems(report, kind); got != count {
+			t.Errorf("%s の件数が不正です: 期待値=%d, 実際=%d", kind, count, got)
+		}
+	}
+
+	// 診断のみではファイルを変更しない
+	if !fileExists(filepath.Join(cfg.IssuesDir, "2_O_重複するIssue_copy.md")) || !fileExists(filepath.Join(cfg.IssuesDir, "3_O_ステータス変更済み.md")) {
+		t.Errorf("--fixなしでファイルが変更されています")
+	}
+	if ids := readOrderIDs(t, cfg); len(ids) != 5 {
+		t.Errorf("--fixなしでorder.csvが変更されています: %v", ids)
+	}
+
+	// 問題が残っている場合はエラーになり、内容が出力される
+	var out bytes.Buffer
+	if err := commands.DoctorCommand(cfg, commands.DoctorOptions{}, &out); err == nil {
+		t.Errorf("問題が残っているのにエラーになりません")
+	}
+	if !strings.Contains(out.String(), "存在しないEpic（ID=9）を参照しています") || !strings.Contains(out.String(), "--fix で修正できます") {
+		t.Errorf("診断結果が出力されていません:\n%s", out.String())
+	}
+}
+
+/**
+ * doctor --fix で安全に修正できる問題のみが修正されること
+ */
+func TestDoctorFix(t *testing.T) {
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	setupBrokenBacklog(t, cfg)
+
+	var out bytes.Buffer
+	if err := commands.DoctorCommand(cfg, commands.DoctorOptions{Fix: true}, &out); err == nil {
+		t.Errorf("手動での修正が必要な問題が残っているのにエラーになりません")
+	}
+	if !strings.Contains(out.String(), "7件の問題を修正しました") {
+		t.Errorf("修正結果が出力されていません:\n%s", out.String())
+	}
+
+	// 同一内容の重複ファイルは削除され、ファイル名は修正される
+	if fileExists(filepath.Join(cfg.IssuesDir, "2_O_重複するIssue_copy.md")) {
+		t.Errorf("重複ファイルが削除されていません")
+	}
+	if !fileExists(filepath.Join(cfg.IssuesDir, "2_O_重複するIssue.md")) {
+		t.Errorf("重複ファイルの元のファイルが削除されています")
+	}
+	if !fileExists(filepath.Join(cfg.IssuesDir, "3_C_ステータス変更済み.md")) {
+		t.Errorf("ファイル名が修正されていません")
+	}
+
+	// order.csvは不要な行が削除され、不足していたIssueが追加される
+	ids := readOrderIDs(t, cfg)
+	if len(ids) != 4 || ids[0] != 1 || ids[1] != 2 || ids[2] != 4 || ids[3] != 5 {
+		t.Errorf("order.csvが正しく修正されていません: %v", ids)
+	}
+
+	// 再診断では手動での修正が必要な問題のみが残る
+	report, err := commands.Diagnose(cfg, commands.DoctorOptions{})
+	if err != nil {
+		t.Fatalf("診断に失敗しました: %v", err)
+	}
+	if report.Remaining() != 2 || report.Fixable() != 0 ||
+		countProblems(report, commands.ProblemOrphanEpic) != 1 || countProblems(report, commands.ProblemInvalidField) != 1 {
+		for _, p := range report.Problems {
+			t.Logf("%s: %s", p.Kind, p.Message)
+		}
+		t.Errorf("修正後に残っている問題が不正です")
+	}
+}
+
+/**
+ * 問題がない場合はエラーにならないこと
+ */
+func TestDoctorHealthyBacklog(t *testing.T) {
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	createTestEpic(t, cfg, 1, "正常なEpic", "Open")
+	createTestOrderCSV(t, cfg, []models.OrderCSVItem{{ID: 1, Title: "正常なIssue", Epic: 1, Estimate: 3}})
+	createTestIssue(t, cfg, 1, "正常なIssue", "Open", 1, 3)
+	createTestIssue(t, cfg, 2, "完了したIssue", "Close", 1, 1)
+
+	var out bytes.Buffer
+	if err := commands.DoctorCommand(cfg, commands.DoctorOptions{}, &out); err != nil {
+		t.Errorf("問題がないのにエラーになりました: %v\n%s", err, out.String())
+	}
+	if !strings.Contains(out.String(), "問題は見つかりませんでした") {
+		t.Errorf("診断結果が出力されていません:\n%s", out.String())
+	}
+}
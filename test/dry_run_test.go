@@ -0,0 +1,141 @@
+// test/dry_run_test.go
+package test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/moai/instant-backlog/internal/commands"
+	"github.com/moai/instant-backlog/internal/config"
+	"github.com/moai/instant-backlog/internal/models"
+)
+
+// sync で order.csv・Epic・ファイル名のすべてが変更されるバックログを作成
+func setupDryRunProject(t *testing.T, cfg *config.Config) {
+	t.Helper()
+
+	createTestEpic(t, cfg, 1, "完了するEpic", "Open")
+	createTestEpic(t, cfg, 2, "継続するEpic", "Open")
+	createTestOrderCSV(t, cfg, []models.OrderCSVItem{
+		{ID: 1, Title: "完了したIssue", Epic: 1, Estimate: 1},
+		{ID: 3, Title: "ファイル名が古いIssue", Epic: 2, Estimate: 1},
+	})
+	createTestIssue(t, cfg, 1, "完了したIssue", "Close", 1, 1)
+	createTestIssue(t, cfg, 2, "新しいIssue", "Open", 2, 3)
+
+	// Front Matterは完了済みだがファイル名は未完了のまま
+	createTestIssue(t, cfg, 3, "ファイル名が古いIssue", "Close", 2, 1)
+	if err := os.Rename(filepath.Join(cfg.IssuesDir, "3_C_ファイル名が古いIssue.md"), filepath.Join(cfg.IssuesDir, "3_O_ファイル名が古いIssue.md")); err != nil {
+		t.Fatalf("テスト用Issueのリネームに失敗しました: %v", err)
+	}
+}
+
+// ファイルが変更されていないことを確認
+func assertUnchanged(t *testing.T, before, after map[string]string) {
+	t.Helper()
+
+	if len(before) != len(after) {
+		t.Errorf("ファイルが追加または削除されています: %d → %d", len(before), len(after))
+	}
+	for path, content := range before {
+		if after[path] != content {
+			t.Errorf("ファイルが変更されています: %s", path)
+		}
+	}
+}
+
+/**
+ * sync --dry-run で変更内容を表示し、ファイルは変更しないこと
+ *
+ * order.csvの差分、Epicのステータス変更、ファイル名の変更が表示され、
+ * 実際のsyncで表示どおりの変更が行われることを確認します。
+ */
+func TestSyncDryRun(t *testing.T) {
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	setupDryRunProject(t, cfg)
+
+	before := snapshotFiles(t, cfg.ProjectsDir)
+
+	var out bytes.Buffer
+	if err := commands.SyncDryRunCommand(cfg, commands.SyncOptions{}, &out); err != nil {
+		t.Fatalf("sync --dry-runの実行に失敗しました: %v", err)
+	}
+	assertUnchanged(t, before, snapshotFiles(t, cfg.ProjectsDir))
+
+	expected := []string{
+		"order.csv:",
+		"  - 1 完了したIssue",
+		"  - 3 ファイル名が古いIssue",
+		"  + 2 新しいIssue",
+		"Epicのステータス:",
+		"  1 完了するEpic: Open → Close",
+		"ファイル名の変更:",
+		"  issues/3_O_ファイル名が古いIssue.md → 3_C_ファイル名が古いIssue.md",
+	}
+	for _, line := range expected {
+		if !strings.Contains(out.String(), line+"\n") {
+			t.Errorf("変更内容に %q が含まれていません:\n%s", line, out.String())
+		}
+	}
+	// ステータスを変更するEpicのファイル名はEpicの更新に含まれる
+	if strings.Contains(out.String(), "epic/") {
+		t.Errorf("Epicのファイル名の変更が重複して表示されています:\n%s", out.String())
+	}
+
+	// 実際のsyncで表示どおりに変更される
+	if err := commands.SyncCommand(cfg); err != nil {
+		t.Fatalf("syncコマンドの実行に失敗しました: %v", err)
+	}
+	if ids := readOrderIDs(t, cfg); len(ids) != 1 || ids[0] != 2 {
+		t.Errorf("order.csvが変更内容どおりになっていません: %v", ids)
+	}
+	if !fileExists(filepath.Join(cfg.EpicDir, "1_C_完了するEpic.md")) {
+		t.Errorf("Epicのステータスが変更内容どおりになっていません")
+	}
+	if !fileExists(filepath.Join(cfg.IssuesDir, "3_C_ファイル名が古いIssue.md")) {
+		t.Errorf("ファイル名が変更内容どおりになっていません")
+	}
+
+	// 同期後は変更がない
+	out.Reset()
+	if err := commands.SyncDryRunCommand(cfg, commands.SyncOptions{}, &out); err != nil {
+		t.Fatalf("sync --dry-runの実行に失敗しました: %v", err)
+	}
+	if strings.TrimSpace(out.String()) != "変更はありません" {
+		t.Errorf("同期後に変更内容が表示されています:\n%s", out.String())
+	}
+}
+
+/**
+ * rename --dry-run でファイル名の変更予定と衝突を表示し、ファイルは変更しないこと
+ */
+func TestRenameDryRun(t *testing.T) {
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	setupDryRunProject(t, cfg)
+
+	// 変更先が既に存在するファイル
+	setupRenameConflict(t, cfg, "リネーム元の本文", "既存の本文")
+
+	before := snapshotFiles(t, cfg.ProjectsDir)
+
+	var out bytes.Buffer
+	if err := commands.RenameDryRunCommand(cfg, &out); err != nil {
+		t.Fatalf("rename --dry-runの実行に失敗しました: %v", err)
+	}
+	assertUnchanged(t, before, snapshotFiles(t, cfg.ProjectsDir))
+
+	expected := []string{
+		"  issues/3_O_ファイル名が古いIssue.md → 3_C_ファイル名が古いIssue.md\n",
+		"  issues/1_O_衝突するIssue.md → 1_C_衝突するIssue.md（変更先が既に存在します。on_conflict=skip に従って処理します）\n",
+	}
+	for _, line := range expected {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("変更内容に %q が含まれていません:\n%s", line, out.String())
+		}
+	}
+}
@@ -0,0 +1,136 @@
+// test/frontmatter_preservation_test.go
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/moai/instant-backlog/internal/commands"
+	"github.com/moai/instant-backlog/internal/parser"
+)
+
+/**
+ * Front Matterの未知のフィールドとコメントが書き換え時に保持されること
+ *
+ * Epicの自動クローズなどでツールがファイルを書き換える場合でも、
+ * ユーザーが追加したフィールド（links, owners など）やコメント、キーの順序が
+ * 失われず、ツールが管理するフィールドのみが更新されることを確認します。
+ */
+func TestEpicRewritePreservesUnknownFieldsAndComments(t *testing.T) {
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	epicMarkdown := `---
+# エピックの管理情報
+id: 1
+owners:
+  - alice
+  - bob
+title: "保持テスト"
+status: "Open" # "Open" または "Close"
+links:
+  design: https://example.com/design # 設計資料
+notes: 手動で追加したメモ
+---
+
+エピック本文
+`
+	epicPath := filepath.Join(cfg.EpicDir, "1_O_保持テスト.md")
+	if err := os.WriteFile(epicPath, []byte(epicMarkdown), 0644); err != nil {
+		t.Fatalf("テスト用Epicファイルの作成に失敗しました: %v", err)
+	}
+	createTestIssue(t, cfg, 1, "完了タスク", "Close", 1, 3)
+
+	// syncによりEpicが自動クローズされる
+	if err := commands.SyncCommand(cfg); err != nil {
+		t.Fatalf("syncコマンドの実行に失敗しました: %v", err)
+	}
+
+	closedPath := filepath.Join(cfg.EpicDir, "1_C_保持テスト.md")
+	content, err := os.ReadFile(closedPath)
+	if err != nil {
+		t.Fatalf("更新後Epicファイルの読み込みに失敗しました: %v", err)
+	}
+	text := string(content)
+
+	// ツールが管理するフィールドのみが更新されていること
+	for _, expected := range []string{
+		"# エピックの管理情報",
+		`status: "Close" # "Open" または "Close"`,
+		"design: https://example.com/design # 設計資料",
+		"notes: 手動で追加したメモ",
+		"owners:\n  - alice",
+		"エピック本文",
+	} {
+		if !strings.Contains(text, expected) {
+			t.Errorf("Front Matterの内容が保持されていません: %q\n実際の内容:\n%s", expected, text)
+		}
+	}
+
+	// キーの順序が維持されていること
+	order := []string{"id:", "owners:", "title:", "status:", "links:", "notes:"}
+	last := -1
+	for _, key := range order {
+		pos := strings.Index(text, key)
+		if pos <= last {
+			t.Errorf("キーの順序が維持されていません: %s\n実際の内容:\n%s", key, text)
+			break
+		}
+		last = pos
+	}
+}
+
+/**
+ * 読み込んだIssueを書き戻すと、変更したフィールドのみが反映されること
+ */
+func TestIssueRoundTripUpdatesOnlyOwnedFields(t *testing.T) {
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	issueMarkdown := `---
+id: 3
+title: 'ラウンドトリップ'
+status: Open
+epic: 1
+estimate: 2
+blocked_by: [1, 2]
+assignee: carol # 担当者
+---
+
+本文
+`
+	issuePath := filepath.Join(cfg.IssuesDir, "3_O_ラウンドトリップ.md")
+	if err := os.WriteFile(issuePath, []byte(issueMarkdown), 0644); err != nil {
+		t.Fatalf("テスト用Issueファイルの作成に失敗しました: %v", err)
+	}
+
+	issue, err := parser.ParseIssueFile(issuePath)
+	if err != nil {
+		t.Fatalf("Issueファイルの読み込みに失敗しました: %v", err)
+	}
+
+	// 見積もりを変更し、依存関係を解除する
+	issue.Estimate = 5
+	issue.BlockedBy = nil
+
+	md, err := parser.GenerateMarkdown(issue, issue.Content)
+	if err != nil {
+		t.Fatalf("マークダウンの生成に失敗しました: %v", err)
+	}
+	text := string(md)
+
+	if !strings.Contains(text, "estimate: 5") {
+		t.Errorf("変更したフィールドが反映されていません:\n%s", text)
+	}
+	if strings.Contains(text, "blocked_by") {
+		t.Errorf("解除した依存関係が残っています:\n%s", text)
+	}
+	if !strings.Contains(text, "title: 'ラウンドトリップ'") {
+		t.Errorf("変更していないフィールドの書式が変わっています:\n%s", text)
+	}
+	if !strings.Contains(text, "assignee: carol # 担当者") {
+		t.Errorf("未知のフィールドとコメントが保持されていません:\n%s", text)
+	}
+}
@@ -0,0 +1,320 @@
+// test/hooks_test.go
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/moai/instant-backlog/internal/commands"
+	"github.com/moai/instant-backlog/internal/config"
+	"github.com/moai/instant-backlog/internal/hooks"
+	"github.com/moai/instant-backlog/internal/logger"
+	"github.com/moai/instant-backlog/internal/models"
+)
+
+// フックのテストはシェルスクリプトを使用するため、Windowsではスキップする
+func skipHooksOnWindows(t *testing.T) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("フックのテストはsh前提のためWindowsではスキップします")
+	}
+}
+
+/**
+ * 設定ファイルのhooksで、イベントごとに1つの文字列またはリストでコマンドを指定できること
+ */
+func TestHooksConfig(t *testing.T) {
+	rootDir := setupProjectRoot(t, "hooks:\n  on_issue_closed: ./notify.sh\n  after_sync:\n    - echo 1\n    - echo 2\nhook_timeout: 5s\n")
+	cfg, err := config.Load(config.LoadOptions{ProjectDir: rootDir})
+	if err != nil {
+		t.Fatalf("設定の読み込みに失敗しました: %v", err)
+	}
+	if got := fmt.Sprint(cfg.Hooks[config.HookIssueClosed]); got != "[./notify.sh]" {
+		t.Errorf("文字列で指定したフックが読み込まれていません: %s", got)
+	}
+	if got := fmt.Sprint(cfg.Hooks[config.HookAfterSync]); got != "[echo 1 echo 2]" {
+		t.Errorf("リストで指定したフックが読み込まれていません: %s", got)
+	}
+	if cfg.HookTimeLimit() != 5*time.Second {
+		t.Errorf("hook_timeoutが読み込まれていません: %s", cfg.HookTimeLimit())
+	}
+
+	t.Setenv(config.EnvHookTimeout, "1500")
+	if cfg, err = config.Load(config.LoadOptions{ProjectDir: rootDir}); err != nil || cfg.HookTimeLimit() != 1500*time.Millisecond {
+		t.Errorf("環境変数でhook_timeoutを上書きできません: %v", err)
+	}
+
+	rootDir = setupProjectRoot(t, "hooks:\n  on_issue_deleted: echo 1\n")
+	if _, err := config.Load(config.LoadOptions{ProjectDir: rootDir}); err == nil {
+		t.Errorf("不明なイベントのフックがエラーになりません")
+	}
+	if (&config.Config{}).HookTimeLimit() != config.DefaultHookTimeout {
+		t.Errorf("hook_timeoutのデフォルト値が不正です")
+	}
+}
+
+/**
+ * syncで発生したイベントごとに、イベントのJSONを標準入力、内容を環境変数としてフックが実行されること
+ *
+ * Issue1・2を完了にするとon_issue_closedが2回、Epic1が完了になるとon_epic_closedが実行されます。
+ * 新しく追加したIssue3ではon_issue_createdが実行され、最後にafter_syncが1回実行されます。
+ */
+func TestSyncRunsHooks(t *testing.T) {
+	skipHooksOnWindows(t)
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	createTestEpic(t, cfg, 1, "Epic1", "Open")
+	createTestEpic(t, cfg, 2, "Epic2", "Open")
+	createTestIssue(t, cfg, 1, "Issue1", "Open", 1, 1)
+	createTestIssue(t, cfg, 2, "Issue2", "Open", 1, 2)
+	if err := commands.SyncCommand(cfg); err != nil {
+		t.Fatalf("syncコマンドの実行に失敗しました: %v", err)
+	}
+
+	outDir := t.TempDir()
+	cfg.BaseDir = outDir
+	cfg.Hooks = map[string]config.HookCommands{
+		config.HookIssueClosed:  {`echo "$IB_HOOK_EVENT $IB_HOOK_ISSUE_ID $IB_HOOK_ISSUE_TITLE $IB_HOOK_ISSUE_STATUS" >> events.log`},
+		config.HookIssueCreated: {`echo "$IB_HOOK_EVENT $IB_HOOK_ISSUE_ID $IB_HOOK_ISSUE_EPIC" >> events.log`},
+		config.HookEpicClosed:   {`echo "$IB_HOOK_EVENT $IB_HOOK_EPIC_ID $IB_HOOK_EPIC_STATUS" >> events.log`, `cat > epic.json`},
+		config.HookAfterSync:    {`echo "$IB_HOOK_EVENT" >> events.log`, `cat > after_sync.json`},
+	}
+
+	rewriteIssue(t, filepath.Join(cfg.IssuesDir, "1_O_Issue1.md"), "status: Open", "status: Close")
+	rewriteIssue(t, filepath.Join(cfg.IssuesDir, "2_O_Issue2.md"), "status: Open", "status: Close")
+	createTestIssue(t, cfg, 3, "Issue3", "Open", 2, 1)
+	if err := commands.SyncCommand(cfg); err != nil {
+		t.Fatalf("syncコマンドの実行に失敗しました: %v", err)
+	}
+
+	expected := "on_issue_created 3 2\non_issue_closed 1 Issue1 Close\non_issue_closed 2 Issue2 Close\non_epic_closed 1 Close\nafter_sync\n"
+	if got := readFile(t, filepath.Join(outDir, "events.log")); got != expected {
+		t.Errorf("フックの実行内容が不正です:\n期待値:\n%s\n実際:\n%s", expected, got)
+	}
+
+	var epicEvent hooks.Event
+	if err := json.Unmarshal([]byte(readFile(t, filepath.Join(outDir, "epic.json"))), &epicEvent); err != nil {
+		t.Fatalf("標準入力のJSONを解析できません: %v", err)
+	}
+	if epicEvent.Name != config.HookEpicClosed || epicEvent.Epic == nil || epicEvent.Epic.ID != 1 || epicEvent.Epic.From != "Open" || epicEvent.Project == "" {
+		t.Errorf("on_epic_closedのイベントが不正です: %+v", epicEvent)
+	}
+
+	var syncEvent hooks.Event
+	if err := json.Unmarshal([]byte(readFile(t, filepath.Join(outDir, "after_sync.json"))), &syncEvent); err != nil {
+		t.Fatalf("標準入力のJSONを解析できません: %v", err)
+	}
+	if s := syncEvent.Sync; s == nil || s.Issues != 1 || fmt.Sprint(s.Created, s.Closed, s.EpicsClosed) != "[3] [1 2] [1]" {
+		t.Errorf("after_syncのイベントが不正です: %+v", s)
+	}
+
+	// 変更がないsyncではafter_syncだけが実行される
+	if err := commands.SyncCommand(cfg); err != nil {
+		t.Fatalf("syncコマンドの実行に失敗しました: %v", err)
+	}
+	if got := readFile(t, filepath.Join(outDir, "events.log")); got != expected+"after_sync\n" {
+		t.Errorf("変更がないsyncでのフックの実行内容が不正です:\n%s", got)
+	}
+}
+
+/**
+ * 再オープンしたIssueや、手でorder.csvから削除した行のIssueがsyncでorder.csvに追加されても、
+ * on_issue_createdが実行されないこと
+ */
+func TestSyncHooksSkipKnownIssues(t *testing.T) {
+	skipHooksOnWindows(t)
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	createTestEpic(t, cfg, 1, "Epic1", "Open")
+	createTestIssue(t, cfg, 1, "Issue1", "Open", 1, 1)
+	createTestIssue(t, cfg, 2, "Issue2", "Open", 1, 1)
+	if err := commands.SyncCommand(cfg); err != nil {
+		t.Fatalf("syncコマンドの実行に失敗しました: %v", err)
+	}
+
+	outDir := t.TempDir()
+	cfg.BaseDir = outDir
+	cfg.Hooks = map[string]config.HookCommands{
+		config.HookIssueCreated: {`echo "$IB_HOOK_EVENT $IB_HOOK_ISSUE_ID" >> events.log`},
+	}
+	sync := func() {
+		t.Helper()
+		if err := commands.SyncCommand(cfg); err != nil {
+			t.Fatalf("syncコマンドの実行に失敗しました: %v", err)
+		}
+	}
+
+	// Issue1を完了にしてから再オープンする
+	rewriteIssue(t, filepath.Join(cfg.IssuesDir, "1_O_Issue1.md"), "status: Open", "status: Close")
+	sync()
+	rewriteIssue(t, filepath.Join(cfg.IssuesDir, "1_C_Issue1.md"), "status: Close", "status: Open")
+	sync()
+
+	// Issue2の行を手でorder.csvから削除する
+	createTestOrderCSV(t, cfg, []models.OrderCSVItem{{ID: 1, Title: "Issue1", Epic: 1, Estimate: 1}})
+	sync()
+
+	if fileExists(filepath.Join(outDir, "events.log")) {
+		t.Errorf("既存のIssueでon_issue_createdが実行されています:\n%s", readFile(t, filepath.Join(outDir, "events.log")))
+	}
+	if ids := readOrderIDs(t, cfg); fmt.Sprint(ids) != "[1 2]" {
+		t.Errorf("既存のIssueがorder.csvに追加されていません: %v", ids)
+	}
+
+	// 新しいIssueでは実行される
+	createTestIssue(t, cfg, 3, "Issue3", "Open", 1, 1)
+	sync()
+	if got := readFile(t, filepath.Join(outDir, "events.log")); got != "on_issue_created 3\n" {
+		t.Errorf("新しいIssueでon_issue_createdが実行されていません:\n%s", got)
+	}
+}
+
+/**
+ * 失敗したフックやタイムアウトしたフックは警告として記録され、syncと後続のフックは続行されること
+ */
+func TestHookFailureAndTimeout(t *testing.T) {
+	skipHooksOnWindows(t)
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	logs := captureLogs(t, logger.Options{})
+
+	createTestEpic(t, cfg, 1, "Epic1", "Open")
+	createTestIssue(t, cfg, 1, "Issue1", "Open", 1, 1)
+
+	outDir := t.TempDir()
+	cfg.BaseDir = outDir
+	cfg.HookTimeout = 200 * time.Millisecond
+	cfg.Hooks = map[string]config.HookCommands{
+		config.HookAfterSync: {"echo 通知に失敗しました >&2; exit 3", "sleep 10", "touch done"},
+	}
+
+	started := time.Now()
+	if err := commands.SyncCommand(cfg); err != nil {
+		t.Fatalf("フックの失敗でsyncが失敗しています: %v", err)
+	}
+	if elapsed := time.Since(started); elapsed > 5*time.Second {
+		t.Errorf("タイムアウトしたフックが停止されていません: %s", elapsed)
+	}
+
+	if !fileExists(filepath.Join(outDir, "done")) {
+		t.Errorf("失敗したフックの後続のフックが実行されていません")
+	}
+	for _, message := range []string{"フックの実行に失敗しました", "exit status 3", "通知に失敗しました", "フックがタイムアウトしたため停止しました"} {
+		if !strings.Contains(logs.String(), message) {
+			t.Errorf("ログが出力されていません: %s\n%s", message, logs.String())
+		}
+	}
+	if ids := readOrderIDs(t, cfg); fmt.Sprint(ids) != "[1]" {
+		t.Errorf("order.csvが同期されていません: %v", ids)
+	}
+}
+
+/**
+ * Epicのステータス更新でEpicが完了になった場合にon_epic_closedのフックが実行されること
+ */
+func TestUpdateEpicStatusRunsHooks(t *testing.T) {
+	skipHooksOnWindows(t)
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	createTestEpic(t, cfg, 1, "Epic1", "Open")
+	createTestIssue(t, cfg, 1, "Issue1", "Close", 1, 1)
+
+	outDir := t.TempDir()
+	cfg.BaseDir = outDir
+	cfg.Hooks = map[string]config.HookCommands{
+		config.HookEpicClosed: {`echo "$IB_HOOK_EPIC_ID $IB_HOOK_EPIC_TITLE" > epic.log`},
+	}
+
+	if err := commands.UpdateEpicStatusBasedOnIssues(cfg); err != nil {
+		t.Fatalf("Epicステータスの更新に失敗しました: %v", err)
+	}
+	if got := readFile(t, filepath.Join(outDir, "epic.log")); got != "1 Epic1\n" {
+		t.Errorf("on_epic_closedのフックが実行されていません: %q", got)
+	}
+}
+
+/**
+ * 監視で使用する増分syncでも、発生したイベントのフックが実行されること
+ */
+func TestIncrementalSyncRunsHooks(t *testing.T) {
+	skipHooksOnWindows(t)
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	index := setupIncrementalProject(t, cfg)
+
+	outDir := t.TempDir()
+	cfg.BaseDir = outDir
+	cfg.Hooks = map[string]config.HookCommands{
+		config.HookIssueClosed: {`echo "$IB_HOOK_EVENT $IB_HOOK_ISSUE_ID" >> events.log`},
+		config.HookEpicClosed:  {`echo "$IB_HOOK_EVENT $IB_HOOK_EPIC_ID" >> events.log`},
+		config.HookAfterSync:   {`echo "$IB_HOOK_EVENT" >> events.log`},
+	}
+
+	issuePath := filepath.Join(cfg.IssuesDir, "1_O_Issue1.md")
+	rewriteIssue(t, issuePath, "status: Open", "status: Close")
+	if err := commands.IncrementalSyncCommand(cfg, index, []string{issuePath}); err != nil {
+		t.Fatalf("増分syncの実行に失敗しました: %v", err)
+	}
+
+	expected := "on_issue_closed 1\non_epic_closed 1\nafter_sync\n"
+	if got := readFile(t, filepath.Join(outDir, "events.log")); got != expected {
+		t.Errorf("フックの実行内容が不正です:\n期待値:\n%s\n実際:\n%s", expected, got)
+	}
+}
+
+/**
+ * ファイル名の衝突でsyncがエラーになっても、反映済みの変更のフックが実行されること
+ *
+ * Issue1を完了にするとorder.csvとEpic1のステータスは更新されますが、
+ * リネーム先の1_C_Issue1.mdが既に存在するためファイル名の更新はエラーになります。
+ * 次回のsyncでは変更として検出されないため、エラーになったsyncでフックを実行します。
+ */
+func TestSyncRunsHooksDespiteRenameCollision(t *testing.T) {
+	skipHooksOnWindows(t)
+
+	for _, incremental := range []bool{false, true} {
+		t.Run(fmt.Sprintf("incremental=%v", incremental), func(t *testing.T) {
+			cfg, cleanup := setupTestEnvironment(t)
+			defer cleanup()
+			index := setupIncrementalProject(t, cfg)
+
+			outDir := t.TempDir()
+			cfg.BaseDir = outDir
+			cfg.Hooks = map[string]config.HookCommands{
+				config.HookIssueClosed: {`echo "$IB_HOOK_EVENT $IB_HOOK_ISSUE_ID" >> events.log`},
+				config.HookEpicClosed:  {`echo "$IB_HOOK_EVENT $IB_HOOK_EPIC_ID" >> events.log`},
+				config.HookAfterSync:   {`echo "$IB_HOOK_EVENT" >> events.log`},
+			}
+
+			if err := os.WriteFile(filepath.Join(cfg.IssuesDir, "1_C_Issue1.md"), []byte("リネーム先と衝突するファイル\n"), 0644); err != nil {
+				t.Fatalf("衝突するファイルの作成に失敗しました: %v", err)
+			}
+			issuePath := filepath.Join(cfg.IssuesDir, "1_O_Issue1.md")
+			rewriteIssue(t, issuePath, "status: Open", "status: Close")
+
+			var err error
+			if incremental {
+				err = commands.IncrementalSyncCommand(cfg, index, []string{issuePath})
+			} else {
+				err = commands.SyncCommand(cfg)
+			}
+			if err == nil {
+				t.Fatalf("ファイル名の衝突がエラーになりません")
+			}
+
+			expected := "on_issue_closed 1\non_epic_closed 1\nafter_sync\n"
+			if got := readFile(t, filepath.Join(outDir, "events.log")); got != expected {
+				t.Errorf("ファイル名の衝突でフックが実行されていません:\n期待値:\n%s\n実際:\n%s", expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,239 @@
+// test/incremental_sync_test.go
+package test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/moai/instant-backlog/internal/commands"
+	"github.com/moai/instant-backlog/internal/config"
+	"github.com/moai/instant-backlog/internal/watcher"
+)
+
+// Issueファイルの内容を置き換える
+func rewriteIssue(t *testing.T, path, old, new string) {
+	t.Helper()
+
+	content := readFile(t, path)
+	if !strings.Contains(content, old) {
+		t.Fatalf("置き換える内容がありません: %s", old)
+	}
+	if err := os.WriteFile(path, []byte(strings.Replace(content, old, new, 1)), 0644); err != nil {
+		t.Fatalf("ファイル書き込みに失敗しました: %v", err)
+	}
+}
+
+// 増分sync用のバックログを作成（Epic1にIssue1・2、Epic2にIssue3）し、インデックスを作成する
+func setupIncrementalProject(t *testing.T, cfg *config.Config) *commands.BacklogIndex {
+	t.Helper()
+
+	createTestEpic(t, cfg, 1, "Epic1", "Open")
+	createTestEpic(t, cfg, 2, "Epic2", "Open")
+	createTestIssue(t, cfg, 1, "Issue1", "Open", 1, 1)
+	createTestIssue(t, cfg, 2, "Issue2", "Close", 1, 1)
+	createTestIssue(t, cfg, 3, "Issue3", "Open", 2, 1)
+	if err := commands.SyncCommand(cfg); err != nil {
+		t.Fatalf("syncコマンドの実行に失敗しました: %v", err)
+	}
+
+	index, err := commands.NewBacklogIndex(cfg)
+	if err != nil {
+		t.Fatalf("インデックスの作成に失敗しました: %v", err)
+	}
+	return index
+}
+
+/**
+ * 増分syncで、変更されたファイルに関係するorder.csvの行・Epicのステータス・ファイル名が更新されること
+ *
+ * Issueを完了にするとorder.csvから除かれ、紐づくEpicが完了になってファイル名が変わります。
+ * 新しく追加したIssueはorder.csvに追加されます。
+ */
+func TestIncrementalSync(t *testing.T) {
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	index := setupIncrementalProject(t, cfg)
+
+	issuePath := filepath.Join(cfg.IssuesDir, "1_O_Issue1.md")
+	rewriteIssue(t, issuePath, "status: Open", "status: Close")
+	createTestIssue(t, cfg, 4, "Issue4", "Open", 2, 1)
+	newPath := filepath.Join(cfg.IssuesDir, "4_O_Issue4.md")
+
+	if err := commands.IncrementalSyncCommand(cfg, index, []string{issuePath, newPath}); err != nil {
+		t.Fatalf("増分syncの実行に失敗しました: %v", err)
+	}
+
+	if ids := readOrderIDs(t, cfg); fmt.Sprint(ids) != fmt.Sprint([]int{3, 4}) {
+		t.Errorf("order.csvが更新されていません: %v", ids)
+	}
+	for _, name := range []string{"1_C_Issue1.md", "4_O_Issue4.md"} {
+		if !fileExists(filepath.Join(cfg.IssuesDir, name)) {
+			t.Errorf("Issueのファイル名が更新されていません: %s", name)
+		}
+	}
+	if !fileExists(filepath.Join(cfg.EpicDir, "1_C_Epic1.md")) || !fileExists(filepath.Join(cfg.EpicDir, "2_O_Epic2.md")) {
+		t.Errorf("変更されたIssueに紐づくEpicだけが完了になっていません")
+	}
+
+	// sync自身による変更（リネーム後のファイル）もインデックスに反映されている
+	reopened := filepath.Join(cfg.IssuesDir, "1_C_Issue1.md")
+	rewriteIssue(t, reopened, "status: Close", "status: Open")
+	if err := commands.IncrementalSyncCommand(cfg, index, []string{reopened}); err != nil {
+		t.Fatalf("増分syncの実行に失敗しました: %v", err)
+	}
+	if ids := readOrderIDs(t, cfg); fmt.Sprint(ids) != fmt.Sprint([]int{3, 4, 1}) {
+		t.Errorf("再開したIssueがorder.csvに追加されていません: %v", ids)
+	}
+	if !fileExists(filepath.Join(cfg.IssuesDir, "1_O_Issue1.md")) || fileExists(reopened) {
+		t.Errorf("再開したIssueのファイル名が更新されていません")
+	}
+}
+
+/**
+ * 増分syncでは変更されたファイルだけを読み直し、それ以外のファイルはインデックスの内容を使うこと
+ */
+func TestIncrementalSyncReadsOnlyChangedFiles(t *testing.T) {
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	index := setupIncrementalProject(t, cfg)
+
+	// 変更として渡さないファイルの変更は反映されない
+	rewriteIssue(t, filepath.Join(cfg.IssuesDir, "3_O_Issue3.md"), "title: Issue3", "title: 渡していない変更")
+	changed := filepath.Join(cfg.IssuesDir, "1_O_Issue1.md")
+	rewriteIssue(t, changed, "title: Issue1", "title: 変更したIssue")
+
+	if err := commands.IncrementalSyncCommand(cfg, index, []string{changed}); err != nil {
+		t.Fatalf("増分syncの実行に失敗しました: %v", err)
+	}
+
+	content := readFile(t, cfg.OrderCSV)
+	if !strings.Contains(content, "1,変更したIssue,") {
+		t.Errorf("変更したファイルの内容がorder.csvに反映されていません:\n%s", content)
+	}
+	if !strings.Contains(content, "3,Issue3,") {
+		t.Errorf("変更として渡していないファイルが読み直されています:\n%s", content)
+	}
+	if !fileExists(filepath.Join(cfg.IssuesDir, "3_O_Issue3.md")) {
+		t.Errorf("変更として渡していないファイルがリネームされています")
+	}
+}
+
+/**
+ * 監視ではデバウンス中に変更されたファイルのパスが増分syncに渡されること
+ */
+func TestWatcherPassesChangedFiles(t *testing.T) {
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	setupIncrementalProject(t, cfg)
+
+	executor := &CountingCommandExecutor{}
+	watcher.SetCommandExecutor(executor)
+	defer watcher.SetCommandExecutor(&MockCommandExecutor{})
+
+	absPath, err := filepath.Abs(cfg.ProjectsDir)
+	if err != nil {
+		t.Fatalf("絶対パスの取得に失敗しました: %v", err)
+	}
+	manager := watcher.GetManager()
+	if err := manager.StartWatching(absPath, 100*time.Millisecond); err != nil {
+		t.Fatalf("監視の開始に失敗しました: %v", err)
+	}
+	defer manager.StopWatching(absPath)
+	time.Sleep(200 * time.Millisecond) // 初期化のための待機
+
+	path1 := filepath.Join(cfg.IssuesDir, "1_O_Issue1.md")
+	path3 := filepath.Join(cfg.IssuesDir, "3_O_Issue3.md")
+	rewriteIssue(t, path1, "estimate: 1", "estimate: 5")
+	rewriteIssue(t, path3, "estimate: 1", "estimate: 8")
+	time.Sleep(500 * time.Millisecond)
+
+	executor.mu.Lock()
+	defer executor.mu.Unlock()
+	expected := fmt.Sprint([][]string{{path1, path3}})
+	if got := fmt.Sprint(executor.changed); got != expected {
+		t.Errorf("増分syncに渡された変更されたファイルが不正です: 期待値=%s, 実際=%s", expected, got)
+	}
+	if content := readFile(t, cfg.OrderCSV); !strings.Contains(content, "1,Issue1,1,5") || !strings.Contains(content, "3,Issue3,2,8") {
+		t.Errorf("order.csvが更新されていません:\n%s", content)
+	}
+}
+
+/**
+ * 監視中にIssueファイルを削除すると、インデックスから除かれてorder.csvの行も削除されること
+ *
+ * 削除と同時に別のIssueを編集した場合も、削除したIssueの行が残らないことを確認します。
+ */
+func TestWatcherHandlesDeletedIssue(t *testing.T) {
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	setupIncrementalProject(t, cfg)
+	createTestIssue(t, cfg, 4, "Issue4", "Open", 2, 1)
+	if err := commands.SyncCommand(cfg); err != nil {
+		t.Fatalf("syncコマンドの実行に失敗しました: %v", err)
+	}
+
+	executor := &CountingCommandExecutor{}
+	watcher.SetCommandExecutor(executor)
+	defer watcher.SetCommandExecutor(&MockCommandExecutor{})
+
+	absPath, err := filepath.Abs(cfg.ProjectsDir)
+	if err != nil {
+		t.Fatalf("絶対パスの取得に失敗しました: %v", err)
+	}
+	manager := watcher.GetManager()
+	if err := manager.StartWatching(absPath, 100*time.Millisecond); err != nil {
+		t.Fatalf("監視の開始に失敗しました: %v", err)
+	}
+	defer manager.StopWatching(absPath)
+	time.Sleep(200 * time.Millisecond) // 初期化のための待機
+
+	// 最初の変更でインデックスを作成させる
+	rewriteIssue(t, filepath.Join(cfg.IssuesDir, "3_O_Issue3.md"), "estimate: 1", "estimate: 2")
+	time.Sleep(500 * time.Millisecond)
+
+	deleted := filepath.Join(cfg.IssuesDir, "4_O_Issue4.md")
+	if err := os.Remove(deleted); err != nil {
+		t.Fatalf("Issueファイルの削除に失敗しました: %v", err)
+	}
+	rewriteIssue(t, filepath.Join(cfg.IssuesDir, "1_O_Issue1.md"), "estimate: 1", "estimate: 5")
+	time.Sleep(500 * time.Millisecond)
+
+	if ids := readOrderIDs(t, cfg); fmt.Sprint(ids) != fmt.Sprint([]int{1, 3}) {
+		t.Errorf("削除したIssueの行がorder.csvに残っています: %v", ids)
+	}
+	executor.mu.Lock()
+	defer executor.mu.Unlock()
+	if last := executor.changed[len(executor.changed)-1]; !strings.Contains(fmt.Sprint(last), deleted) {
+		t.Errorf("削除したファイルが増分syncに渡されていません: %v", last)
+	}
+}
+
+/**
+ * 増分syncに削除されたファイルを渡すと、インデックスから除かれること
+ */
+func TestIncrementalSyncEvictsDeletedFiles(t *testing.T) {
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	index := setupIncrementalProject(t, cfg)
+
+	deleted := filepath.Join(cfg.IssuesDir, "3_O_Issue3.md")
+	if err := os.Remove(deleted); err != nil {
+		t.Fatalf("Issueファイルの削除に失敗しました: %v", err)
+	}
+	if err := commands.IncrementalSyncCommand(cfg, index, []string{deleted}); err != nil {
+		t.Fatalf("増分syncの実行に失敗しました: %v", err)
+	}
+
+	if ids := readOrderIDs(t, cfg); fmt.Sprint(ids) != fmt.Sprint([]int{1}) {
+		t.Errorf("削除したIssueの行がorder.csvに残っています: %v", ids)
+	}
+	for _, issue := range index.Issues(cfg.StatusSet()) {
+		if issue.ID == 3 {
+			t.Errorf("削除したIssueがインデックスに残っています")
+		}
+	}
+}
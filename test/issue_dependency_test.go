@@ -0,0 +1,202 @@
+// test/issue_dependency_test.go
+package test
+
+import (
+	"bytes"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/moai/instant-backlog/internal/commands"
+	"github.com/moai/instant-backlog/internal/config"
+	"github.com/moai/instant-backlog/internal/fileops"
+	"github.com/moai/instant-backlog/internal/logger"
+	"github.com/moai/instant-backlog/internal/models"
+	"github.com/moai/instant-backlog/internal/parser"
+	"github.com/moai/instant-backlog/pkg/utils"
+)
+
+// 依存関係付きのIssueを作成
+func createDependentIssue(t *testing.T, cfg *config.Config, id int, title, status string, blockedBy ...int) {
+	t.Helper()
+
+	issue := &models.Issue{
+		ID:        id,
+		Title:     title,
+		Status:    status,
+		Epic:      1,
+		Estimate:  1,
+		BlockedBy: blockedBy,
+		Content:   "依存関係テスト",
+	}
+	if err := fileops.WriteIssue(cfg.IssuesDir, issue); err != nil {
+		t.Fatalf("テスト用Issueの作成に失敗しました: %v", err)
+	}
+}
+
+// order.csvのID列を取得
+func readOrderIDs(t *testing.T, cfg *config.Config) []int {
+	t.Helper()
+
+	items, err := parser.ReadOrderCSV(cfg.OrderCSV)
+	if err != nil {
+		t.Fatalf("order.csvの読み込みに失敗しました: %v", err)
+	}
+	ids := make([]int, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+	return ids
+}
+
+/**
+ * Issueの依存関係をFront Matterで管理できること
+ *
+ * blocked_by フィールドに先に完了すべきIssueのIDを記述でき、
+ * 読み込み時に循環参照や存在しない依存先が検出されることを確認します。
+ */
+func TestIssueDependenciesDetection(t *testing.T) {
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	createDependentIssue(t, cfg, 1, "循環A", "Open", 2)
+	createDependentIssue(t, cfg, 2, "循環B", "Open", 3)
+	createDependentIssue(t, cfg, 3, "循環C", "Open", 1)
+	createDependentIssue(t, cfg, 4, "存在しない依存先", "Open", 99)
+
+	// blocked_byが読み込めることを確認
+	issue, err := parser.ParseIssueFile(filepath.Join(cfg.IssuesDir, "4_O_存在しない依存先.md"))
+	if err != nil {
+		t.Fatalf("Issueファイルの読み込みに失敗しました: %v", err)
+	}
+	if !reflect.DeepEqual(issue.BlockedBy, []int{99}) {
+		t.Errorf("blocked_byが正しく読み込まれていません: %v", issue.BlockedBy)
+	}
+
+	issues, err := fileops.ReadAllIssues(cfg.IssuesDir)
+	if err != nil {
+		t.Fatalf("Issueの読み込みに失敗しました: %v", err)
+	}
+
+	// 循環参照の検出
+	cycles := utils.FindDependencyCycles(issues)
+	if len(cycles) != 1 || len(cycles[0]) != 3 {
+		t.Fatalf("循環参照が正しく検出されていません: %v", cycles)
+	}
+
+	// 存在しない依存先の検出
+	dangling := utils.FindDanglingDependencies(issues)
+	if len(dangling) != 1 || dangling[0].IssueID != 4 || dangling[0].MissingID != 99 {
+		t.Errorf("存在しない依存先が正しく検出されていません: %v", dangling)
+	}
+
+	// 読み込みと同時に問題を受け取れる
+	_, problems, err := fileops.ReadAllIssuesWithProblems(cfg.IssuesDir, models.DefaultStatusSet())
+	if err != nil {
+		t.Fatalf("Issueの読み込みに失敗しました: %v", err)
+	}
+	if len(problems) != 2 || problems[0].MissingID != 99 || len(problems[1].Cycle) != 3 {
+		t.Errorf("読み込んだIssueの依存関係の問題が返されていません: %+v", problems)
+	}
+}
+
+/**
+ * 依存関係の問題はIssueの読み込み自体では警告せず、sync・list・moveの実行ごとに1回だけ警告すること
+ */
+func TestSyncWarnsDependencyProblemsOnce(t *testing.T) {
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	createTestEpic(t, cfg, 1, "依存関係", "Open")
+	createDependentIssue(t, cfg, 1, "循環A", "Open", 2)
+	createDependentIssue(t, cfg, 2, "循環B", "Open", 1)
+	createDependentIssue(t, cfg, 3, "存在しない依存先", "Open", 99)
+
+	logs := captureLogs(t, logger.Options{})
+	if _, err := fileops.ReadAllIssues(cfg.IssuesDir); err != nil {
+		t.Fatalf("Issueの読み込みに失敗しました: %v", err)
+	}
+	if logs.Len() != 0 {
+		t.Errorf("Issueの読み込みで警告が出力されています:\n%s", logs.String())
+	}
+
+	if err := commands.SyncCommand(cfg); err != nil {
+		t.Fatalf("syncコマンドの実行に失敗しました: %v", err)
+	}
+	output := logs.String()
+	if n := strings.Count(output, "blocked_byに存在しないIssueが指定されています"); n != 1 {
+		t.Errorf("存在しない依存先の警告が%d回出力されています:\n%s", n, output)
+	}
+	if n := strings.Count(output, "Issueの依存関係が循環しています"); n != 1 {
+		t.Errorf("循環参照の警告が%d回出力されています:\n%s", n, output)
+	}
+
+	// syncを実行しなくても、Issueを読み込むコマンドで問題に気付ける
+	logs.Reset()
+	var out bytes.Buffer
+	if err := commands.ListCommand(cfg, commands.ListOptions{MinEstimate: -1, MaxEstimate: -1}, &out); err != nil {
+		t.Fatalf("listコマンドの実行に失敗しました: %v", err)
+	}
+	if err := commands.MoveCommand(cfg, []int{3}, commands.MoveOptions{Top: true}); err != nil {
+		t.Fatalf("moveコマンドの実行に失敗しました: %v", err)
+	}
+	if n := strings.Count(logs.String(), "blocked_byに存在しないIssueが指定されています"); n != 2 {
+		t.Errorf("listとmoveで依存関係の問題が警告されていません:\n%s", logs.String())
+	}
+}
+
+/**
+ * order.csvで依存先より上位にあるIssueはsyncで警告のみ行い、順序を維持すること
+ */
+func TestSyncKeepsOrderWithDependencyViolation(t *testing.T) {
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	createDependentIssue(t, cfg, 1, "前提タスク", "Open")
+	createDependentIssue(t, cfg, 2, "後続タスク", "Open", 1)
+	createTestOrderCSV(t, cfg, []models.OrderCSVItem{
+		{ID: 2, Title: "後続タスク", Epic: 1, Estimate: 1},
+		{ID: 1, Title: "前提タスク", Epic: 1, Estimate: 1},
+	})
+
+	if err := commands.SyncCommand(cfg); err != nil {
+		t.Fatalf("syncコマンドの実行に失敗しました: %v", err)
+	}
+
+	if ids := readOrderIDs(t, cfg); !reflect.DeepEqual(ids, []int{2, 1}) {
+		t.Errorf("警告のみのモードで順序が変更されています: %v", ids)
+	}
+}
+
+/**
+ * 並び替えオプションを指定すると、依存先が依存元より上位に移動すること
+ *
+ * 完了済みの依存先は並び順の制約にならないことも確認します。
+ */
+func TestSyncSortsByDependencies(t *testing.T) {
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	createDependentIssue(t, cfg, 1, "独立タスク", "Open")
+	createDependentIssue(t, cfg, 2, "後続タスク", "Open", 3, 5)
+	createDependentIssue(t, cfg, 3, "前提タスク", "Open")
+	createDependentIssue(t, cfg, 4, "別タスク", "Open")
+	createDependentIssue(t, cfg, 5, "完了済みの前提", "Close")
+	createTestOrderCSV(t, cfg, []models.OrderCSVItem{
+		{ID: 1, Title: "独立タスク", Epic: 1, Estimate: 1},
+		{ID: 2, Title: "後続タスク", Epic: 1, Estimate: 1},
+		{ID: 4, Title: "別タスク", Epic: 1, Estimate: 1},
+		{ID: 3, Title: "前提タスク", Epic: 1, Estimate: 1},
+	})
+
+	err := commands.SyncCommandWithOptions(cfg, commands.SyncOptions{SortByDependencies: true})
+	if err != nil {
+		t.Fatalf("syncコマンドの実行に失敗しました: %v", err)
+	}
+
+	expected := []int{1, 3, 2, 4}
+	if ids := readOrderIDs(t, cfg); !reflect.DeepEqual(ids, expected) {
+		t.Errorf("依存関係に基づく並び替えが不正です。期待値: %v, 実際: %v", expected, ids)
+	}
+}
@@ -0,0 +1,220 @@
+// test/list_command_test.go
+package test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/moai/instant-backlog/internal/commands"
+	"github.com/moai/instant-backlog/internal/config"
+	"github.com/moai/instant-backlog/internal/logger"
+	"github.com/moai/instant-backlog/internal/models"
+)
+
+// 一覧表示用のテストデータを作成
+func setupListData(t *testing.T, cfg *config.Config) {
+	t.Helper()
+
+	createTestEpic(t, cfg, 1, "認証", "Open")
+	createTestEpic(t, cfg, 2, "決済", "Open")
+	createTestIssue(t, cfg, 1, "ログイン画面", "Open", 1, 3)
+	createTestIssue(t, cfg, 2, "ログアウト", "Open", 1, 1)
+	createTestIssue(t, cfg, 3, "カード決済", "Open", 2, 8)
+	createTestIssue(t, cfg, 4, "パスワード再設定", "Close", 1, 5)
+	createTestOrderCSV(t, cfg, []models.OrderCSVItem{
+		{ID: 3, Title: "カード決済", Epic: 2, Estimate: 8},
+		{ID: 1, Title: "ログイン画面", Epic: 1, Estimate: 3},
+		{ID: 2, Title: "ログアウト", Epic: 1, Estimate: 1},
+	})
+}
+
+// listコマンドをJSON形式で実行して結果を返す
+func listIssuesJSON(t *testing.T, cfg *config.Config, opts commands.ListOptions) []map[string]interface{} {
+	t.Helper()
+
+	opts.Format = commands.ListFormatJSON
+	var out bytes.Buffer
+	if err := commands.ListCommand(cfg, opts, &out); err != nil {
+		t.Fatalf("listコマンドの実行に失敗しました: %v", err)
+	}
+
+	var result []map[string]interface{}
+	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+		t.Fatalf("JSON出力の解析に失敗しました: %v\n%s", err, out.String())
+	}
+	return result
+}
+
+// 結果のID列を取得
+func resultIDs(result []map[string]interface{}) []int {
+	ids := make([]int, 0, len(result))
+	for _, row := range result {
+		ids = append(ids, int(row["id"].(float64)))
+	}
+	return ids
+}
+
+/**
+ * ファイルを開かずにバックログを条件で検索できること
+ *
+ * ステータス、Epic、見積もり範囲、タイトルの部分一致で絞り込み、
+ * order.csvの順位・ID・見積もりで並べ替えられることを確認します。
+ */
+func TestListIssuesWithFilters(t *testing.T) {
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	setupListData(t, cfg)
+
+	noRange := commands.ListOptions{MinEstimate: -1, MaxEstimate: -1}
+
+	tests := []struct {
+		name     string
+		modify   func(*commands.ListOptions)
+		expected []int
+	}{
+		{"order.csvの順位順（含まれないものは末尾）", func(o *commands.ListOptions) {}, []int{3, 1, 2, 4}},
+		{"ID順", func(o *commands.ListOptions) { o.Sort = commands.ListSortID }, []int{1, 2, 3, 4}},
+		{"見積もり順", func(o *commands.ListOptions) { o.Sort = commands.ListSortEstimate }, []int{2, 1, 4, 3}},
+		{"未完了のみ", func(o *commands.ListOptions) { o.Open = true }, []int{3, 1, 2}},
+		{"ステータス指定", func(o *commands.ListOptions) { o.Statuses = []string{"close"} }, []int{4}},
+		{"Epic指定", func(o *commands.ListOptions) { o.Epic = 1 }, []int{1, 2, 4}},
+		{"見積もり範囲", func(o *commands.ListOptions) { o.MinEstimate = 2; o.MaxEstimate = 5 }, []int{1, 4}},
+		{"タイトル部分一致", func(o *commands.ListOptions) { o.Title = "ログ" }, []int{1, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := noRange
+			tt.modify(&opts)
+			if ids := resultIDs(listIssuesJSON(t, cfg, opts)); !reflect.DeepEqual(ids, tt.expected) {
+				t.Errorf("一覧の結果が不正です。期待値: %v, 実際: %v", tt.expected, ids)
+			}
+		})
+	}
+
+	// JSONには順位が含まれること
+	result := listIssuesJSON(t, cfg, noRange)
+	if result[0]["rank"].(float64) != 1 {
+		t.Errorf("JSON出力に順位が含まれていません: %v", result[0])
+	}
+}
+
+/**
+ * CSV形式と表形式で出力できること
+ */
+func TestListOutputFormats(t *testing.T) {
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	setupListData(t, cfg)
+
+	// CSV形式
+	var csvOut bytes.Buffer
+	opts := commands.ListOptions{MinEstimate: -1, MaxEstimate: -1, Open: true, Format: commands.ListFormatCSV}
+	if err := commands.ListCommand(cfg, opts, &csvOut); err != nil {
+		t.Fatalf("listコマンドの実行に失敗しました: %v", err)
+	}
+	records, err := csv.NewReader(&csvOut).ReadAll()
+	if err != nil {
+		t.Fatalf("CSV出力の解析に失敗しました: %v", err)
+	}
+	if len(records) != 4 || records[0][1] != "id" || records[1][1] != "3" || records[1][2] != "カード決済" {
+		t.Errorf("CSV出力が不正です: %v", records)
+	}
+
+	// 表形式（全角文字を含んでも列が揃うこと）
+	var tableOut bytes.Buffer
+	opts.Format = commands.ListFormatTable
+	if err := commands.ListCommand(cfg, opts, &tableOut); err != nil {
+		t.Fatalf("listコマンドの実行に失敗しました: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(tableOut.String()), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[0], "RANK") {
+		t.Fatalf("表形式の出力が不正です:\n%s", tableOut.String())
+	}
+	// STATUS列の開始位置（表示幅）が全行で一致すること
+	statusCol := strings.Index(lines[0], "STATUS")
+	for _, line := range lines[1:] {
+		open := strings.Index(line, "Open")
+		if open < 0 {
+			t.Fatalf("表形式の行にステータスがありません: %s", line)
+		}
+		if width := displayWidthOf(line[:open]); width != statusCol {
+			t.Errorf("表の列が揃っていません: %q（期待値: %d, 実際: %d）", line, statusCol, width)
+		}
+	}
+
+	// 不正な出力形式はエラー
+	opts.Format = "xml"
+	if err := commands.ListCommand(cfg, opts, &bytes.Buffer{}); err == nil {
+		t.Errorf("不正な出力形式がエラーになりません")
+	}
+}
+
+/**
+ * Epicを紐づくIssueの集計付きで一覧表示できること
+ */
+func TestListEpics(t *testing.T) {
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	setupListData(t, cfg)
+
+	result := listIssuesJSON(t, cfg, commands.ListOptions{Epics: true, MinEstimate: -1, MaxEstimate: -1})
+	if len(result) != 2 {
+		t.Fatalf("Epicの件数が不正です: %v", result)
+	}
+	epic1 := result[0]
+	if epic1["issues"].(float64) != 3 || epic1["open_issues"].(float64) != 2 || epic1["estimate"].(float64) != 4 {
+		t.Errorf("Epicの集計が不正です: %v", epic1)
+	}
+
+	// 見積もりの条件は未完了のIssueの見積もり合計に適用する
+	result = listIssuesJSON(t, cfg, commands.ListOptions{Epics: true, MinEstimate: 5, MaxEstimate: -1})
+	if ids := resultIDs(result); !reflect.DeepEqual(ids, []int{2}) {
+		t.Errorf("見積もりの下限で絞り込まれていません: %v", ids)
+	}
+	result = listIssuesJSON(t, cfg, commands.ListOptions{Epics: true, MinEstimate: -1, MaxEstimate: 4})
+	if ids := resultIDs(result); !reflect.DeepEqual(ids, []int{1}) {
+		t.Errorf("見積もりの上限で絞り込まれていません: %v", ids)
+	}
+}
+
+/**
+ * order.csvに不正な行があっても、警告して残りの行の順位で一覧表示できること
+ */
+func TestListWithInvalidOrderRows(t *testing.T) {
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	setupListData(t, cfg)
+
+	content := "id,title,epic,estimate\n3,カード決済,2,8\nabc,不正な行,1,1\n1,ログイン画面,1,3\n"
+	if err := os.WriteFile(cfg.OrderCSV, []byte(content), 0644); err != nil {
+		t.Fatalf("order.csvの書き込みに失敗しました: %v", err)
+	}
+
+	logs := captureLogs(t, logger.Options{})
+	result := listIssuesJSON(t, cfg, commands.ListOptions{Open: true, MinEstimate: -1, MaxEstimate: -1})
+	if ids := resultIDs(result); !reflect.DeepEqual(ids, []int{3, 1, 2}) {
+		t.Errorf("不正な行以外の順位で並んでいません: %v", ids)
+	}
+	if !strings.Contains(logs.String(), "order.csvに不正な行があります") {
+		t.Errorf("不正な行が警告されていません:\n%s", logs.String())
+	}
+}
+
+// 全角文字を2として表示幅を数える
+func displayWidthOf(s string) int {
+	width := 0
+	for _, r := range s {
+		if r >= 0x2E80 {
+			width += 2
+		} else {
+			width++
+		}
+	}
+	return width
+}
@@ -0,0 +1,140 @@
+// test/logging_test.go
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/moai/instant-backlog/internal/commands"
+	"github.com/moai/instant-backlog/internal/config"
+	"github.com/moai/instant-backlog/internal/logger"
+	"github.com/moai/instant-backlog/internal/models"
+)
+
+// ログの出力先をバッファに切り替え、テスト終了時に元に戻す
+func captureLogs(t *testing.T, opts logger.Options) *bytes.Buffer {
+	t.Helper()
+
+	var buf bytes.Buffer
+	opts.Writer = &buf
+	if err := logger.Setup(opts); err != nil {
+		t.Fatalf("ロガーの設定に失敗しました: %v", err)
+	}
+	t.Cleanup(func() { logger.Setup(logger.Options{}) })
+	return &buf
+}
+
+// ログ確認用のプロジェクトを作成
+func setupLoggingProject(t *testing.T, cfg *config.Config) {
+	t.Helper()
+
+	createTestEpic(t, cfg, 1, "ログ確認", "Open")
+	createTestOrderCSV(t, cfg, []models.OrderCSVItem{})
+	createTestIssue(t, cfg, 1, "通常のIssue", "Open", 1, 1)
+	createDependentIssue(t, cfg, 2, "存在しない依存先", "Open", 99)
+}
+
+/**
+ * 通常の実行では簡潔な結果と警告のみが出力されること
+ *
+ * ファイルごとのデバッグ出力（ファイル内容の先頭部分など）は出力されず、
+ * 警告が埋もれないことを確認します。
+ */
+func TestLoggingDefaultIsConcise(t *testing.T) {
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	setupLoggingProject(t, cfg)
+
+	logs := captureLogs(t, logger.Options{})
+	if err := commands.SyncCommand(cfg); err != nil {
+		t.Fatalf("syncコマンドの実行に失敗しました: %v", err)
+	}
+
+	output := logs.String()
+	if strings.Contains(output, "[debug]") || strings.Contains(output, "Issueファイルを解析しました") {
+		t.Errorf("通常の実行でデバッグログが出力されています:\n%s", output)
+	}
+	if !strings.Contains(output, "order.csvを同期しました issues=2") {
+		t.Errorf("同期結果の概要が出力されていません:\n%s", output)
+	}
+	if !strings.Contains(output, "警告: blocked_byに存在しないIssueが指定されています id=2 blocked_by=99") {
+		t.Errorf("警告が出力されていません:\n%s", output)
+	}
+}
+
+/**
+ * --verbose で詳細なログ、--quiet で警告のみが出力されること
+ */
+func TestLoggingLevels(t *testing.T) {
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	setupLoggingProject(t, cfg)
+
+	// --verbose
+	logs := captureLogs(t, logger.Options{Verbose: true})
+	if err := commands.SyncCommand(cfg); err != nil {
+		t.Fatalf("syncコマンドの実行に失敗しました: %v", err)
+	}
+	if !strings.Contains(logs.String(), "[debug] Issueファイルを解析しました") {
+		t.Errorf("--verboseでデバッグログが出力されていません:\n%s", logs.String())
+	}
+
+	// --quiet
+	logs = captureLogs(t, logger.Options{Quiet: true})
+	if err := commands.SyncCommand(cfg); err != nil {
+		t.Fatalf("syncコマンドの実行に失敗しました: %v", err)
+	}
+	output := logs.String()
+	if strings.Contains(output, "order.csvを同期しました") {
+		t.Errorf("--quietで情報ログが出力されています:\n%s", output)
+	}
+	if !strings.Contains(output, "警告:") {
+		t.Errorf("--quietで警告が出力されていません:\n%s", output)
+	}
+
+	// 同時指定と不明な形式はエラー
+	if err := logger.Setup(logger.Options{Verbose: true, Quiet: true}); err == nil {
+		t.Errorf("--verboseと--quietの同時指定がエラーになりません")
+	}
+	if err := logger.Setup(logger.Options{Format: "xml"}); err == nil {
+		t.Errorf("不明なログ形式がエラーになりません")
+	}
+}
+
+/**
+ * --log-format=json で1行1件のJSONとして出力されること
+ */
+func TestLoggingJSONFormat(t *testing.T) {
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	setupLoggingProject(t, cfg)
+
+	logs := captureLogs(t, logger.Options{Format: logger.FormatJSON})
+	if err := commands.SyncCommand(cfg); err != nil {
+		t.Fatalf("syncコマンドの実行に失敗しました: %v", err)
+	}
+
+	var synced, warned bool
+	for _, line := range strings.Split(strings.TrimSpace(logs.String()), "\n") {
+		var entry map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("JSONとして解析できないログがあります: %v\n%s", err, line)
+		}
+		switch entry["msg"] {
+		case "order.csvを同期しました":
+			synced = entry["level"] == "INFO" && entry["issues"] == float64(2)
+		case "blocked_byに存在しないIssueが指定されています":
+			warned = entry["level"] == "WARN" && entry["blocked_by"] == float64(99)
+		}
+	}
+	if !synced || !warned {
+		t.Errorf("JSONログに必要な項目が含まれていません:\n%s", logs.String())
+	}
+
+	// 子プロセスに引き継ぐフラグ
+	if flags := strings.Join(logger.Flags(), " "); flags != "--log-format json" {
+		t.Errorf("引き継ぐログ設定のフラグが不正です: %s", flags)
+	}
+}
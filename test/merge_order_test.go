@@ -0,0 +1,187 @@
+// test/merge_order_test.go
+package test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/moai/instant-backlog/internal/commands"
+	"github.com/moai/instant-backlog/internal/models"
+)
+
+// IDの一覧からorder.csvの行を作成
+func orderItems(ids ...int) []models.OrderCSVItem {
+	items := make([]models.OrderCSVItem, 0, len(ids))
+	for _, id := range ids {
+		items = append(items, models.OrderCSVItem{ID: id, Title: fmt.Sprintf("Issue%d", id), Epic: 1, Estimate: 1})
+	}
+	return items
+}
+
+// 行のIDの一覧を返す
+func itemIDs(items []models.OrderCSVItem) []int {
+	ids := make([]int, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+	return ids
+}
+
+/**
+ * 両方のブランチでの追加・削除・移動がIssue IDごとにマージされること
+ *
+ * 自分: 3を削除、5を先頭に移動、6を2の後に追加
+ * 相手: 1を4の後に移動、7を末尾に追加
+ * それぞれの変更がすべて反映され、競合にならないことを確認します。
+ */
+func TestMergeOrderCombinesChanges(t *testing.T) {
+	base := orderItems(1, 2, 3, 4, 5)
+	ours := orderItems(5, 1, 2, 6, 4)
+	theirs := orderItems(2, 3, 4, 1, 5, 7)
+
+	result := commands.MergeOrder(base, ours, theirs)
+
+	expected := []int{5, 2, 6, 4, 1, 7}
+	if ids := itemIDs(result.Items); fmt.Sprint(ids) != fmt.Sprint(expected) {
+		t.Errorf("マージ結果の並び順が不正です: 期待値=%v, 実際=%v", expected, ids)
+	}
+	if len(result.Conflicts) != 0 || len(result.Moves) != 0 {
+		t.Errorf("競合しない変更が競合として報告されています: %+v", result)
+	}
+}
+
+/**
+ * 両方で異なる位置に移動された行は自分の位置を採用し、移動として報告すること
+ */
+func TestMergeOrderConflictingMoves(t *testing.T) {
+	result := commands.MergeOrder(orderItems(1, 2, 3, 4), orderItems(4, 1, 2, 3), orderItems(1, 4, 2, 3))
+
+	if ids := itemIDs(result.Items); fmt.Sprint(ids) != fmt.Sprint([]int{4, 1, 2, 3}) {
+		t.Errorf("自分の位置が採用されていません: %v", ids)
+	}
+	if len(result.Moves) != 1 || result.Moves[0].ID != 4 {
+		t.Errorf("競合した移動が報告されていません: %+v", result.Moves)
+	}
+	if len(result.Conflicts) != 0 {
+		t.Errorf("移動の競合が内容の競合として報告されています: %+v", result.Conflicts)
+	}
+
+	// 両方で同じ位置に移動した場合は報告しない
+	result = commands.MergeOrder(orderItems(1, 2, 3, 4), orderItems(4, 1, 2, 3), orderItems(4, 1, 2, 3))
+	if len(result.Moves) != 0 {
+		t.Errorf("同じ移動が競合として報告されています: %+v", result.Moves)
+	}
+}
+
+/**
+ * 行の内容は列ごとにマージされ、両方で同じ列が異なる値に変更された場合のみ競合になること
+ */
+func TestMergeOrderRowConflicts(t *testing.T) {
+	base := orderItems(1, 2)
+	ours := orderItems(1, 2)
+	theirs := orderItems(1, 2)
+	ours[0].Title = "自分のタイトル"
+	theirs[0].Epic = 2
+	ours[1].Estimate = 5
+	theirs[1].Estimate = 8
+
+	result := commands.MergeOrder(base, ours, theirs)
+
+	if item := result.Items[0]; item.Title != "自分のタイトル" || item.Epic != 2 {
+		t.Errorf("異なる列の変更がマージされていません: %+v", item)
+	}
+	if item := result.Items[1]; item.Estimate != 5 {
+		t.Errorf("競合した列で自分の値が採用されていません: %+v", item)
+	}
+	if len(result.Conflicts) != 1 || result.Conflicts[0].ID != 2 || !strings.Contains(result.Conflicts[0].Message, "estimate: 自分=5, 相手=8") {
+		t.Errorf("競合が正しく報告されていません: %+v", result.Conflicts)
+	}
+}
+
+/**
+ * merge-orderコマンドでマージ結果を自分のファイルに書き込み、
+ * 完了済みのIssueの行を除き、追加の列を保持すること
+ */
+func TestMergeOrderCommand(t *testing.T) {
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	createTestEpic(t, cfg, 1, "Epic1", "Open")
+	createTestIssue(t, cfg, 1, "Issue1", "Open", 1, 1)
+	createTestIssue(t, cfg, 2, "Issue2", "Close", 1, 1)
+	createTestIssue(t, cfg, 3, "Issue3", "Open", 1, 1)
+	createTestIssue(t, cfg, 4, "Issue4", "Open", 1, 1)
+
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("テスト用CSVの作成に失敗しました: %v", err)
+		}
+		return path
+	}
+	base := write("base.csv", "id,title,epic,estimate,note\n1,Issue1,1,1,\n2,Issue2,1,1,\n")
+	ours := write("ours.csv", "id,title,epic,estimate,note\n1,Issue1,1,1,メモ\n2,Issue2,1,1,\n3,Issue3,1,1,\n")
+	theirs := write("theirs.csv", "id,title,epic,estimate,note\n4,Issue4,1,1,\n1,Issue1,1,1,\n2,Issue2,1,1,\n")
+
+	var out bytes.Buffer
+	if err := commands.MergeOrderCommand(cfg, base, ours, theirs, &out); err != nil {
+		t.Fatalf("merge-orderコマンドの実行に失敗しました: %v\n%s", err, out.String())
+	}
+
+	expected := "id,title,epic,estimate,note\n4,Issue4,1,1,\n1,Issue1,1,1,メモ\n3,Issue3,1,1,\n"
+	if got := readFile(t, ours); got != expected {
+		t.Errorf("マージ結果が不正です:\n期待値:\n%s\n実際:\n%s", expected, got)
+	}
+
+	// 内容が競合した場合はエラーになる
+	ours = write("ours.csv", "id,title,epic,estimate\n1,Issue1,1,3\n")
+	theirs = write("theirs.csv", "id,title,epic,estimate\n1,Issue1,1,5\n")
+	out.Reset()
+	if err := commands.MergeOrderCommand(cfg, base, ours, theirs, &out); err == nil {
+		t.Errorf("競合があるのにエラーになりません")
+	}
+	if !strings.Contains(out.String(), "競合: ID=1: estimate: 自分=3, 相手=5") {
+		t.Errorf("競合が出力されていません:\n%s", out.String())
+	}
+}
+
+/**
+ * 両方のブランチでorder.csvを作成した場合（add/addの競合）に、
+ * gitが渡す空の共通祖先ファイルでもマージできること
+ */
+func TestMergeOrderCommandEmptyBase(t *testing.T) {
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	createTestEpic(t, cfg, 1, "Epic1", "Open")
+	createTestIssue(t, cfg, 1, "Issue1", "Open", 1, 1)
+	createTestIssue(t, cfg, 2, "Issue2", "Open", 1, 1)
+
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("テスト用CSVの作成に失敗しました: %v", err)
+		}
+		return path
+	}
+	base := write("base.csv", "")
+	ours := write("ours.csv", "id,title,epic,estimate\n1,Issue1,1,1\n")
+	theirs := write("theirs.csv", "id,title,epic,estimate\n2,Issue2,1,1\n")
+
+	var out bytes.Buffer
+	if err := commands.MergeOrderCommand(cfg, base, ours, theirs, &out); err != nil {
+		t.Fatalf("空の共通祖先でmerge-orderコマンドが失敗しました: %v\n%s", err, out.String())
+	}
+
+	// 相手が先頭に追加した行は先頭に入る
+	expected := "id,title,epic,estimate\n2,Issue2,1,1\n1,Issue1,1,1\n"
+	if got := readFile(t, ours); got != expected {
+		t.Errorf("マージ結果が不正です:\n期待値:\n%s\n実際:\n%s", expected, got)
+	}
+}
@@ -0,0 +1,127 @@
+// test/move_command_test.go
+package test
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/moai/instant-backlog/internal/commands"
+	"github.com/moai/instant-backlog/internal/config"
+)
+
+// 並び替え用のバックログを作成（order.csvは1〜5の順、6はorder.csvにない未完了、7は完了済み）
+func setupMoveProject(t *testing.T, cfg *config.Config) {
+	t.Helper()
+
+	createTestBacklog(t, cfg, 5)
+	createTestIssue(t, cfg, 6, "Issue6", "Open", 1, 1)
+	createTestIssue(t, cfg, 7, "Issue7", "Close", 1, 1)
+}
+
+/**
+ * moveコマンドで指定した位置にIssueを移動できること
+ *
+ * 複数のIDを指定した場合は指定した順序のまとまりとして移動し、
+ * order.csvにない未完了のIssueは移動先に追加されることを確認します。
+ */
+func TestMoveCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		ids      []int
+		opts     commands.MoveOptions
+		expected []int
+	}{
+		{"先頭", []int{4}, commands.MoveOptions{Top: true}, []int{4, 1, 2, 3, 5}},
+		{"末尾", []int{1}, commands.MoveOptions{Bottom: true}, []int{2, 3, 4, 5, 1}},
+		{"直前", []int{5}, commands.MoveOptions{Before: 2}, []int{1, 5, 2, 3, 4}},
+		{"直後", []int{1}, commands.MoveOptions{After: 3}, []int{2, 3, 1, 4, 5}},
+		{"順位", []int{1}, commands.MoveOptions{Position: 3}, []int{2, 3, 1, 4, 5}},
+		{"複数", []int{5, 2}, commands.MoveOptions{Top: true}, []int{5, 2, 1, 3, 4}},
+		{"order.csvにないIssue", []int{6}, commands.MoveOptions{After: 1}, []int{1, 6, 2, 3, 4, 5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, cleanup := setupTestEnvironment(t)
+			defer cleanup()
+			setupMoveProject(t, cfg)
+
+			if err := commands.MoveCommand(cfg, tt.ids, tt.opts); err != nil {
+				t.Fatalf("moveコマンドの実行に失敗しました: %v", err)
+			}
+			if ids := readOrderIDs(t, cfg); fmt.Sprint(ids) != fmt.Sprint(tt.expected) {
+				t.Errorf("order.csvの並び順が不正です: 期待値=%v, 実際=%v", tt.expected, ids)
+			}
+			assertNoTempFiles(t, cfg.ProjectsDir)
+		})
+	}
+}
+
+/**
+ * 移動できないIssueや不正な移動先を指定した場合、order.csvを変更せずにエラーになること
+ */
+func TestMoveCommandValidation(t *testing.T) {
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	setupMoveProject(t, cfg)
+
+	before := readFile(t, cfg.OrderCSV)
+
+	tests := []struct {
+		name string
+		ids  []int
+		opts commands.MoveOptions
+	}{
+		{"存在しないIssue", []int{99}, commands.MoveOptions{Top: true}},
+		{"完了済みのIssue", []int{7}, commands.MoveOptions{Top: true}},
+		{"重複したID", []int{1, 1}, commands.MoveOptions{Top: true}},
+		{"移動先なし", []int{1}, commands.MoveOptions{}},
+		{"移動先が複数", []int{1}, commands.MoveOptions{Top: true, After: 2}},
+		{"移動するIssueが基準", []int{1, 2}, commands.MoveOptions{After: 2}},
+		{"order.csvにない基準", []int{1}, commands.MoveOptions{Before: 6}},
+		{"範囲外の順位", []int{1}, commands.MoveOptions{Position: 6}},
+	}
+	for _, tt := range tests {
+		if err := commands.MoveCommand(cfg, tt.ids, tt.opts); err == nil {
+			t.Errorf("%s: エラーになりません", tt.name)
+		}
+	}
+
+	if after := readFile(t, cfg.OrderCSV); after != before {
+		t.Errorf("エラー時にorder.csvが変更されています:\n%s", after)
+	}
+}
+
+/**
+ * moveコマンドでorder.csvの追加の列が保持され、IDをカンマ区切りで指定できること
+ */
+func TestMoveCommandPreservesColumns(t *testing.T) {
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	createTestEpic(t, cfg, 1, "Epic1", "Open")
+	createTestIssue(t, cfg, 1, "Issue1", "Open", 1, 1)
+	createTestIssue(t, cfg, 2, "Issue2", "Open", 1, 1)
+	if err := os.WriteFile(cfg.OrderCSV, []byte("id,title,epic,estimate,note\n1,Issue1,1,1,メモ1\n2,Issue2,1,1,メモ2\n"), 0644); err != nil {
+		t.Fatalf("テスト用CSVの作成に失敗しました: %v", err)
+	}
+
+	ids, err := commands.ParseIDs([]string{"2,1"})
+	if err != nil {
+		t.Fatalf("IDの解析に失敗しました: %v", err)
+	}
+	if err := commands.MoveCommand(cfg, ids, commands.MoveOptions{Top: true}); err != nil {
+		t.Fatalf("moveコマンドの実行に失敗しました: %v", err)
+	}
+
+	expected := "id,title,epic,estimate,note\n2,Issue2,1,1,メモ2\n1,Issue1,1,1,メモ1\n"
+	if got := readFile(t, cfg.OrderCSV); got != expected {
+		t.Errorf("order.csvの内容が不正です:\n%s", got)
+	}
+
+	if _, err := commands.ParseIDs([]string{"1", "x"}); err == nil || !strings.Contains(err.Error(), "x") {
+		t.Errorf("整数でないIDがエラーになりません: %v", err)
+	}
+}
@@ -0,0 +1,129 @@
+// test/new_command_test.go
+package test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/moai/instant-backlog/internal/commands"
+	"github.com/moai/instant-backlog/internal/models"
+	"github.com/moai/instant-backlog/internal/parser"
+)
+
+/**
+ * newコマンドで次の空きIDを自動採番してIssueを作成できること
+ *
+ * Open/Closeを問わず既存のすべてのIssueファイルから次のIDを採番し、
+ * 指定したFront Matterでファイルを作成したうえで、order.csvに即座に追加されることを確認します。
+ */
+func TestNewIssueAllocatesNextID(t *testing.T) {
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	createTestEpic(t, cfg, 1, "既存エピック", "Open")
+	createTestIssue(t, cfg, 1, "既存タスク", "Open", 1, 3)
+	createTestIssue(t, cfg, 5, "完了済みタスク", "Close", 1, 2)
+	createTestOrderCSV(t, cfg, []models.OrderCSVItem{{ID: 1, Title: "既存タスク", Epic: 1, Estimate: 3}})
+
+	err := commands.NewIssueCommand(cfg, commands.NewIssueOptions{
+		Title:    "新しいタスク",
+		Epic:     1,
+		Estimate: 8,
+	})
+	if err != nil {
+		t.Fatalf("new issueコマンドの実行に失敗しました: %v", err)
+	}
+
+	// Closeのファイルも含めた最大ID+1が採番されること
+	issuePath := filepath.Join(cfg.IssuesDir, "6_O_新しいタスク.md")
+	issue, err := parser.ParseIssueFile(issuePath)
+	if err != nil {
+		t.Fatalf("作成されたIssueの読み込みに失敗しました: %v", err)
+	}
+	if issue.ID != 6 || issue.Status != "Open" || issue.Epic != 1 || issue.Estimate != 8 {
+		t.Errorf("作成されたIssueのFront Matterが不正です: %+v", issue)
+	}
+
+	// order.csvに追加されていること
+	if ids := readOrderIDs(t, cfg); len(ids) != 2 || ids[1] != 6 {
+		t.Errorf("作成されたIssueがorder.csvに追加されていません: %v", ids)
+	}
+}
+
+/**
+ * タイトルやEpicが不正な場合はIssueを作成しないこと
+ */
+func TestNewIssueValidation(t *testing.T) {
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	if err := commands.NewIssueCommand(cfg, commands.NewIssueOptions{Title: "", Epic: 1}); err == nil {
+		t.Errorf("タイトルが空でもエラーになりません")
+	}
+	if err := commands.NewIssueCommand(cfg, commands.NewIssueOptions{Title: "Epicなし"}); err == nil {
+		t.Errorf("Epicが未指定でもエラーになりません")
+	}
+
+	files, _ := os.ReadDir(cfg.IssuesDir)
+	if len(files) != 0 {
+		t.Errorf("不正な指定でIssueファイルが作成されています: %d件", len(files))
+	}
+}
+
+/**
+ * newコマンドでEpicを作成できること
+ */
+func TestNewEpicAllocatesNextID(t *testing.T) {
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	createTestEpic(t, cfg, 2, "完了済みエピック", "Close")
+
+	if err := commands.NewEpicCommand(cfg, commands.NewEpicOptions{Title: "新しいエピック"}); err != nil {
+		t.Fatalf("new epicコマンドの実行に失敗しました: %v", err)
+	}
+
+	epic, err := parser.ParseEpicFile(filepath.Join(cfg.EpicDir, "3_O_新しいエピック.md"))
+	if err != nil {
+		t.Fatalf("作成されたEpicの読み込みに失敗しました: %v", err)
+	}
+	if epic.ID != 3 || epic.Status != "Open" {
+		t.Errorf("作成されたEpicのFront Matterが不正です: %+v", epic)
+	}
+}
+
+/**
+ * --edit 指定時は$EDITORで開き、編集後の内容で同期されること
+ */
+func TestNewIssueOpensEditor(t *testing.T) {
+	if _, err := exec.LookPath("sed"); err != nil {
+		t.Skip("sedコマンドが利用できないためスキップします")
+	}
+
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	createTestEpic(t, cfg, 1, "エピック", "Open")
+
+	// エディタの代わりにsedでタイトルを書き換える
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "sed -i s/仮タイトル/編集後タイトル/")
+
+	err := commands.NewIssueCommand(cfg, commands.NewIssueOptions{Title: "仮タイトル", Epic: 1, Edit: true})
+	if err != nil {
+		t.Fatalf("new issueコマンドの実行に失敗しました: %v", err)
+	}
+
+	// 編集後のタイトルでファイル名が更新されていること
+	editedPath := filepath.Join(cfg.IssuesDir, "1_O_編集後タイトル.md")
+	content, err := os.ReadFile(editedPath)
+	if err != nil {
+		t.Fatalf("編集後のIssueファイルが見つかりません: %v", err)
+	}
+	if !strings.Contains(string(content), "title: 編集後タイトル") {
+		t.Errorf("エディタでの編集内容が反映されていません:\n%s", string(content))
+	}
+}
@@ -0,0 +1,125 @@
+// test/order_columns_test.go
+package test
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/moai/instant-backlog/internal/commands"
+	"github.com/moai/instant-backlog/internal/config"
+	"github.com/moai/instant-backlog/internal/parser"
+)
+
+/**
+ * order_columnsで設定したFront Matterのフィールドがorder.csvの列として出力されること
+ *
+ * リストのフィールドは「, 」で連結され、Front Matterにないフィールドは空欄になることを確認します。
+ */
+func TestOrderColumnsFromFrontMatter(t *testing.T) {
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	cfg.OrderColumns = []string{"status", "assignee", "labels", "sprint"}
+
+	createTestEpic(t, cfg, 1, "Epic1", "Open")
+	path := createTestIssueWithFields(t, cfg, 1, "担当者あり", "Open", 1, 3, "assignee: tanaka", "labels:\n  - backend\n  - api", "sprint: 2026-S3")
+	createTestIssue(t, cfg, 2, "担当者なし", "Open", 1, 1)
+
+	if err := commands.SyncCommand(cfg); err != nil {
+		t.Fatalf("syncコマンドの実行に失敗しました: %v", err)
+	}
+
+	expected := "id,title,epic,estimate,status,assignee,labels,sprint\n" +
+		"1,担当者あり,1,3,Open,tanaka,\"backend, api\",2026-S3\n" +
+		"2,担当者なし,1,1,Open,,,\n"
+	if got := readFile(t, cfg.OrderCSV); got != expected {
+		t.Errorf("order.csvの内容が不正です:\n期待値:\n%s\n実際:\n%s", expected, got)
+	}
+
+	// Front Matterの変更がorder.csvに反映される
+	rewriteIssue(t, path, "assignee: tanaka", "assignee: suzuki")
+
+	var out bytes.Buffer
+	if err := commands.SyncDryRunCommand(cfg, commands.SyncOptions{}, &out); err != nil {
+		t.Fatalf("sync --dry-runの実行に失敗しました: %v", err)
+	}
+	if line := "  ~ 1 担当者あり（assignee: tanaka → suzuki）"; !strings.Contains(out.String(), line) {
+		t.Errorf("変更内容に %q が含まれていません:\n%s", line, out.String())
+	}
+}
+
+/**
+ * order.csvにユーザーが追加した列が、syncの後もそのまま保持されること
+ *
+ * 完了したIssueの行は削除され、追加したIssueの行は空欄になることを確認します。
+ */
+func TestOrderColumnsPreserveUnknown(t *testing.T) {
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	cfg.OrderColumns = []string{"status"}
+
+	createTestEpic(t, cfg, 1, "Epic1", "Open")
+	createTestIssue(t, cfg, 1, "メモあり", "Open", 1, 3)
+	createTestIssue(t, cfg, 2, "完了", "Close", 1, 1)
+	createTestIssue(t, cfg, 3, "追加", "Open", 1, 2)
+	csv := "id,note,title,epic,estimate,priority\n" +
+		"1,\"顧客要望, \"\"至急\"\"\",メモあり,1,3,高\n" +
+		"2,完了済み,完了,1,1,低\n"
+	if err := os.WriteFile(cfg.OrderCSV, []byte(csv), 0644); err != nil {
+		t.Fatalf("テスト用CSVの作成に失敗しました: %v", err)
+	}
+
+	if err := commands.SyncCommand(cfg); err != nil {
+		t.Fatalf("syncコマンドの実行に失敗しました: %v", err)
+	}
+
+	expected := "id,title,epic,estimate,status,note,priority\n" +
+		"1,メモあり,1,3,Open,\"顧客要望, \"\"至急\"\"\",高\n" +
+		"3,追加,1,2,Open,,\n"
+	if got := readFile(t, cfg.OrderCSV); got != expected {
+		t.Errorf("order.csvの内容が不正です:\n期待値:\n%s\n実際:\n%s", expected, got)
+	}
+
+	items, columns, err := parser.ReadOrderCSVWithColumns(cfg.OrderCSV)
+	if err != nil {
+		t.Fatalf("order.csvの読み込みに失敗しました: %v", err)
+	}
+	if strings.Join(columns, ",") != "status,note,priority" || items[0].Extra["note"] != `顧客要望, "至急"` {
+		t.Errorf("追加した列が読み込まれていません: columns=%v, items=%+v", columns, items)
+	}
+
+	// 再度syncしても変更されない
+	var out bytes.Buffer
+	if err := commands.SyncDryRunCommand(cfg, commands.SyncOptions{}, &out); err != nil {
+		t.Fatalf("sync --dry-runの実行に失敗しました: %v", err)
+	}
+	if strings.TrimSpace(out.String()) != "変更はありません" {
+		t.Errorf("同期後に変更内容が表示されています:\n%s", out.String())
+	}
+}
+
+/**
+ * order_columnsに固定の列や重複した列を指定した場合、設定の読み込みがエラーになること
+ */
+func TestOrderColumnsValidation(t *testing.T) {
+	for _, content := range []string{
+		"order_columns: [status, title]\n",
+		"order_columns: [assignee, assignee]\n",
+		"order_columns: [\"\"]\n",
+	} {
+		rootDir := setupProjectRoot(t, content)
+		if _, err := config.Load(config.LoadOptions{ProjectDir: rootDir}); err == nil {
+			t.Errorf("不正なorder_columnsがエラーになりません: %q", content)
+		}
+	}
+
+	rootDir := setupProjectRoot(t, "order_columns: [status, sprint]\n")
+	cfg, err := config.Load(config.LoadOptions{ProjectDir: rootDir})
+	if err != nil {
+		t.Fatalf("設定の読み込みに失敗しました: %v", err)
+	}
+	if strings.Join(cfg.OrderColumns, ",") != "status,sprint" {
+		t.Errorf("order_columnsが読み込まれていません: %v", cfg.OrderColumns)
+	}
+}
@@ -0,0 +1,135 @@
+// test/order_csv_watch_test.go
+package test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/moai/instant-backlog/internal/commands"
+	"github.com/moai/instant-backlog/internal/logger"
+	"github.com/moai/instant-backlog/internal/parser"
+	"github.com/moai/instant-backlog/internal/watcher"
+)
+
+/**
+ * 手で編集した不正なorder.csvでもsyncが失敗せず、行の順序を保ったまま正規化されること
+ *
+ * IDが不正な行・存在しないIssueの行・完了済みのIssueの行・重複した行は削除し、
+ * epic/estimateが不正な行はIssueファイルの値で置き換え、order.csvにない未完了のIssueを追加します。
+ */
+func TestSyncNormalizesEditedOrderCSV(t *testing.T) {
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	logs := captureLogs(t, logger.Options{})
+
+	createTestEpic(t, cfg, 1, "Epic1", "Open")
+	createTestIssue(t, cfg, 1, "Issue1", "Open", 1, 3)
+	createTestIssue(t, cfg, 2, "Issue2", "Close", 1, 1)
+	createTestIssue(t, cfg, 3, "Issue3", "Open", 1, 1)
+	createTestIssue(t, cfg, 4, "Issue4", "Open", 1, 1)
+	createTestIssue(t, cfg, 5, "Issue5", "Open", 1, 1)
+	issuePath := filepath.Join(cfg.IssuesDir, "1_O_Issue1.md")
+	before := readFile(t, issuePath)
+
+	edited := "id, title ,epic,estimate,note\n" +
+		" 3 , Issue3 ,1,1,メモ\n" +
+		"abc,typo,1,1,\n" +
+		"1,Issue1,1,x,\n" +
+		"99,Unknown,1,1,\n" +
+		"2,Issue2,1,1,\n" +
+		"5\n" +
+		"3,Issue3,1,1,\n"
+	if err := os.WriteFile(cfg.OrderCSV, []byte(edited), 0644); err != nil {
+		t.Fatalf("テスト用CSVの作成に失敗しました: %v", err)
+	}
+
+	if err := commands.SyncCommand(cfg); err != nil {
+		t.Fatalf("syncコマンドの実行に失敗しました: %v", err)
+	}
+
+	expected := "id,title,epic,estimate,note\n3,Issue3,1,1,メモ\n1,Issue1,1,3,\n5,Issue5,1,1,\n4,Issue4,1,1,\n"
+	if got := readFile(t, cfg.OrderCSV); got != expected {
+		t.Errorf("order.csvが正規化されていません:\n期待値:\n%s\n実際:\n%s", expected, got)
+	}
+	if readFile(t, issuePath) != before {
+		t.Errorf("不正な値がIssueファイルに書き戻されています")
+	}
+	for _, message := range []string{"IDが整数ではありません", "存在しないIssueの行", "重複している行", "Issueファイルの値を使用します"} {
+		if !strings.Contains(logs.String(), message) {
+			t.Errorf("警告が出力されていません: %s\n%s", message, logs.String())
+		}
+	}
+}
+
+/**
+ * 寛容な読み込みで、不正な行の問題が行番号とともに報告されること
+ */
+func TestReadOrderCSVWithProblems(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "order.csv")
+	if err := os.WriteFile(path, []byte("id,title,epic,estimate\n1,A,1,1\nx,B,1,1\n2,C,,1\n"), 0644); err != nil {
+		t.Fatalf("テスト用CSVの作成に失敗しました: %v", err)
+	}
+
+	items, _, problems, err := parser.ReadOrderCSVWithProblems(path)
+	if err != nil {
+		t.Fatalf("order.csvの読み込みに失敗しました: %v", err)
+	}
+	if ids := itemIDs(items); fmt.Sprint(ids) != fmt.Sprint([]int{1, 2}) {
+		t.Errorf("IDが不正な行だけが読み飛ばされていません: %v", ids)
+	}
+	if len(problems) != 2 || problems[0].Line != 3 || problems[0].ID != 0 || problems[1].Line != 4 || problems[1].ID != 2 {
+		t.Errorf("問題が正しく報告されていません: %+v", problems)
+	}
+
+	if err := os.WriteFile(path, []byte("title,epic\nA,1\n"), 0644); err != nil {
+		t.Fatalf("テスト用CSVの作成に失敗しました: %v", err)
+	}
+	if _, _, _, err := parser.ReadOrderCSVWithProblems(path); err == nil {
+		t.Errorf("id列がないのにエラーになりません")
+	}
+}
+
+/**
+ * 監視中にorder.csvを手で編集すると、並び順を保ったまま検証・正規化されること
+ */
+func TestWatcherReactsToOrderCSV(t *testing.T) {
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	setupMoveProject(t, cfg)
+	if err := commands.SyncCommand(cfg); err != nil {
+		t.Fatalf("syncコマンドの実行に失敗しました: %v", err)
+	}
+
+	executor := &CountingCommandExecutor{}
+	watcher.SetCommandExecutor(executor)
+	defer watcher.SetCommandExecutor(&MockCommandExecutor{})
+
+	absPath, err := filepath.Abs(cfg.ProjectsDir)
+	if err != nil {
+		t.Fatalf("絶対パスの取得に失敗しました: %v", err)
+	}
+	manager := watcher.GetManager()
+	if err := manager.StartWatching(absPath, 100*time.Millisecond); err != nil {
+		t.Fatalf("監視の開始に失敗しました: %v", err)
+	}
+	defer manager.StopWatching(absPath)
+	time.Sleep(200 * time.Millisecond) // 初期化のための待機
+
+	// 並び替えて2の行を削除し、存在しない99の行を追加する
+	edited := "id,title,epic,estimate\n5,Issue5,1,1\n99,Unknown,1,1\n1,Issue1,1,1\n6,Issue6,1,1\n3,Issue3,1,1\n4,Issue4,1,1\n"
+	if err := os.WriteFile(cfg.OrderCSV, []byte(edited), 0644); err != nil {
+		t.Fatalf("order.csvの書き込みに失敗しました: %v", err)
+	}
+	time.Sleep(500 * time.Millisecond)
+
+	if ids := readOrderIDs(t, cfg); fmt.Sprint(ids) != fmt.Sprint([]int{5, 1, 6, 3, 4, 2}) {
+		t.Errorf("order.csvが並び順を保ったまま正規化されていません: %v", ids)
+	}
+	if count := executor.syncCount.Load(); count != 1 {
+		t.Errorf("syncの実行回数が不正です: 期待値=1, 実際=%d", count)
+	}
+}
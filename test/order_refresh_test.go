@@ -0,0 +1,82 @@
+// test/order_refresh_test.go
+package test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/moai/instant-backlog/internal/commands"
+	"github.com/moai/instant-backlog/internal/logger"
+	"github.com/moai/instant-backlog/internal/models"
+	"github.com/moai/instant-backlog/internal/parser"
+)
+
+/**
+ * syncでorder.csvのタイトル・Epic・見積もりがIssueファイルの内容に更新されること
+ *
+ * 行の並び順は維持され、更新した行がログに出力されることを確認します。
+ */
+func TestSyncRefreshesOrderColumns(t *testing.T) {
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	createTestEpic(t, cfg, 1, "Epic1", "Open")
+	createTestEpic(t, cfg, 2, "Epic2", "Open")
+	createTestOrderCSV(t, cfg, []models.OrderCSVItem{
+		{ID: 3, Title: "古いタイトル", Epic: 1, Estimate: 5},
+		{ID: 1, Title: "変更なし", Epic: 1, Estimate: 1},
+		{ID: 2, Title: "見積もり変更", Epic: 1, Estimate: 2},
+	})
+	createTestIssue(t, cfg, 1, "変更なし", "Open", 1, 1)
+	createTestIssue(t, cfg, 2, "見積もり変更", "Open", 2, 8)
+	createTestIssue(t, cfg, 3, "新しいタイトル", "Open", 1, 5)
+
+	// dry-runで変更される行を確認できる
+	var out bytes.Buffer
+	if err := commands.SyncDryRunCommand(cfg, commands.SyncOptions{}, &out); err != nil {
+		t.Fatalf("sync --dry-runの実行に失敗しました: %v", err)
+	}
+	for _, line := range []string{
+		"  ~ 3 新しいタイトル（title: 古いタイトル → 新しいタイトル）",
+		"  ~ 2 見積もり変更（epic: 1 → 2, estimate: 2 → 8）",
+	} {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("変更内容に %q が含まれていません:\n%s", line, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "~ 1 ") {
+		t.Errorf("変更のない行が表示されています:\n%s", out.String())
+	}
+
+	logs := captureLogs(t, logger.Options{})
+	if err := commands.SyncCommand(cfg); err != nil {
+		t.Fatalf("syncコマンドの実行に失敗しました: %v", err)
+	}
+
+	items, err := parser.ReadOrderCSV(cfg.OrderCSV)
+	if err != nil {
+		t.Fatalf("order.csvの読み込みに失敗しました: %v", err)
+	}
+	expected := []models.OrderCSVItem{
+		{ID: 3, Title: "新しいタイトル", Epic: 1, Estimate: 5},
+		{ID: 1, Title: "変更なし", Epic: 1, Estimate: 1},
+		{ID: 2, Title: "見積もり変更", Epic: 2, Estimate: 8},
+	}
+	if len(items) != len(expected) {
+		t.Fatalf("order.csvの行数が不正です: %v", items)
+	}
+	for i := range expected {
+		if !items[i].Equal(expected[i]) {
+			t.Errorf("%d行目が更新されていません: 期待値=%+v, 実際=%+v", i+1, expected[i], items[i])
+		}
+	}
+
+	if !strings.Contains(logs.String(), "order.csvの行をIssueファイルの内容で更新しました id=3") ||
+		!strings.Contains(logs.String(), "order.csvの行をIssueファイルの内容で更新しました id=2") {
+		t.Errorf("更新した行がログに出力されていません:\n%s", logs.String())
+	}
+	if strings.Contains(logs.String(), "更新しました id=1") {
+		t.Errorf("変更のない行が更新として出力されています:\n%s", logs.String())
+	}
+}
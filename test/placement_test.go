@@ -0,0 +1,162 @@
+// test/placement_test.go
+package test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/moai/instant-backlog/internal/commands"
+	"github.com/moai/instant-backlog/internal/config"
+	"github.com/moai/instant-backlog/internal/models"
+)
+
+/**
+ * 新しいIssueが設定した位置(placement)に追加されること
+ *
+ * 末尾・先頭・同じEpicの直後・priorityの順のそれぞれで、
+ * 同じ位置に追加されるIssueがID順に並ぶことを確認します。
+ */
+func TestPlacementPolicies(t *testing.T) {
+	tests := []struct {
+		placement config.PlacementPolicy
+		expected  []int
+	}{
+		{"", []int{1, 2, 3, 4, 5}},
+		{config.PlacementBottom, []int{1, 2, 3, 4, 5}},
+		{config.PlacementTop, []int{3, 4, 5, 1, 2}},
+		{config.PlacementEpic, []int{1, 4, 5, 2, 3}},
+		{config.PlacementPriority, []int{5, 1, 4, 2, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.placement), func(t *testing.T) {
+			cfg, cleanup := setupTestEnvironment(t)
+			defer cleanup()
+			cfg.Placement = tt.placement
+
+			createTestEpic(t, cfg, 1, "Epic1", "Open")
+			createTestEpic(t, cfg, 2, "Epic2", "Open")
+			createTestOrderCSV(t, cfg, []models.OrderCSVItem{
+				{ID: 1, Title: "既存1", Epic: 1, Estimate: 1},
+				{ID: 2, Title: "既存2", Epic: 2, Estimate: 1},
+			})
+			createTestIssueWithFields(t, cfg, 1, "既存1", "Open", 1, 1, "priority: 2")
+			createTestIssueWithFields(t, cfg, 2, "既存2", "Open", 2, 1, "priority: 5")
+			createTestIssueWithFields(t, cfg, 3, "優先度なし", "Open", 2, 1)
+			createTestIssueWithFields(t, cfg, 4, "優先度3", "Open", 1, 1, "priority: 3")
+			createTestIssueWithFields(t, cfg, 5, "優先度1", "Open", 1, 1, "priority: 1")
+
+			if err := commands.SyncCommand(cfg); err != nil {
+				t.Fatalf("syncコマンドの実行に失敗しました: %v", err)
+			}
+
+			ids := readOrderIDs(t, cfg)
+			if fmt.Sprint(ids) != fmt.Sprint(tt.expected) {
+				t.Errorf("order.csvの並び順が不正です: 期待値=%v, 実際=%v", tt.expected, ids)
+			}
+		})
+	}
+}
+
+/**
+ * priorityの順で追加する場合、priorityが未設定の行があっても優先度の高い行より上に追加されないこと
+ *
+ * priorityが同じか小さい最後の行の直後に追加し、そのような行がなければ
+ * priorityが設定された最初の行の直前に追加されることを確認します。
+ */
+func TestPlacementPriorityWithUnprioritizedRows(t *testing.T) {
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	cfg.Placement = config.PlacementPriority
+
+	createTestEpic(t, cfg, 1, "Epic1", "Open")
+	createTestOrderCSV(t, cfg, []models.OrderCSVItem{
+		{ID: 1, Title: "優先度なし", Epic: 1, Estimate: 1},
+		{ID: 2, Title: "優先度1", Epic: 1, Estimate: 1},
+		{ID: 3, Title: "優先度5", Epic: 1, Estimate: 1},
+	})
+	createTestIssueWithFields(t, cfg, 1, "優先度なし", "Open", 1, 1)
+	createTestIssueWithFields(t, cfg, 2, "優先度1", "Open", 1, 1, "priority: 1")
+	createTestIssueWithFields(t, cfg, 3, "優先度5", "Open", 1, 1, "priority: 5")
+	createTestIssueWithFields(t, cfg, 4, "追加優先度2", "Open", 1, 1, "priority: 2")
+	createTestIssueWithFields(t, cfg, 5, "追加優先度0", "Open", 1, 1, "priority: 0")
+	createTestIssueWithFields(t, cfg, 6, "追加優先度なし", "Open", 1, 1)
+
+	if err := commands.SyncCommand(cfg); err != nil {
+		t.Fatalf("syncコマンドの実行に失敗しました: %v", err)
+	}
+
+	expected := []int{1, 5, 2, 4, 3, 6}
+	if ids := readOrderIDs(t, cfg); fmt.Sprint(ids) != fmt.Sprint(expected) {
+		t.Errorf("order.csvの並び順が不正です: 期待値=%v, 実際=%v", expected, ids)
+	}
+}
+
+/**
+ * doctor --fix でorder.csvに追加するIssueもsyncと同じ位置(placement)に追加されること
+ */
+func TestPlacementDoctorFix(t *testing.T) {
+	tests := []struct {
+		placement config.PlacementPolicy
+		expected  []int
+	}{
+		{config.PlacementTop, []int{3, 4, 5, 1, 2}},
+		{config.PlacementEpic, []int{1, 4, 5, 2, 3}},
+		{config.PlacementPriority, []int{5, 1, 4, 2, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.placement), func(t *testing.T) {
+			cfg, cleanup := setupTestEnvironment(t)
+			defer cleanup()
+			cfg.Placement = tt.placement
+
+			createTestEpic(t, cfg, 1, "Epic1", "Open")
+			createTestEpic(t, cfg, 2, "Epic2", "Open")
+			createTestOrderCSV(t, cfg, []models.OrderCSVItem{
+				{ID: 1, Title: "既存1", Epic: 1, Estimate: 1},
+				{ID: 2, Title: "既存2", Epic: 2, Estimate: 1},
+			})
+			createTestIssueWithFields(t, cfg, 1, "既存1", "Open", 1, 1, "priority: 2")
+			createTestIssueWithFields(t, cfg, 2, "既存2", "Open", 2, 1, "priority: 5")
+			createTestIssueWithFields(t, cfg, 3, "優先度なし", "Open", 2, 1)
+			createTestIssueWithFields(t, cfg, 4, "優先度3", "Open", 1, 1, "priority: 3")
+			createTestIssueWithFields(t, cfg, 5, "優先度1", "Open", 1, 1, "priority: 1")
+
+			var out bytes.Buffer
+			if err := commands.DoctorCommand(cfg, commands.DoctorOptions{Fix: true}, &out); err != nil {
+				t.Fatalf("doctor --fixの実行に失敗しました: %v\n%s", err, out.String())
+			}
+
+			ids := readOrderIDs(t, cfg)
+			if fmt.Sprint(ids) != fmt.Sprint(tt.expected) {
+				t.Errorf("order.csvの並び順が不正です: 期待値=%v, 実際=%v", tt.expected, ids)
+			}
+		})
+	}
+}
+
+/**
+ * placementを設定ファイルと環境変数で指定でき、不明な値はエラーになること
+ */
+func TestPlacementConfig(t *testing.T) {
+	rootDir := setupProjectRoot(t, "placement: epic\n")
+	cfg, err := config.Load(config.LoadOptions{ProjectDir: rootDir})
+	if err != nil {
+		t.Fatalf("設定の読み込みに失敗しました: %v", err)
+	}
+	if cfg.PlacementPolicy() != config.PlacementEpic {
+		t.Errorf("placementが読み込まれていません: %s", cfg.PlacementPolicy())
+	}
+
+	t.Setenv(config.EnvPlacement, "top")
+	if cfg, err = config.Load(config.LoadOptions{ProjectDir: rootDir}); err != nil || cfg.PlacementPolicy() != config.PlacementTop {
+		t.Errorf("環境変数でplacementを上書きできません: %v", err)
+	}
+
+	t.Setenv(config.EnvPlacement, "middle")
+	if _, err := config.Load(config.LoadOptions{ProjectDir: rootDir}); err == nil {
+		t.Errorf("不明なplacementがエラーになりません")
+	}
+}
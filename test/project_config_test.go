@@ -0,0 +1,210 @@
+// test/project_config_test.go
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/moai/instant-backlog/internal/commands"
+	"github.com/moai/instant-backlog/internal/config"
+	"github.com/moai/instant-backlog/internal/models"
+)
+
+// テスト用のプロジェクト設定ファイル
+const projectConfigYAML = `projects_dir: backlog
+issues_dir: tasks
+order_csv: priority.csv
+debounce: 200ms
+`
+
+// 設定ファイルを置いたプロジェクトのルートディレクトリを作成
+func setupProjectRoot(t *testing.T, content string) string {
+	t.Helper()
+
+	rootDir := t.TempDir()
+	// macOSなどで一時ディレクトリがシンボリックリンクの場合に備えて実パスにする
+	rootDir, err := filepath.EvalSymlinks(rootDir)
+	if err != nil {
+		t.Fatalf("一時ディレクトリの解決に失敗しました: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(rootDir, config.FileName), []byte(content), 0644); err != nil {
+		t.Fatalf("設定ファイルの作成に失敗しました: %v", err)
+	}
+	// 前回のsyncの内容などをホームディレクトリに保存しないよう、状態ディレクトリも一時ディレクトリにする
+	t.Setenv(config.EnvStateDir, filepath.Join(rootDir, "state"))
+	return rootDir
+}
+
+/**
+ * サブディレクトリから実行しても設定ファイルを見つけられること
+ *
+ * カレントディレクトリから親ディレクトリへ遡って.instant-backlog.yamlを探索し、
+ * 設定ファイルのあるディレクトリを基準にディレクトリ構成が決まることを確認します。
+ */
+func TestConfigDiscoveryFromSubdirectory(t *testing.T) {
+	rootDir := setupProjectRoot(t, projectConfigYAML)
+	subDir := filepath.Join(rootDir, "src", "pkg")
+	if err := os.MkdirAll(subDir, 0755); err != nil {
+		t.Fatalf("サブディレクトリの作成に失敗しました: %v", err)
+	}
+	t.Chdir(subDir)
+
+	cfg, err := config.Load(config.LoadOptions{})
+	if err != nil {
+		t.Fatalf("設定の読み込みに失敗しました: %v", err)
+	}
+
+	projectsDir := filepath.Join(rootDir, "backlog")
+	expected := map[string][2]string{
+		"BaseDir":     {cfg.BaseDir, rootDir},
+		"ConfigFile":  {cfg.ConfigFile, filepath.Join(rootDir, config.FileName)},
+		"ProjectsDir": {cfg.ProjectsDir, projectsDir},
+		"EpicDir":     {cfg.EpicDir, filepath.Join(projectsDir, "epic")},
+		"IssuesDir":   {cfg.IssuesDir, filepath.Join(projectsDir, "tasks")},
+		"OrderCSV":    {cfg.OrderCSV, filepath.Join(projectsDir, "priority.csv")},
+	}
+	for name, v := range expected {
+		if v[0] != v[1] {
+			t.Errorf("%sが不正です。期待値: %s, 実際: %s", name, v[1], v[0])
+		}
+	}
+	if cfg.DebounceTime() != 200*time.Millisecond {
+		t.Errorf("デバウンス時間が不正です: %v", cfg.DebounceTime())
+	}
+
+	// 設定したディレクトリ構成でコマンドが動作すること
+	for _, dir := range []string{cfg.EpicDir, cfg.IssuesDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("ディレクトリの作成に失敗しました: %v", err)
+		}
+	}
+	if err := commands.NewEpicCommand(cfg, commands.NewEpicOptions{Title: "設定テスト"}); err != nil {
+		t.Fatalf("Epicの作成に失敗しました: %v", err)
+	}
+	if err := commands.NewIssueCommand(cfg, commands.NewIssueOptions{Title: "サブディレクトリから作成", Epic: 1}); err != nil {
+		t.Fatalf("Issueの作成に失敗しました: %v", err)
+	}
+	if !fileExists(filepath.Join(cfg.IssuesDir, "1_O_サブディレクトリから作成.md")) {
+		t.Errorf("設定したissuesディレクトリにIssueが作成されていません")
+	}
+	if ids := readOrderIDs(t, cfg); len(ids) != 1 || ids[0] != 1 {
+		t.Errorf("設定したorder.csvにIssueが追加されていません: %v", ids)
+	}
+}
+
+/**
+ * --projectで指定したディレクトリの設定を使用できること
+ *
+ * 監視プロセスなどprojectsディレクトリから設定を求める場合も、
+ * 親ディレクトリの設定ファイルのディレクトリ構成が使用されることを確認します。
+ */
+func TestConfigProjectFlag(t *testing.T) {
+	rootDir := setupProjectRoot(t, projectConfigYAML)
+	t.Chdir(t.TempDir())
+
+	cfg, err := config.Load(config.LoadOptions{ProjectDir: rootDir})
+	if err != nil {
+		t.Fatalf("設定の読み込みに失敗しました: %v", err)
+	}
+	if cfg.ProjectsDir != filepath.Join(rootDir, "backlog") {
+		t.Errorf("--projectで指定した設定が使用されていません: %s", cfg.ProjectsDir)
+	}
+
+	// projectsディレクトリから設定を求める
+	projectCfg, err := config.ForProject(cfg.ProjectsDir)
+	if err != nil {
+		t.Fatalf("設定の読み込みに失敗しました: %v", err)
+	}
+	if projectCfg.IssuesDir != cfg.IssuesDir || projectCfg.OrderCSV != cfg.OrderCSV {
+		t.Errorf("projectsディレクトリからの設定が一致しません: %+v", projectCfg)
+	}
+
+	// 存在しないディレクトリはエラー
+	if _, err := config.Load(config.LoadOptions{ProjectDir: filepath.Join(rootDir, "missing")}); err == nil {
+		t.Errorf("存在しないプロジェクトディレクトリがエラーになりません")
+	}
+}
+
+/**
+ * 環境変数(IB_*)で設定ファイルの値を上書きできること
+ */
+func TestConfigEnvironmentOverrides(t *testing.T) {
+	rootDir := setupProjectRoot(t, projectConfigYAML+`statuses:
+  - name: Todo
+    prefix: T
+  - name: Done
+    prefix: D
+    done: true
+`)
+	t.Chdir(rootDir)
+
+	templateDir := filepath.Join(rootDir, "templates")
+	t.Setenv(config.EnvProjectsDir, "work")
+	t.Setenv(config.EnvIssuesDir, "issues")
+	t.Setenv(config.EnvTemplatePath, templateDir)
+	t.Setenv(config.EnvDebounce, "1500")
+	t.Setenv(config.EnvStateDir, "state")
+
+	cfg, err := config.Load(config.LoadOptions{})
+	if err != nil {
+		t.Fatalf("設定の読み込みに失敗しました: %v", err)
+	}
+
+	workDir := filepath.Join(rootDir, "work")
+	if cfg.ProjectsDir != workDir || cfg.IssuesDir != filepath.Join(workDir, "issues") {
+		t.Errorf("ディレクトリが環境変数で上書きされていません: %s, %s", cfg.ProjectsDir, cfg.IssuesDir)
+	}
+	// 環境変数で指定していない値は設定ファイルの値を使用
+	if cfg.OrderCSV != filepath.Join(workDir, "priority.csv") {
+		t.Errorf("設定ファイルの値が使用されていません: %s", cfg.OrderCSV)
+	}
+	if cfg.TemplatePath != templateDir {
+		t.Errorf("テンプレートパスが環境変数で上書きされていません: %s", cfg.TemplatePath)
+	}
+	if cfg.DebounceTime() != 1500*time.Millisecond {
+		t.Errorf("デバウンス時間が環境変数で上書きされていません: %v", cfg.DebounceTime())
+	}
+	if cfg.StateDir != filepath.Join(rootDir, "state") {
+		t.Errorf("状態ディレクトリが環境変数で上書きされていません: %s", cfg.StateDir)
+	}
+	if _, ok := cfg.StatusSet().Find("Todo"); !ok {
+		t.Errorf("設定ファイルのステータス定義が使用されていません: %v", cfg.StatusSet().Names())
+	}
+
+	// 不正な値はエラー
+	t.Setenv(config.EnvDebounce, "すぐ")
+	if _, err := config.Load(config.LoadOptions{}); err == nil {
+		t.Errorf("不正なデバウンス時間がエラーになりません")
+	}
+}
+
+/**
+ * 設定ファイルがない場合は従来どおりカレントディレクトリのprojectsを使用すること
+ */
+func TestConfigDefaultsWithoutFile(t *testing.T) {
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatalf("一時ディレクトリの解決に失敗しました: %v", err)
+	}
+	t.Chdir(dir)
+
+	cfg, err := config.Load(config.LoadOptions{})
+	if err != nil {
+		t.Fatalf("設定の読み込みに失敗しました: %v", err)
+	}
+	if cfg.ConfigFile != "" && filepath.Dir(cfg.ConfigFile) != dir {
+		// 一時ディレクトリの上位に設定ファイルがある環境ではスキップ
+		t.Skipf("一時ディレクトリの上位に設定ファイルがあります: %s", cfg.ConfigFile)
+	}
+	if cfg.ProjectsDir != filepath.Join(dir, "projects") || cfg.OrderCSV != filepath.Join(dir, "projects", "order.csv") {
+		t.Errorf("デフォルトのディレクトリ構成が使用されていません: %+v", cfg)
+	}
+	if cfg.DebounceTime() != config.DefaultDebounce {
+		t.Errorf("デフォルトのデバウンス時間が使用されていません: %v", cfg.DebounceTime())
+	}
+	if len(cfg.StatusSet()) != len(models.DefaultStatusSet()) {
+		t.Errorf("デフォルトのステータスが使用されていません: %v", cfg.StatusSet().Names())
+	}
+}
@@ -0,0 +1,262 @@
+// test/rank_order_test.go
+package test
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/moai/instant-backlog/internal/commands"
+	"github.com/moai/instant-backlog/internal/config"
+	"github.com/moai/instant-backlog/pkg/utils"
+)
+
+// rankで並び順を管理するバックログを作成（rankの順は2, 1で、3はrankなし）
+func setupRankProject(t *testing.T, cfg *config.Config) map[int]string {
+	t.Helper()
+
+	cfg.Ordering = config.OrderingRank
+	createTestEpic(t, cfg, 1, "Epic1", "Open")
+	return map[int]string{
+		1: createTestIssueWithFields(t, cfg, 1, "Issue1", "Open", 1, 1, "rank: m"),
+		2: createTestIssueWithFields(t, cfg, 2, "Issue2", "Open", 1, 1, "rank: c"),
+		3: createTestIssueWithFields(t, cfg, 3, "Issue3", "Open", 1, 1),
+	}
+}
+
+/**
+ * 任意の位置への挿入を繰り返しても、rankの辞書順が挿入した順序と一致すること
+ */
+func TestRankBetween(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	var ranks []string
+	for i := 0; i < 500; i++ {
+		position := random.Intn(len(ranks) + 1)
+		prev, next := "", ""
+		if position > 0 {
+			prev = ranks[position-1]
+		}
+		if position < len(ranks) {
+			next = ranks[position]
+		}
+
+		rank := utils.RankBetween(prev, next)
+		if !utils.IsValidRank(rank) || rank <= prev || (next != "" && rank >= next) {
+			t.Fatalf("前後の間のrankになっていません: prev=%q, next=%q, rank=%q", prev, next, rank)
+		}
+		ranks = append(ranks[:position], append([]string{rank}, ranks[position:]...)...)
+	}
+
+	for _, n := range []int{1, 35, 36, 1000} {
+		ranks := utils.EvenRanks(n)
+		if len(ranks) != n {
+			t.Fatalf("rankの数が不正です: 期待値=%d, 実際=%d", n, len(ranks))
+		}
+		for i, rank := range ranks {
+			if !utils.IsValidRank(rank) || (i > 0 && ranks[i-1] >= rank) {
+				t.Errorf("等間隔のrankが昇順になっていません（n=%d）: %q, %q", n, ranks[max(i-1, 0)], rank)
+			}
+		}
+	}
+}
+
+/**
+ * rankで並び順を管理する場合、syncでorder.csvがrankの順に生成され、
+ * rankのないIssueにだけrankが設定されること
+ */
+func TestRankOrderSync(t *testing.T) {
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	paths := setupRankProject(t, cfg)
+
+	// order.csvの並び順は反映されず、追加の列は保持される
+	if err := os.WriteFile(cfg.OrderCSV, []byte("id,title,epic,estimate,note\n1,Issue1,1,1,メモ\n2,Issue2,1,1,\n"), 0644); err != nil {
+		t.Fatalf("テスト用CSVの作成に失敗しました: %v", err)
+	}
+	before := map[int]string{1: readFile(t, paths[1]), 2: readFile(t, paths[2])}
+
+	if err := commands.SyncCommand(cfg); err != nil {
+		t.Fatalf("syncコマンドの実行に失敗しました: %v", err)
+	}
+
+	expected := "id,title,epic,estimate,note\n2,Issue2,1,1,\n1,Issue1,1,1,メモ\n3,Issue3,1,1,\n"
+	if got := readFile(t, cfg.OrderCSV); got != expected {
+		t.Errorf("order.csvがrankの順に生成されていません:\n%s", got)
+	}
+	for id, content := range before {
+		if readFile(t, paths[id]) != content {
+			t.Errorf("rankのあるIssueファイルが変更されています: %d", id)
+		}
+	}
+	if rank := readIssue(t, paths[3]).Rank; rank <= "m" || !utils.IsValidRank(rank) {
+		t.Errorf("追加したIssueに末尾のrankが設定されていません: %q", rank)
+	}
+	if fileExists(cfg.SyncBaseFile()) {
+		t.Errorf("rankで管理する場合に前回の同期内容が保存されています")
+	}
+
+	// 2回目のsyncでは変更がない
+	var out bytes.Buffer
+	if err := commands.SyncDryRunCommand(cfg, commands.SyncOptions{}, &out); err != nil {
+		t.Fatalf("dry-runの実行に失敗しました: %v", err)
+	}
+	if !strings.Contains(out.String(), "変更はありません") {
+		t.Errorf("同期済みなのに変更があります:\n%s", out.String())
+	}
+}
+
+/**
+ * rankに使用しない文字（大文字や記号）を含むrankがあっても、syncが異常終了せずrankを割り当て直すこと
+ */
+func TestRankOrderInvalidRanks(t *testing.T) {
+	for _, pair := range [][2]string{{"-", "0"}, {"A", "B"}, {"a", "B"}, {"~", ""}, {"", "-"}} {
+		if rank := utils.RankBetween(pair[0], pair[1]); rank == "" {
+			t.Errorf("rankが生成されていません: prev=%q, next=%q", pair[0], pair[1])
+		}
+	}
+
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	cfg.Ordering = config.OrderingRank
+	cfg.Placement = config.PlacementEpic
+	createTestEpic(t, cfg, 1, "Epic1", "Open")
+	createTestEpic(t, cfg, 2, "Epic2", "Open")
+	paths := []string{
+		createTestIssueWithFields(t, cfg, 1, "Issue1", "Open", 1, 1, "rank: A"),
+		createTestIssueWithFields(t, cfg, 2, "Issue2", "Open", 1, 1, "rank: B"),
+		createTestIssueWithFields(t, cfg, 3, "Issue3", "Open", 1, 1),
+		createTestIssueWithFields(t, cfg, 4, "Issue4", "Open", 1, 1, "rank: _"),
+	}
+	rewriteIssue(t, paths[1], "epic: 1", "epic: 2")
+
+	if err := commands.SyncCommand(cfg); err != nil {
+		t.Fatalf("syncコマンドの実行に失敗しました: %v", err)
+	}
+
+	ranks := make(map[int]string, len(paths))
+	for i, path := range paths {
+		rank := readIssue(t, path).Rank
+		if !utils.IsValidRank(rank) {
+			t.Errorf("不正なrankが割り当て直されていません（ID=%d）: %q", i+1, rank)
+		}
+		ranks[i+1] = rank
+	}
+	ids := readOrderIDs(t, cfg)
+	if len(ids) != len(paths) {
+		t.Fatalf("order.csvの行数が不正です: %v", ids)
+	}
+	for i := 1; i < len(ids); i++ {
+		if ranks[ids[i-1]] >= ranks[ids[i]] {
+			t.Errorf("order.csvの並び順とrankの順序が一致しません: %v, %v", ids, ranks)
+		}
+	}
+}
+
+/**
+ * rankで並び順を管理する場合、moveコマンドは移動したIssueのファイルだけを変更すること
+ */
+func TestRankOrderMove(t *testing.T) {
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	paths := setupRankProject(t, cfg)
+	if err := commands.SyncCommand(cfg); err != nil {
+		t.Fatalf("syncコマンドの実行に失敗しました: %v", err)
+	}
+	before := snapshotFiles(t, cfg.IssuesDir)
+
+	if err := commands.MoveCommand(cfg, []int{3}, commands.MoveOptions{After: 2}); err != nil {
+		t.Fatalf("moveコマンドの実行に失敗しました: %v", err)
+	}
+
+	if ids := readOrderIDs(t, cfg); fmt.Sprint(ids) != fmt.Sprint([]int{2, 3, 1}) {
+		t.Errorf("order.csvの並び順が不正です: %v", ids)
+	}
+	after := snapshotFiles(t, cfg.IssuesDir)
+	for path, content := range before {
+		if changed := after[path] != content; changed != (path == paths[3]) {
+			t.Errorf("移動したIssue以外のファイルが変更されたか、移動したIssueが変更されていません: %s", path)
+		}
+	}
+	if rank := readIssue(t, paths[3]).Rank; rank <= "c" || rank >= "m" {
+		t.Errorf("移動先の前後の間のrankになっていません: %q", rank)
+	}
+}
+
+/**
+ * order.csvとrankの間で並び順を保ったまま移行できること
+ */
+func TestMigrateOrder(t *testing.T) {
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	setupMoveProject(t, cfg)
+
+	var out bytes.Buffer
+	if err := commands.MigrateOrderCommand(cfg, config.OrderingRank, &out); err != nil {
+		t.Fatalf("rankへの移行に失敗しました: %v", err)
+	}
+	if !strings.Contains(out.String(), "ordering: rank") {
+		t.Errorf("設定の変更が案内されていません:\n%s", out.String())
+	}
+
+	// order.csvの並び順（order.csvにない6は末尾）でrankが設定される
+	issues := snapshotFiles(t, cfg.IssuesDir)
+	var ranks []string
+	for id := 1; id <= 6; id++ {
+		rank := readIssue(t, filepath.Join(cfg.IssuesDir, fmt.Sprintf("%d_O_Issue%d.md", id, id))).Rank
+		if rank == "" || (len(ranks) > 0 && ranks[len(ranks)-1] >= rank) {
+			t.Errorf("order.csvの並び順でrankが設定されていません: ID=%d, rank=%q, 前=%v", id, rank, ranks)
+		}
+		ranks = append(ranks, rank)
+	}
+	for path, content := range issues {
+		if strings.Contains(path, "_C_") && strings.Contains(content, "rank:") {
+			t.Errorf("完了済みのIssueにrankが設定されています: %s", path)
+		}
+	}
+
+	// rankの並び順を変更してからcsvに戻す
+	cfg.Ordering = config.OrderingRank
+	if err := commands.MoveCommand(cfg, []int{5}, commands.MoveOptions{Top: true}); err != nil {
+		t.Fatalf("moveコマンドの実行に失敗しました: %v", err)
+	}
+	out.Reset()
+	if err := commands.MigrateOrderCommand(cfg, config.OrderingCSV, &out); err != nil {
+		t.Fatalf("csvへの移行に失敗しました: %v", err)
+	}
+	if ids := readOrderIDs(t, cfg); fmt.Sprint(ids) != fmt.Sprint([]int{5, 1, 2, 3, 4, 6}) {
+		t.Errorf("rankの並び順でorder.csvが書き込まれていません: %v", ids)
+	}
+	for path, content := range snapshotFiles(t, cfg.IssuesDir) {
+		if strings.Contains(content, "rank:") {
+			t.Errorf("rankが削除されていません: %s", path)
+		}
+	}
+}
+
+/**
+ * orderingを設定ファイルと環境変数で指定でき、不明な値はエラーになること
+ */
+func TestOrderingConfig(t *testing.T) {
+	rootDir := setupProjectRoot(t, "ordering: rank\n")
+	cfg, err := config.Load(config.LoadOptions{ProjectDir: rootDir})
+	if err != nil {
+		t.Fatalf("設定の読み込みに失敗しました: %v", err)
+	}
+	if cfg.OrderingMode() != config.OrderingRank {
+		t.Errorf("orderingが読み込まれていません: %s", cfg.OrderingMode())
+	}
+
+	t.Setenv(config.EnvOrdering, "csv")
+	if cfg, err = config.Load(config.LoadOptions{ProjectDir: rootDir}); err != nil || cfg.OrderingMode() != config.OrderingCSV {
+		t.Errorf("環境変数でorderingを上書きできません: %v", err)
+	}
+
+	t.Setenv(config.EnvOrdering, "fractional")
+	if _, err := config.Load(config.LoadOptions{ProjectDir: rootDir}); err == nil {
+		t.Errorf("不明なorderingがエラーになりません")
+	}
+}
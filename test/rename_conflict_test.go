@@ -0,0 +1,232 @@
+// test/rename_conflict_test.go
+package test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/moai/instant-backlog/internal/commands"
+	"github.com/moai/instant-backlog/internal/config"
+	"github.com/moai/instant-backlog/internal/fileops"
+)
+
+// Issueファイルを指定したファイル名でそのまま作成
+func writeRawIssue(t *testing.T, cfg *config.Config, filename, status, body string) string {
+	t.Helper()
+
+	path := filepath.Join(cfg.IssuesDir, filename)
+	content := "---\nid: 1\ntitle: 衝突するIssue\nstatus: " + status + "\nepic: 1\nestimate: 3\n---\n\n" + body + "\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("テスト用Issueの作成に失敗しました: %v", err)
+	}
+	return path
+}
+
+// リネーム先と衝突するIssueファイルを作成
+// 1_O_衝突するIssue.md はFront Matterが「Close」のため 1_C_衝突するIssue.md にリネームされる
+func setupRenameConflict(t *testing.T, cfg *config.Config, sourceBody, targetBody string) (source, target string) {
+	t.Helper()
+
+	target = writeRawIssue(t, cfg, "1_C_衝突するIssue.md", "Close", targetBody)
+	source = writeRawIssue(t, cfg, "1_O_衝突するIssue.md", "Close", sourceBody)
+	return source, target
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ファイルの読み込みに失敗しました: %v", err)
+	}
+	return string(data)
+}
+
+/**
+ * リネーム先のファイルが既に存在する場合、既存ファイルを削除せずエラーとして報告すること
+ *
+ * デフォルトの方針(skip)では両方のファイルが残り、renameコマンドはエラーを返します。
+ */
+func TestRenameConflictSkip(t *testing.T) {
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	source, target := setupRenameConflict(t, cfg, "リネーム元の本文", "既存の本文")
+
+	err := commands.RenameCommand(cfg)
+	if err == nil {
+		t.Fatalf("ファイル名の衝突がエラーになりません")
+	}
+	var collision *fileops.CollisionError
+	if !errors.As(err, &collision) || collision.Target != target {
+		t.Errorf("衝突したファイルがエラーに含まれていません: %v", err)
+	}
+
+	if !strings.Contains(readFile(t, source), "リネーム元の本文") || !strings.Contains(readFile(t, target), "既存の本文") {
+		t.Errorf("衝突したファイルの内容が変更されています")
+	}
+
+	// syncでも同様にエラーになる
+	createTestOrderCSV(t, cfg, nil)
+	if err := commands.SyncCommand(cfg); err == nil {
+		t.Errorf("syncでファイル名の衝突がエラーになりません")
+	}
+}
+
+/**
+ * 内容がまったく同じファイルが既に存在する場合は、重複ファイルとして削除されること
+ */
+func TestRenameConflictIdenticalContent(t *testing.T) {
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	source, target := setupRenameConflict(t, cfg, "同じ本文", "同じ本文")
+
+	if err := commands.RenameCommand(cfg); err != nil {
+		t.Fatalf("renameコマンドの実行に失敗しました: %v", err)
+	}
+	if fileExists(source) {
+		t.Errorf("重複ファイルが削除されていません: %s", source)
+	}
+	if !strings.Contains(readFile(t, target), "同じ本文") {
+		t.Errorf("リネーム先のファイルの内容が変更されています")
+	}
+}
+
+/**
+ * conflicts方針ではリネーム元のファイルがconflictsディレクトリに退避されること
+ */
+func TestRenameConflictMoveToConflicts(t *testing.T) {
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	cfg.OnConflict = config.ConflictMove
+
+	for i := 0; i < 2; i++ {
+		source, target := setupRenameConflict(t, cfg, "リネーム元の本文", "既存の本文")
+
+		if err := commands.RenameCommand(cfg); err != nil {
+			t.Fatalf("renameコマンドの実行に失敗しました: %v", err)
+		}
+		if fileExists(source) {
+			t.Errorf("リネーム元のファイルが残っています: %s", source)
+		}
+		if !strings.Contains(readFile(t, target), "既存の本文") {
+			t.Errorf("リネーム先のファイルの内容が変更されています")
+		}
+	}
+
+	// 同名のファイルは連番を付けて退避される
+	conflictsDir := filepath.Join(cfg.ProjectsDir, config.ConflictsDirName, "issues")
+	for _, name := range []string{"1_O_衝突するIssue.md", "1_O_衝突するIssue.1.md"} {
+		path := filepath.Join(conflictsDir, name)
+		if !fileExists(path) {
+			t.Fatalf("衝突したファイルが退避されていません: %s", path)
+		}
+		if !strings.Contains(readFile(t, path), "リネーム元の本文") {
+			t.Errorf("退避したファイルの内容が不正です: %s", path)
+		}
+	}
+}
+
+/**
+ * merge方針ではリネーム元の本文がリネーム先に追記されること
+ *
+ * Front Matterが異なる場合は、その内容が失われないようリネーム元も退避されます。
+ */
+func TestRenameConflictMerge(t *testing.T) {
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	cfg.OnConflict = config.ConflictMerge
+
+	// Front Matterが同じ場合は統合してリネーム元を削除
+	source, target := setupRenameConflict(t, cfg, "追加の本文", "既存の本文")
+	if err := commands.RenameCommand(cfg); err != nil {
+		t.Fatalf("renameコマンドの実行に失敗しました: %v", err)
+	}
+	merged := readFile(t, target)
+	if !strings.Contains(merged, "既存の本文") || !strings.Contains(merged, "追加の本文") {
+		t.Errorf("本文が統合されていません:\n%s", merged)
+	}
+	if fileExists(source) {
+		t.Errorf("統合元のファイルが残っています: %s", source)
+	}
+
+	// Front Matterが異なる場合は統合したうえでリネーム元を退避
+	source = writeRawIssue(t, cfg, "1_O_衝突するIssue.md", "Close", "別の本文")
+	content := strings.Replace(readFile(t, source), "estimate: 3", "estimate: 8", 1)
+	if err := os.WriteFile(source, []byte(content), 0644); err != nil {
+		t.Fatalf("テスト用Issueの作成に失敗しました: %v", err)
+	}
+	if err := commands.RenameCommand(cfg); err != nil {
+		t.Fatalf("renameコマンドの実行に失敗しました: %v", err)
+	}
+	if merged := readFile(t, target); !strings.Contains(merged, "別の本文") || strings.Contains(merged, "estimate: 8") {
+		t.Errorf("本文のみが統合されていません:\n%s", merged)
+	}
+	backup := filepath.Join(cfg.ProjectsDir, config.ConflictsDirName, "issues", "1_O_衝突するIssue.md")
+	if !fileExists(backup) || !strings.Contains(readFile(t, backup), "estimate: 8") {
+		t.Errorf("Front Matterが異なる統合元が退避されていません: %s", backup)
+	}
+}
+
+/**
+ * 衝突時の処理方法を設定ファイルと環境変数で指定できること
+ */
+func TestRenameConflictPolicyConfig(t *testing.T) {
+	rootDir := setupProjectRoot(t, "on_conflict: merge\n")
+	t.Chdir(rootDir)
+
+	cfg, err := config.Load(config.LoadOptions{})
+	if err != nil {
+		t.Fatalf("設定の読み込みに失敗しました: %v", err)
+	}
+	if cfg.ConflictPolicy() != config.ConflictMerge {
+		t.Errorf("設定ファイルの衝突時の処理方法が使用されていません: %s", cfg.ConflictPolicy())
+	}
+	if cfg.ConflictsDir() != filepath.Join(rootDir, "projects", config.ConflictsDirName) {
+		t.Errorf("conflictsディレクトリが不正です: %s", cfg.ConflictsDir())
+	}
+
+	t.Setenv(config.EnvOnConflict, "conflicts")
+	if cfg, err = config.Load(config.LoadOptions{}); err != nil {
+		t.Fatalf("設定の読み込みに失敗しました: %v", err)
+	}
+	if cfg.ConflictPolicy() != config.ConflictMove {
+		t.Errorf("環境変数の衝突時の処理方法が使用されていません: %s", cfg.ConflictPolicy())
+	}
+
+	// 不明な方針はエラー
+	t.Setenv(config.EnvOnConflict, "overwrite")
+	if _, err := config.Load(config.LoadOptions{}); err == nil {
+		t.Errorf("不明な衝突時の処理方法がエラーになりません")
+	}
+}
+
+/**
+ * リネーム先がリネーム元と同じファイルを指す場合（大文字・小文字を区別しないファイルシステムでの
+ * 大文字・小文字のみの変更）に、重複ファイルとして削除せずにリネームすること
+ *
+ * テストではリネーム先をリネーム元へのハードリンクとして作成して同じ状況を再現します。
+ */
+func TestRenameSameFile(t *testing.T) {
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	source := writeRawIssue(t, cfg, "1_O_foo.md", "Open", "唯一の本文")
+	target := filepath.Join(cfg.IssuesDir, "1_O_Foo.md")
+	if err := os.Link(source, target); err != nil {
+		t.Skipf("ハードリンクを作成できません: %v", err)
+	}
+
+	for _, policy := range []config.ConflictPolicy{config.ConflictSkip, config.ConflictMove, config.ConflictMerge} {
+		if err := fileops.RenameWithPolicy(source, target, policy, cfg.ConflictsDir()); err != nil {
+			t.Fatalf("同じファイルへのリネームに失敗しました（%s）: %v", policy, err)
+		}
+		if !fileExists(target) || !strings.Contains(readFile(t, target), "唯一の本文") {
+			t.Fatalf("同じファイルへのリネームでファイルが失われました（%s）", policy)
+		}
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/moai/instant-backlog/internal/fileops"
 	"github.com/moai/instant-backlog/internal/models"
 	"github.com/moai/instant-backlog/internal/parser"
+	"github.com/moai/instant-backlog/pkg/utils"
 )
 
 // テスト用の一時ディレクトリを作成
@@ -30,6 +31,10 @@ func setupTestEnvironment(t *testing.T) (*config.Config, func()) {
 		t.Fatalf("テスト環境のセットアップに失敗しました: %v", err)
 	}
 
+	// 前回のsyncの内容などが前のテストから引き継がれないよう、状態ディレクトリもテストごとに指定する
+	// （監視で設定ファイルから読み込む場合にも使われるよう環境変数で指定する）
+	t.Setenv(config.EnvStateDir, filepath.Join(tempDir, "state"))
+
 	// テスト用の設定を作成
 	cfg := &config.Config{
 		ProjectsDir: filepath.Join(tempDir, "projects"),
@@ -80,6 +85,39 @@ func createTestIssue(t *testing.T, cfg *config.Config, id int, title, status str
 	}
 }
 
+// テスト用のIssueファイルをFront Matterを直接書き込んで作成し、ファイルのパスを返す
+// extraはFront Matterに追加する行（「priority: 1」「rank: m」など）で、指定した順に書き込む
+func createTestIssueWithFields(t *testing.T, cfg *config.Config, id int, title, status string, epicID, estimate int, extra ...string) string {
+	t.Helper()
+
+	content := fmt.Sprintf("---\nid: %d\ntitle: %s\nstatus: %s\nepic: %d\nestimate: %d\n", id, title, status, epicID, estimate)
+	for _, line := range extra {
+		content += line + "\n"
+	}
+	content += "---\n\n本文\n"
+
+	path := filepath.Join(cfg.IssuesDir, utils.GenerateFilename(id, status, title))
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("テスト用Issueの作成に失敗しました: %v", err)
+	}
+	return path
+}
+
+// テスト用のバックログを作成する
+// Epic1に未完了・見積もり1のIssue1〜nを作成し、order.csvにその順で記載する
+func createTestBacklog(t *testing.T, cfg *config.Config, n int) {
+	t.Helper()
+
+	createTestEpic(t, cfg, 1, "Epic1", "Open")
+	items := make([]models.OrderCSVItem, 0, n)
+	for id := 1; id <= n; id++ {
+		title := fmt.Sprintf("Issue%d", id)
+		createTestIssue(t, cfg, id, title, "Open", 1, 1)
+		items = append(items, models.OrderCSVItem{ID: id, Title: title, Epic: 1, Estimate: 1})
+	}
+	createTestOrderCSV(t, cfg, items)
+}
+
 // テスト用CSVファイルを作成
 func createTestOrderCSV(t *testing.T, cfg *config.Config, items []models.OrderCSVItem) {
 	t.Helper()
@@ -0,0 +1,231 @@
+// test/two_way_sync_test.go
+package test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/moai/instant-backlog/internal/commands"
+	"github.com/moai/instant-backlog/internal/logger"
+	"github.com/moai/instant-backlog/internal/models"
+	"github.com/moai/instant-backlog/internal/parser"
+)
+
+// ファイルの更新日時を現在時刻からずらす
+func touch(t *testing.T, path string, offset time.Duration) {
+	t.Helper()
+
+	mtime := time.Now().Add(offset)
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatalf("更新日時の変更に失敗しました: %v", err)
+	}
+}
+
+// Issueファイルを読み込む
+func readIssue(t *testing.T, path string) *models.Issue {
+	t.Helper()
+
+	issue, err := parser.ParseIssueFile(path)
+	if err != nil {
+		t.Fatalf("Issueファイルの読み込みに失敗しました: %v", err)
+	}
+	return issue
+}
+
+/**
+ * 前回の同期内容がない場合、更新日時に関係なくIssueファイルの値を採用すること
+ *
+ * クローン直後のように前回の同期内容がなく、order.csvの方が新しい場合でも、
+ * order.csvの古い値をIssueファイルに書き戻さず、order.csvをIssueファイルの値で更新することを確認します。
+ */
+func TestTwoWaySyncWithoutBase(t *testing.T) {
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	createTestEpic(t, cfg, 1, "Epic1", "Open")
+	createTestEpic(t, cfg, 2, "Epic2", "Open")
+	createTestIssue(t, cfg, 1, "見積もり変更", "Open", 1, 8)
+	createTestIssue(t, cfg, 2, "Epic変更", "Open", 2, 1)
+	issuePath := filepath.Join(cfg.IssuesDir, "1_O_見積もり変更.md")
+	touch(t, issuePath, -time.Hour)
+
+	// Issueファイルより後にorder.csvを書き込む
+	createTestOrderCSV(t, cfg, []models.OrderCSVItem{
+		{ID: 1, Title: "見積もり変更", Epic: 1, Estimate: 3},
+		{ID: 2, Title: "Epic変更", Epic: 1, Estimate: 1},
+	})
+
+	logs := captureLogs(t, logger.Options{})
+	if err := commands.SyncCommand(cfg); err != nil {
+		t.Fatalf("syncコマンドの実行に失敗しました: %v", err)
+	}
+
+	if issue := readIssue(t, issuePath); issue.Epic != 1 || issue.Estimate != 8 {
+		t.Errorf("order.csvの古い値がIssueファイルに書き戻されています: epic=%d, estimate=%d", issue.Epic, issue.Estimate)
+	}
+	if strings.Contains(logs.String(), "order.csvの変更をIssueファイルに書き戻しました") {
+		t.Errorf("前回の同期内容がないのに書き戻しが行われています:\n%s", logs.String())
+	}
+
+	items, err := parser.ReadOrderCSV(cfg.OrderCSV)
+	if err != nil {
+		t.Fatalf("order.csvの読み込みに失敗しました: %v", err)
+	}
+	expected := []models.OrderCSVItem{
+		{ID: 1, Title: "見積もり変更", Epic: 1, Estimate: 8},
+		{ID: 2, Title: "Epic変更", Epic: 2, Estimate: 1},
+	}
+	for i := range expected {
+		if !items[i].Equal(expected[i]) {
+			t.Errorf("%d行目が不正です: 期待値=%+v, 実際=%+v", i+1, expected[i], items[i])
+		}
+	}
+
+	// 次回のsyncのために同期した内容が保存される
+	if !fileExists(cfg.SyncBaseFile()) {
+		t.Errorf("同期内容が保存されていません: %s", cfg.SyncBaseFile())
+	}
+	// 作業環境ごとの状態のため、gitで管理するprojectsディレクトリには保存しない
+	if strings.HasPrefix(cfg.SyncBaseFile(), cfg.ProjectsDir) {
+		t.Errorf("同期内容がprojectsディレクトリに保存されています: %s", cfg.SyncBaseFile())
+	}
+}
+
+/**
+ * 前回の同期内容と比較して、変更された側の値を採用すること
+ *
+ * 同期後にorder.csvだけを編集した場合は、更新日時に関係なくIssueファイルに書き戻されることを確認します。
+ */
+func TestTwoWaySyncWithBase(t *testing.T) {
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	createTestEpic(t, cfg, 1, "Epic1", "Open")
+	createTestIssue(t, cfg, 1, "見積もり変更", "Open", 1, 1)
+	createTestOrderCSV(t, cfg, []models.OrderCSVItem{{ID: 1, Title: "見積もり変更", Epic: 1, Estimate: 1}})
+	if err := commands.SyncCommand(cfg); err != nil {
+		t.Fatalf("syncコマンドの実行に失敗しました: %v", err)
+	}
+
+	// order.csvを編集した後に、Issueファイルの更新日時だけが新しくなった場合
+	createTestOrderCSV(t, cfg, []models.OrderCSVItem{{ID: 1, Title: "見積もり変更", Epic: 1, Estimate: 8}})
+	path := filepath.Join(cfg.IssuesDir, "1_O_見積もり変更.md")
+	touch(t, path, time.Hour)
+
+	var out bytes.Buffer
+	if err := commands.SyncDryRunCommand(cfg, commands.SyncOptions{}, &out); err != nil {
+		t.Fatalf("sync --dry-runの実行に失敗しました: %v", err)
+	}
+	if line := "  → 1 見積もり変更: Issueファイルに書き戻します（estimate: 1 → 8）"; !strings.Contains(out.String(), line) {
+		t.Errorf("変更内容に %q が含まれていません:\n%s", line, out.String())
+	}
+
+	if err := commands.SyncCommand(cfg); err != nil {
+		t.Fatalf("syncコマンドの実行に失敗しました: %v", err)
+	}
+	if issue := readIssue(t, path); issue.Estimate != 8 {
+		t.Errorf("order.csvの変更がIssueファイルに書き戻されていません: estimate=%d", issue.Estimate)
+	}
+	if !strings.Contains(readFile(t, path), "これはテスト用のタスク 1 です。") {
+		t.Errorf("書き戻しで本文が失われています:\n%s", readFile(t, path))
+	}
+
+	// 同期後は変更がない
+	out.Reset()
+	if err := commands.SyncDryRunCommand(cfg, commands.SyncOptions{}, &out); err != nil {
+		t.Fatalf("sync --dry-runの実行に失敗しました: %v", err)
+	}
+	if strings.TrimSpace(out.String()) != "変更はありません" {
+		t.Errorf("同期後に変更内容が表示されています:\n%s", out.String())
+	}
+}
+
+/**
+ * order.csvとIssueファイルの両方で同じ列が変更された場合、衝突として報告しどちらも変更しないこと
+ *
+ * 衝突は解消されるまで報告され続け、--from-csv ではorder.csvの値で解消できることを確認します。
+ */
+func TestTwoWaySyncConflict(t *testing.T) {
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	createTestEpic(t, cfg, 1, "Epic1", "Open")
+	createTestIssue(t, cfg, 1, "両方で変更", "Open", 1, 1)
+	createTestOrderCSV(t, cfg, []models.OrderCSVItem{{ID: 1, Title: "両方で変更", Epic: 1, Estimate: 1}})
+	if err := commands.SyncCommand(cfg); err != nil {
+		t.Fatalf("syncコマンドの実行に失敗しました: %v", err)
+	}
+
+	createTestOrderCSV(t, cfg, []models.OrderCSVItem{{ID: 1, Title: "両方で変更", Epic: 1, Estimate: 3}})
+	createTestIssue(t, cfg, 1, "両方で変更", "Open", 1, 5)
+	path := filepath.Join(cfg.IssuesDir, "1_O_両方で変更.md")
+
+	for i := 0; i < 2; i++ {
+		logs := captureLogs(t, logger.Options{})
+		if err := commands.SyncCommand(cfg); err != nil {
+			t.Fatalf("syncコマンドの実行に失敗しました: %v", err)
+		}
+		if !strings.Contains(logs.String(), "order.csvとIssueファイルの両方で変更されているため同期しませんでした id=1") ||
+			!strings.Contains(logs.String(), "estimate: order.csv=3, ファイル=5") {
+			t.Errorf("%d回目のsyncで衝突が報告されていません:\n%s", i+1, logs.String())
+		}
+		if issue := readIssue(t, path); issue.Estimate != 5 {
+			t.Errorf("衝突したIssueファイルが変更されています: estimate=%d", issue.Estimate)
+		}
+		if items, _ := parser.ReadOrderCSV(cfg.OrderCSV); items[0].Estimate != 3 {
+			t.Errorf("衝突したorder.csvの行が変更されています: %+v", items[0])
+		}
+	}
+
+	// --from-csv ではorder.csvの値を採用する
+	if err := commands.SyncCommandWithOptions(cfg, commands.SyncOptions{FromCSV: true}); err != nil {
+		t.Fatalf("sync --from-csvの実行に失敗しました: %v", err)
+	}
+	if issue := readIssue(t, path); issue.Estimate != 3 {
+		t.Errorf("--from-csvでorder.csvの値が書き戻されていません: estimate=%d", issue.Estimate)
+	}
+
+	var out bytes.Buffer
+	if err := commands.SyncDryRunCommand(cfg, commands.SyncOptions{}, &out); err != nil {
+		t.Fatalf("sync --dry-runの実行に失敗しました: %v", err)
+	}
+	if strings.TrimSpace(out.String()) != "変更はありません" {
+		t.Errorf("衝突の解消後に変更内容が表示されています:\n%s", out.String())
+	}
+}
+
+/**
+ * ExcelやGoogleスプレッドシートで保存した、先頭にBOMが付いたorder.csvでも同期できること
+ *
+ * BOMを含む列名で追加の列が読み込まれず、編集した値がIssueファイルに書き戻されることを確認します。
+ */
+func TestTwoWaySyncWithBOM(t *testing.T) {
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	createTestEpic(t, cfg, 1, "Epic1", "Open")
+	createTestIssue(t, cfg, 1, "見積もり変更", "Open", 1, 1)
+	createTestOrderCSV(t, cfg, []models.OrderCSVItem{{ID: 1, Title: "見積もり変更", Epic: 1, Estimate: 1}})
+	if err := commands.SyncCommand(cfg); err != nil {
+		t.Fatalf("syncコマンドの実行に失敗しました: %v", err)
+	}
+
+	content := "\ufeffid,title,epic,estimate,note\n1,見積もり変更,1,8,メモ\n"
+	if err := os.WriteFile(cfg.OrderCSV, []byte(content), 0644); err != nil {
+		t.Fatalf("order.csvの書き込みに失敗しました: %v", err)
+	}
+	if _, columns, err := parser.ReadOrderCSVWithColumns(cfg.OrderCSV); err != nil || len(columns) != 1 || columns[0] != "note" {
+		t.Errorf("BOM付きのorder.csvの列が正しく読み込まれません: columns=%q, err=%v", columns, err)
+	}
+
+	if err := commands.SyncCommand(cfg); err != nil {
+		t.Fatalf("BOM付きのorder.csvでsyncに失敗しました: %v", err)
+	}
+	if issue := readIssue(t, filepath.Join(cfg.IssuesDir, "1_O_見積もり変更.md")); issue.Estimate != 8 {
+		t.Errorf("order.csvの変更がIssueファイルに書き戻されていません: estimate=%d", issue.Estimate)
+	}
+}
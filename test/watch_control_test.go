@@ -0,0 +1,174 @@
+// test/watch_control_test.go
+package test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/moai/instant-backlog/internal/commands"
+	"github.com/moai/instant-backlog/internal/watcher"
+)
+
+// テスト用の状態ディレクトリを設定（ソケットパスの長さ制限を避けるため短いパスを使用）
+func setupStateDir(t *testing.T) string {
+	t.Helper()
+
+	stateDir, err := os.MkdirTemp("", "ib-state-")
+	if err != nil {
+		t.Fatalf("状態ディレクトリの作成に失敗しました: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(stateDir) })
+	t.Setenv("IB_STATE_DIR", stateDir)
+	return stateDir
+}
+
+/**
+ * 別のターミナルからunwatchで監視を停止できること
+ *
+ * 監視プロセスが制御ソケットを開いている場合、別プロセスから実行した
+ * unwatch / watch --list がそのプロセスの監視状態を操作・参照できることを確認します。
+ * （テストでは同一プロセス内で制御ソケット経由の操作を行います）
+ */
+func TestUnwatchFromAnotherProcess(t *testing.T) {
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	stateDir := setupStateDir(t)
+
+	watcher.SetCommandExecutor(&MockCommandExecutor{})
+	manager := watcher.GetManager()
+	defer manager.StopAll()
+
+	projectPath, _ := filepath.Abs(cfg.ProjectsDir)
+
+	// 監視プロセス側：監視を開始して制御ソケットを開く
+	if err := manager.StartWatching(projectPath, 100*time.Millisecond); err != nil {
+		t.Fatalf("監視の開始に失敗しました: %v", err)
+	}
+	server, err := watcher.StartControlServer(stateDir, manager)
+	if err != nil {
+		t.Fatalf("制御ソケットの作成に失敗しました: %v", err)
+	}
+	defer server.Close()
+
+	// 状態ディレクトリに監視中のプロジェクトが登録されていること
+	registry, err := watcher.ReadRegistry(stateDir)
+	if err != nil {
+		t.Fatalf("監視状態の読み込みに失敗しました: %v", err)
+	}
+	if len(registry.Projects) != 1 || registry.Projects[0] != projectPath || registry.PID != os.Getpid() {
+		t.Errorf("監視状態が正しく登録されていません: %+v", registry)
+	}
+
+	// 別プロセス側：watch --list で監視中のプロジェクトを確認
+	var out bytes.Buffer
+	if err := commands.WatchListCommand(cfg, &out); err != nil {
+		t.Fatalf("watch --listの実行に失敗しました: %v", err)
+	}
+	if !strings.Contains(out.String(), projectPath) {
+		t.Errorf("監視中のプロジェクトが一覧に表示されていません:\n%s", out.String())
+	}
+
+	// 別プロセス側：unwatchで監視を停止
+	if err := commands.UnwatchCommand(cfg, projectPath); err != nil {
+		t.Fatalf("unwatchの実行に失敗しました: %v", err)
+	}
+	if manager.IsWatching(projectPath) {
+		t.Errorf("unwatch後もプロジェクトが監視されています")
+	}
+
+	// 監視中のプロジェクトがなくなったことが監視プロセスに通知されること
+	select {
+	case <-server.Idle():
+	case <-time.After(time.Second):
+		t.Errorf("すべての監視が停止されたことが通知されません")
+	}
+
+	// 監視されていないプロジェクトのunwatchはエラー
+	if err := commands.UnwatchCommand(cfg, projectPath); err == nil {
+		t.Errorf("監視されていないプロジェクトのunwatchがエラーになりません")
+	}
+}
+
+/**
+ * 監視プロセスが起動中の場合、watchは監視対象の追加を依頼し、unwatch（引数なし）ですべて停止できること
+ */
+func TestWatchDelegatesToRunningProcess(t *testing.T) {
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	stateDir := setupStateDir(t)
+
+	watcher.SetCommandExecutor(&MockCommandExecutor{})
+	manager := watcher.GetManager()
+	defer manager.StopAll()
+
+	server, err := watcher.StartControlServer(stateDir, manager)
+	if err != nil {
+		t.Fatalf("制御ソケットの作成に失敗しました: %v", err)
+	}
+	defer server.Close()
+
+	// 2つ目の制御ソケットは開けないこと
+	if _, err := watcher.StartControlServer(stateDir, manager); err == nil {
+		t.Errorf("監視プロセスが二重に起動できてしまいます")
+	}
+
+	// watchコマンドは起動中のプロセスに監視を依頼してすぐに戻る
+	projectPath, _ := filepath.Abs(cfg.ProjectsDir)
+	if err := commands.WatchCommand(cfg, projectPath); err != nil {
+		t.Fatalf("watchコマンドの実行に失敗しました: %v", err)
+	}
+	if !manager.IsWatching(projectPath) {
+		t.Fatalf("起動中のプロセスで監視が開始されていません")
+	}
+
+	// unwatch（すべて）で停止
+	if err := commands.UnwatchAllCommand(cfg); err != nil {
+		t.Fatalf("unwatchの実行に失敗しました: %v", err)
+	}
+	if len(manager.GetWatchingProjects()) != 0 {
+		t.Errorf("すべての監視が停止されていません")
+	}
+
+	// 制御ソケットを閉じると監視プロセスなしとして扱われること
+	server.Close()
+	var out bytes.Buffer
+	if err := commands.WatchListCommand(cfg, &out); err != nil {
+		t.Fatalf("watch --listの実行に失敗しました: %v", err)
+	}
+	if !strings.Contains(out.String(), "起動していません") {
+		t.Errorf("監視プロセスが停止していることが表示されません:\n%s", out.String())
+	}
+}
+
+/**
+ * 末尾の区切り文字などの表記の違いがあっても同じプロジェクトとして監視・停止できること
+ */
+func TestUnwatchNormalizesProjectPath(t *testing.T) {
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	setupStateDir(t)
+
+	watcher.SetCommandExecutor(&MockCommandExecutor{})
+	manager := watcher.GetManager()
+	defer manager.StopAll()
+
+	projectPath, _ := filepath.Abs(cfg.ProjectsDir)
+
+	if err := manager.StartWatching(projectPath+string(filepath.Separator), 100*time.Millisecond); err != nil {
+		t.Fatalf("監視の開始に失敗しました: %v", err)
+	}
+	if !manager.IsWatching(projectPath) {
+		t.Errorf("末尾に区切り文字を付けたパスで監視したプロジェクトが監視中になっていません")
+	}
+
+	if err := commands.UnwatchCommand(cfg, projectPath); err != nil {
+		t.Fatalf("unwatchの実行に失敗しました: %v", err)
+	}
+	if manager.IsWatching(projectPath) {
+		t.Errorf("unwatch後もプロジェクトが監視されています")
+	}
+}
@@ -0,0 +1,148 @@
+// test/watcher_self_write_test.go
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/moai/instant-backlog/internal/commands"
+	"github.com/moai/instant-backlog/internal/config"
+	"github.com/moai/instant-backlog/internal/watcher"
+	"github.com/moai/instant-backlog/pkg/utils"
+)
+
+// CountingCommandExecutor - 実際のコマンドを実行し、syncの実行回数を数えるコマンド実行インスタンス
+type CountingCommandExecutor struct {
+	commands.CommandExecutorImpl
+	syncCount atomic.Int32
+	changed   [][]string // 増分syncに渡された変更されたファイル
+	mu        sync.Mutex
+}
+
+// ExecuteSync - 実行回数を数えてSyncCommandを実行
+func (e *CountingCommandExecutor) ExecuteSync(cfg *config.Config) error {
+	e.syncCount.Add(1)
+	return e.CommandExecutorImpl.ExecuteSync(cfg)
+}
+
+// ExecuteIncrementalSync - 実行回数と変更されたファイルを記録して増分syncを実行
+func (e *CountingCommandExecutor) ExecuteIncrementalSync(cfg *config.Config, changed []string) error {
+	e.syncCount.Add(1)
+	e.mu.Lock()
+	e.changed = append(e.changed, changed)
+	e.mu.Unlock()
+	return e.CommandExecutorImpl.ExecuteIncrementalSync(cfg, changed)
+}
+
+/**
+ * 自身が書き込んだ内容と一致するファイルだけが自身の書き込みとして扱われること
+ */
+func TestSelfWriteTracking(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "1_O_Issue1.md")
+	renamed := filepath.Join(dir, "1_C_Issue1.md")
+
+	if err := utils.WriteFileAtomic(path, []byte("内容"), 0644); err != nil {
+		t.Fatalf("ファイルの書き込みに失敗しました: %v", err)
+	}
+	if !utils.IsSelfWrite(path) {
+		t.Errorf("自身の書き込みとして扱われません")
+	}
+
+	// 内容が変わった後は他のプロセスによる変更として扱い、元の内容に戻しても記録は残らない
+	if err := os.WriteFile(path, []byte("ユーザーの編集"), 0644); err != nil {
+		t.Fatalf("ファイルの書き込みに失敗しました: %v", err)
+	}
+	if utils.IsSelfWrite(path) {
+		t.Errorf("他のプロセスによる変更が自身の書き込みとして扱われています")
+	}
+	if err := os.WriteFile(path, []byte("内容"), 0644); err != nil {
+		t.Fatalf("ファイルの書き込みに失敗しました: %v", err)
+	}
+	if utils.IsSelfWrite(path) {
+		t.Errorf("破棄した記録が残っています")
+	}
+
+	// リネーム元は削除、リネーム先は書き込みとして扱う
+	if err := utils.ReplaceFileAtomic(path, renamed, []byte("完了"), 0644); err != nil {
+		t.Fatalf("ファイルの置き換えに失敗しました: %v", err)
+	}
+	if !utils.IsSelfWrite(path) || !utils.IsSelfWrite(renamed) {
+		t.Errorf("リネームが自身の書き込みとして扱われません")
+	}
+}
+
+/**
+ * ユーザーの1回の編集に対してsyncが1回だけ実行されること
+ *
+ * Issueを完了にすると、syncがEpicのステータスを更新し、Issueとepicのファイル名を変更します。
+ * これらの自身の書き込みによるイベントで、もう一度syncが実行されないことを確認します。
+ */
+func TestWatcherIgnoresSelfWrites(t *testing.T) {
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	createTestBacklog(t, cfg, 1)
+	if err := commands.SyncCommand(cfg); err != nil {
+		t.Fatalf("syncコマンドの実行に失敗しました: %v", err)
+	}
+
+	executor := &CountingCommandExecutor{}
+	watcher.SetCommandExecutor(executor)
+	defer watcher.SetCommandExecutor(&MockCommandExecutor{})
+
+	absPath, err := filepath.Abs(cfg.ProjectsDir)
+	if err != nil {
+		t.Fatalf("絶対パスの取得に失敗しました: %v", err)
+	}
+	manager := watcher.GetManager()
+	if err := manager.StartWatching(absPath, 100*time.Millisecond); err != nil {
+		t.Fatalf("監視の開始に失敗しました: %v", err)
+	}
+	defer manager.StopWatching(absPath)
+	time.Sleep(200 * time.Millisecond) // 初期化のための待機
+
+	path := filepath.Join(cfg.IssuesDir, "1_O_Issue1.md")
+	content := strings.Replace(readFile(t, path), "status: Open", "status: Close", 1)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("ファイル書き込みに失敗しました: %v", err)
+	}
+
+	// 自身の書き込みによるイベントでsyncが再実行される場合も含めて待機する
+	time.Sleep(800 * time.Millisecond)
+
+	if !fileExists(filepath.Join(cfg.IssuesDir, "1_C_Issue1.md")) {
+		t.Fatalf("syncでファイル名が変更されていません")
+	}
+	if count := executor.syncCount.Load(); count != 1 {
+		t.Errorf("syncの実行回数が不正です: 期待値=1, 実際=%d", count)
+	}
+}
+
+/**
+ * 保持する時間を過ぎた書き込みの記録は破棄され、自身の書き込みとして扱われないこと
+ *
+ * 監視していないファイルの記録が、長時間動作する監視プロセスに残り続けないようにします。
+ */
+func TestSelfWriteExpires(t *testing.T) {
+	defer func(ttl time.Duration) { utils.SelfWriteTTL = ttl }(utils.SelfWriteTTL)
+	utils.SelfWriteTTL = 50 * time.Millisecond
+
+	path := filepath.Join(t.TempDir(), "1_O_Issue1.md")
+	if err := utils.WriteFileAtomic(path, []byte("内容"), 0644); err != nil {
+		t.Fatalf("ファイルの書き込みに失敗しました: %v", err)
+	}
+	if !utils.IsSelfWrite(path) {
+		t.Errorf("自身の書き込みとして扱われません")
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	if utils.IsSelfWrite(path) {
+		t.Errorf("保持する時間を過ぎた記録が自身の書き込みとして扱われています")
+	}
+}
@@ -0,0 +1,474 @@
+// test/webhooks_test.go
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/moai/instant-backlog/internal/commands"
+	"github.com/moai/instant-backlog/internal/config"
+	"github.com/moai/instant-backlog/internal/logger"
+	"github.com/moai/instant-backlog/internal/watcher"
+	"github.com/moai/instant-backlog/internal/webhooks"
+)
+
+// receivedWebhook - テスト用のエンドポイントが受信したリクエスト
+type receivedWebhook struct {
+	event     string
+	delivery  string
+	signature string
+	body      []byte
+}
+
+// webhookReceiver - 受信したリクエストを記録するテスト用のエンドポイント
+// failures回目までのリクエストには500を返す
+type webhookReceiver struct {
+	mu       sync.Mutex
+	received []receivedWebhook
+	failures int
+	requests int
+}
+
+// ServeHTTP - リクエストを記録して応答する
+func (r *webhookReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests++
+	if r.requests <= r.failures {
+		http.Error(w, "一時的なエラー", http.StatusInternalServerError)
+		return
+	}
+	r.received = append(r.received, receivedWebhook{
+		event:     req.Header.Get(webhooks.HeaderEvent),
+		delivery:  req.Header.Get(webhooks.HeaderDelivery),
+		signature: req.Header.Get(webhooks.HeaderSignature),
+		body:      body,
+	})
+}
+
+// events - 受信したイベントの名前を受信した順に返す
+func (r *webhookReceiver) events() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var events []string
+	for _, received := range r.received {
+		events = append(events, received.event)
+	}
+	return events
+}
+
+// pendingEvents - 送信待ちの配信の「イベント名 URLの末尾」を保存した順に返す
+func pendingEvents(t *testing.T, cfg *config.Config) []string {
+	t.Helper()
+
+	deliveries, err := webhooks.Pending(cfg)
+	if err != nil {
+		t.Fatalf("送信待ちの配信の読み込みに失敗しました: %v", err)
+	}
+	var events []string
+	for _, delivery := range deliveries {
+		events = append(events, delivery.Event+" "+filepath.Base(delivery.URL))
+	}
+	return events
+}
+
+// Webhookの送信を確認するバックログ（Epic1にIssue1・2）を作成して同期し、Webhookを設定する
+// 送信待ちに追加されるようプロジェクトを監視中にする（設定ファイルがないため、監視側では送信しない）
+func setupWebhookProject(t *testing.T, cfg *config.Config, hooks ...config.Webhook) {
+	t.Helper()
+
+	cfg.StateDir = t.TempDir()
+	createTestBacklog(t, cfg, 2)
+	if err := commands.SyncCommand(cfg); err != nil {
+		t.Fatalf("syncコマンドの実行に失敗しました: %v", err)
+	}
+	cfg.Webhooks = hooks
+
+	watcher.SetCommandExecutor(&MockCommandExecutor{})
+	manager := watcher.GetManager()
+	if err := manager.StartWatching(cfg.ProjectsDir, time.Hour); err != nil {
+		t.Fatalf("監視の開始に失敗しました: %v", err)
+	}
+	t.Cleanup(func() { manager.StopWatching(cfg.ProjectsDir) })
+}
+
+/**
+ * 設定ファイルのwebhooksで、URL・秘密鍵・送信するイベントを指定できること
+ */
+func TestWebhooksConfig(t *testing.T) {
+	rootDir := setupProjectRoot(t, "webhooks:\n  - url: https://example.com/hook\n    secret_env: TEST_WEBHOOK_SECRET\n    events: [issue.closed, epic.closed]\n  - url: http://localhost:8080/all\n")
+	cfg, err := config.Load(config.LoadOptions{ProjectDir: rootDir})
+	if err != nil {
+		t.Fatalf("設定の読み込みに失敗しました: %v", err)
+	}
+	if len(cfg.Webhooks) != 2 {
+		t.Fatalf("webhooksが読み込まれていません: %+v", cfg.Webhooks)
+	}
+	hook, all := cfg.Webhooks[0], cfg.Webhooks[1]
+	if !hook.Accepts(config.WebhookIssueClosed) || hook.Accepts(config.WebhookOrderChanged) || !all.Accepts(config.WebhookOrderChanged) {
+		t.Errorf("送信するイベントの判定が不正です: %+v", cfg.Webhooks)
+	}
+
+	// secret_envは送信時に環境変数から読み込む
+	if _, ok := hook.SigningSecret(); ok {
+		t.Errorf("環境変数が未設定なのに秘密鍵が返されています")
+	}
+	t.Setenv("TEST_WEBHOOK_SECRET", "秘密")
+	if secret, ok := hook.SigningSecret(); !ok || secret != "秘密" {
+		t.Errorf("環境変数から秘密鍵が読み込まれていません: %q", secret)
+	}
+
+	for _, content := range []string{
+		"webhooks:\n  - url: ftp://example.com/hook\n",
+		"webhooks:\n  - url: https://example.com/hook\n    events: [issue.deleted]\n",
+		"webhooks:\n  - url: https://example.com/hook\n    secret: a\n    secret_env: B\n",
+	} {
+		if _, err := config.Load(config.LoadOptions{ProjectDir: setupProjectRoot(t, content)}); err == nil {
+			t.Errorf("不正なwebhooksがエラーになりません:\n%s", content)
+		}
+	}
+}
+
+/**
+ * syncで発生したイベントが、送信する対象のWebhookごとに送信待ちとして保存されること
+ *
+ * Issue1を完了・Issue2のタイトルを変更・Issue3を追加すると、
+ * すべてのイベントを送信するWebhookにはissue.created・issue.updated・issue.closed・order.changedが、
+ * issue.closedだけを送信するWebhookにはissue.closedだけが保存されます。
+ */
+func TestSyncEnqueuesWebhooks(t *testing.T) {
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	setupWebhookProject(t, cfg,
+		config.Webhook{URL: "http://127.0.0.1:1/all"},
+		config.Webhook{URL: "http://127.0.0.1:1/closed", Events: []string{config.WebhookIssueClosed}},
+	)
+
+	rewriteIssue(t, filepath.Join(cfg.IssuesDir, "1_O_Issue1.md"), "status: Open", "status: Close")
+	rewriteIssue(t, filepath.Join(cfg.IssuesDir, "2_O_Issue2.md"), "title: Issue2", "title: 変更したIssue2")
+	createTestIssue(t, cfg, 3, "Issue3", "Open", 1, 1)
+	if err := commands.SyncCommand(cfg); err != nil {
+		t.Fatalf("syncコマンドの実行に失敗しました: %v", err)
+	}
+
+	expected := []string{"issue.created all", "issue.updated all", "issue.closed all", "issue.closed closed", "order.changed all"}
+	if got := pendingEvents(t, cfg); fmt.Sprint(got) != fmt.Sprint(expected) {
+		t.Errorf("送信待ちの配信が不正です:\n期待値: %v\n実際:   %v", expected, got)
+	}
+
+	deliveries, _ := webhooks.Pending(cfg)
+	var event webhooks.Event
+	if err := json.Unmarshal(deliveries[len(deliveries)-1].Payload, &event); err != nil {
+		t.Fatalf("イベントのJSONを解析できません: %v", err)
+	}
+	if fmt.Sprint(event.Order) != "[2 3]" || event.ID == "" || event.Project == "" {
+		t.Errorf("order.changedのイベントが不正です: %+v", event)
+	}
+
+	// 変更がないsyncでは何も追加されない
+	if err := commands.SyncCommand(cfg); err != nil {
+		t.Fatalf("syncコマンドの実行に失敗しました: %v", err)
+	}
+	if got := pendingEvents(t, cfg); len(got) != len(expected) {
+		t.Errorf("変更がないsyncで配信が追加されています: %v", got)
+	}
+
+	// 送信待ちはgitで管理するprojectsディレクトリではなく状態ディレクトリに保存される
+	if dir := cfg.WebhookQueueDir(); !strings.HasPrefix(dir, cfg.StateDir) {
+		t.Errorf("送信待ちのディレクトリが状態ディレクトリ配下にありません: %s", dir)
+	}
+}
+
+/**
+ * 再オープンしたIssueがsyncでorder.csvに追加されても、issue.createdが送信待ちに追加されないこと
+ */
+func TestSyncSkipsCreatedWebhookForReopenedIssue(t *testing.T) {
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	setupWebhookProject(t, cfg, config.Webhook{URL: "http://127.0.0.1:1/created", Events: []string{config.WebhookIssueCreated}})
+
+	rewriteIssue(t, filepath.Join(cfg.IssuesDir, "1_O_Issue1.md"), "status: Open", "status: Close")
+	if err := commands.SyncCommand(cfg); err != nil {
+		t.Fatalf("syncコマンドの実行に失敗しました: %v", err)
+	}
+	rewriteIssue(t, filepath.Join(cfg.IssuesDir, "1_C_Issue1.md"), "status: Close", "status: Open")
+	if err := commands.SyncCommand(cfg); err != nil {
+		t.Fatalf("syncコマンドの実行に失敗しました: %v", err)
+	}
+
+	if got := pendingEvents(t, cfg); len(got) != 0 {
+		t.Errorf("再オープンしたIssueのissue.createdが追加されています: %v", got)
+	}
+	if ids := readOrderIDs(t, cfg); fmt.Sprint(ids) != "[2 1]" {
+		t.Errorf("再オープンしたIssueがorder.csvに追加されていません: %v", ids)
+	}
+}
+
+/**
+ * プロジェクトを監視しているプロセスがなければ、syncしても送信待ちに追加されないこと
+ *
+ * 送信するプロセスがないまま送信待ちが増え続けないよう、監視中のプロジェクトのイベントだけを保存します。
+ */
+func TestSyncSkipsWebhooksWithoutWatcher(t *testing.T) {
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	cfg.StateDir = t.TempDir()
+	cfg.Webhooks = []config.Webhook{{URL: "http://127.0.0.1:1/all"}}
+
+	createTestEpic(t, cfg, 1, "Epic1", "Open")
+	createTestIssue(t, cfg, 1, "Issue1", "Open", 1, 1)
+	if err := commands.SyncCommand(cfg); err != nil {
+		t.Fatalf("syncコマンドの実行に失敗しました: %v", err)
+	}
+
+	if got := pendingEvents(t, cfg); len(got) != 0 {
+		t.Errorf("監視していないプロジェクトの配信が追加されています: %v", got)
+	}
+	if fileExists(cfg.WebhookQueueDir()) {
+		t.Errorf("送信待ちのディレクトリが作成されています: %s", cfg.WebhookQueueDir())
+	}
+}
+
+/**
+ * ファイル名の衝突でsyncがエラーになっても、反映済みの変更のイベントが送信待ちに追加されること
+ */
+func TestSyncEnqueuesWebhooksDespiteRenameCollision(t *testing.T) {
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	setupWebhookProject(t, cfg, config.Webhook{URL: "http://127.0.0.1:1/closed", Events: []string{config.WebhookIssueClosed}})
+
+	if err := os.WriteFile(filepath.Join(cfg.IssuesDir, "1_C_Issue1.md"), []byte("リネーム先と衝突するファイル\n"), 0644); err != nil {
+		t.Fatalf("衝突するファイルの作成に失敗しました: %v", err)
+	}
+	rewriteIssue(t, filepath.Join(cfg.IssuesDir, "1_O_Issue1.md"), "status: Open", "status: Close")
+	if err := commands.SyncCommand(cfg); err == nil {
+		t.Fatalf("ファイル名の衝突がエラーになりません")
+	}
+
+	if got := pendingEvents(t, cfg); fmt.Sprint(got) != "[issue.closed closed]" {
+		t.Errorf("ファイル名の衝突で配信が追加されていません: %v", got)
+	}
+}
+
+/**
+ * 送信待ちの配信が署名付きで送信され、送信した配信は送信待ちから削除されること
+ */
+func TestDispatcherSendsSignedWebhooks(t *testing.T) {
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	receiver := &webhookReceiver{}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+	setupWebhookProject(t, cfg, config.Webhook{URL: server.URL, Secret: "秘密鍵"})
+
+	rewriteIssue(t, filepath.Join(cfg.IssuesDir, "1_O_Issue1.md"), "status: Open", "status: Close")
+	if err := commands.SyncCommand(cfg); err != nil {
+		t.Fatalf("syncコマンドの実行に失敗しました: %v", err)
+	}
+
+	dispatcher := webhooks.NewDispatcher(cfg, webhooks.Options{})
+	if next := dispatcher.Flush(context.Background()); !next.IsZero() {
+		t.Errorf("再送待ちの配信が残っています: %s", next)
+	}
+
+	if got := receiver.events(); fmt.Sprint(got) != "[issue.closed order.changed]" {
+		t.Fatalf("受信したイベントが不正です: %v", got)
+	}
+	received := receiver.received[0]
+	if received.signature != webhooks.Sign("秘密鍵", received.body) || !strings.HasPrefix(received.signature, "sha256=") {
+		t.Errorf("署名が不正です: %s", received.signature)
+	}
+	var event webhooks.Event
+	if err := json.Unmarshal(received.body, &event); err != nil {
+		t.Fatalf("受信したボディを解析できません: %v", err)
+	}
+	if event.Name != config.WebhookIssueClosed || event.Issue == nil || event.Issue.ID != 1 || event.Issue.Status != "Close" {
+		t.Errorf("受信したイベントの内容が不正です: %s", received.body)
+	}
+	if got := pendingEvents(t, cfg); len(got) != 0 {
+		t.Errorf("送信した配信が送信待ちに残っています: %v", got)
+	}
+}
+
+/**
+ * 送信に失敗した配信が、再起動後も保存した順のまま同じ配信IDで再送されること
+ *
+ * 最初の2回のリクエストには500を返すエンドポイントに送信します。
+ * 失敗した配信より後の配信は、順序を保つため失敗した配信が送信されるまで待たされます。
+ */
+func TestDispatcherRetriesPersistedDeliveries(t *testing.T) {
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	logs := captureLogs(t, logger.Options{})
+	receiver := &webhookReceiver{failures: 2}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+	setupWebhookProject(t, cfg, config.Webhook{URL: server.URL, Events: []string{config.WebhookIssueClosed}})
+
+	rewriteIssue(t, filepath.Join(cfg.IssuesDir, "1_O_Issue1.md"), "status: Open", "status: Close")
+	rewriteIssue(t, filepath.Join(cfg.IssuesDir, "2_O_Issue2.md"), "status: Open", "status: Close")
+	if err := commands.SyncCommand(cfg); err != nil {
+		t.Fatalf("syncコマンドの実行に失敗しました: %v", err)
+	}
+	before, _ := webhooks.Pending(cfg)
+
+	opts := webhooks.Options{RetryBase: 50 * time.Millisecond}
+	if next := webhooks.NewDispatcher(cfg, opts).Flush(context.Background()); next.IsZero() {
+		t.Fatalf("失敗した配信の再送時刻が返されていません")
+	}
+	pending, _ := webhooks.Pending(cfg)
+	if len(pending) != 2 || pending[0].Attempts != 1 || pending[0].LastError == "" || pending[1].Attempts != 0 {
+		t.Fatalf("失敗した配信の状態が保存されていません: %+v", pending)
+	}
+	if !strings.Contains(logs.String(), "Webhookの送信に失敗したため再送します") {
+		t.Errorf("再送の警告が出力されていません:\n%s", logs.String())
+	}
+
+	// 再起動したプロセスのDispatcherが送信待ちの配信を送信する
+	dispatcher := webhooks.NewDispatcher(cfg, opts)
+	dispatcher.Start()
+	defer dispatcher.Stop()
+	deadline := time.Now().Add(5 * time.Second)
+	for len(receiver.events()) < 2 && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	receiver.mu.Lock()
+	defer receiver.mu.Unlock()
+	if len(receiver.received) != 2 || receiver.requests != 4 {
+		t.Fatalf("再送されていません: 受信=%d, リクエスト=%d", len(receiver.received), receiver.requests)
+	}
+	for i, received := range receiver.received {
+		if received.delivery != before[i].ID {
+			t.Errorf("配信の順序またはIDが変わっています: 期待値=%s, 実際=%s", before[i].ID, received.delivery)
+		}
+	}
+}
+
+/**
+ * 試行回数の上限まで失敗した配信は送信を諦め、送信待ちのディレクトリのfailedに移動されること
+ */
+func TestDispatcherGivesUpAfterMaxAttempts(t *testing.T) {
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	logs := captureLogs(t, logger.Options{})
+	receiver := &webhookReceiver{failures: 100}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+	setupWebhookProject(t, cfg, config.Webhook{URL: server.URL, Events: []string{config.WebhookIssueClosed}})
+
+	rewriteIssue(t, filepath.Join(cfg.IssuesDir, "1_O_Issue1.md"), "status: Open", "status: Close")
+	if err := commands.SyncCommand(cfg); err != nil {
+		t.Fatalf("syncコマンドの実行に失敗しました: %v", err)
+	}
+
+	dispatcher := webhooks.NewDispatcher(cfg, webhooks.Options{RetryBase: time.Millisecond, MaxAttempts: 2})
+	dispatcher.Flush(context.Background())
+	time.Sleep(10 * time.Millisecond)
+	if next := dispatcher.Flush(context.Background()); !next.IsZero() {
+		t.Errorf("送信を諦めた配信が再送待ちになっています: %s", next)
+	}
+
+	if got := pendingEvents(t, cfg); len(got) != 0 {
+		t.Errorf("送信を諦めた配信が送信待ちに残っています: %v", got)
+	}
+	failed, _ := filepath.Glob(filepath.Join(cfg.WebhookQueueDir(), "failed", "*.json"))
+	if len(failed) != 1 {
+		t.Errorf("送信を諦めた配信がfailedに移動されていません: %v", failed)
+	}
+	if !strings.Contains(logs.String(), "Webhookの送信を諦めました") {
+		t.Errorf("送信を諦めたエラーが出力されていません:\n%s", logs.String())
+	}
+}
+
+// 設定ファイルの内容でバックログ（Epic1にIssue1）を作成して同期し、実際のsyncを実行する監視を開始する
+func startWebhookWatcher(t *testing.T, content string) (*config.Config, string) {
+	t.Helper()
+
+	rootDir := setupProjectRoot(t, content)
+	cfg, err := config.Load(config.LoadOptions{ProjectDir: rootDir})
+	if err != nil {
+		t.Fatalf("設定の読み込みに失敗しました: %v", err)
+	}
+	for _, dir := range []string{cfg.IssuesDir, cfg.EpicDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("ディレクトリの作成に失敗しました: %v", err)
+		}
+	}
+	createTestEpic(t, cfg, 1, "Epic1", "Open")
+	createTestIssue(t, cfg, 1, "Issue1", "Open", 1, 1)
+	if err := commands.SyncCommand(cfg); err != nil {
+		t.Fatalf("syncコマンドの実行に失敗しました: %v", err)
+	}
+
+	watcher.SetCommandExecutor(&commands.CommandExecutorImpl{})
+	t.Cleanup(func() { watcher.SetCommandExecutor(&MockCommandExecutor{}) })
+	manager := watcher.GetManager()
+	if err := manager.StartWatching(cfg.ProjectsDir, 100*time.Millisecond); err != nil {
+		t.Fatalf("監視の開始に失敗しました: %v", err)
+	}
+	t.Cleanup(func() { manager.StopWatching(cfg.ProjectsDir) })
+	time.Sleep(200 * time.Millisecond) // 初期化のための待機
+	return cfg, rootDir
+}
+
+// waitForWebhooks - エンドポイントがn件のイベントを受信するまで待つ
+func waitForWebhooks(receiver *webhookReceiver, n int) {
+	deadline := time.Now().Add(3 * time.Second)
+	for len(receiver.events()) < n && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+/**
+ * 監視中にIssueを完了にすると、設定ファイルのWebhookにイベントが送信されること
+ */
+func TestWatcherSendsWebhooks(t *testing.T) {
+	receiver := &webhookReceiver{}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	cfg, _ := startWebhookWatcher(t, fmt.Sprintf("state_dir: %s\nwebhooks:\n  - url: %s\n    events: [issue.closed, epic.closed]\n", t.TempDir(), server.URL))
+
+	rewriteIssue(t, filepath.Join(cfg.IssuesDir, "1_O_Issue1.md"), "status: Open", "status: Close")
+	waitForWebhooks(receiver, 2)
+
+	if got := receiver.events(); fmt.Sprint(got) != "[issue.closed epic.closed]" {
+		t.Errorf("監視中のsyncのイベントが送信されていません: %v", got)
+	}
+}
+
+/**
+ * Webhookを設定せずに監視を開始した後で設定ファイルにWebhookを追加しても、監視を再起動せずに送信されること
+ */
+func TestWatcherReloadsWebhooks(t *testing.T) {
+	receiver := &webhookReceiver{}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	stateDir := t.TempDir()
+	cfg, rootDir := startWebhookWatcher(t, fmt.Sprintf("state_dir: %s\n", stateDir))
+
+	content := fmt.Sprintf("state_dir: %s\nwebhooks:\n  - url: %s\n    events: [issue.closed]\n", stateDir, server.URL)
+	if err := os.WriteFile(filepath.Join(rootDir, config.FileName), []byte(content), 0644); err != nil {
+		t.Fatalf("設定ファイルの更新に失敗しました: %v", err)
+	}
+	rewriteIssue(t, filepath.Join(cfg.IssuesDir, "1_O_Issue1.md"), "status: Open", "status: Close")
+	waitForWebhooks(receiver, 1)
+
+	if got := receiver.events(); fmt.Sprint(got) != "[issue.closed]" {
+		t.Errorf("監視中に追加したWebhookにイベントが送信されていません: %v", got)
+	}
+}
@@ -0,0 +1,226 @@
+// test/workflow_status_test.go
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/moai/instant-backlog/internal/commands"
+	"github.com/moai/instant-backlog/internal/config"
+	"github.com/moai/instant-backlog/internal/fileops"
+	"github.com/moai/instant-backlog/internal/models"
+	"github.com/moai/instant-backlog/internal/parser"
+	"github.com/moai/instant-backlog/pkg/utils"
+)
+
+// テスト用のワークフロー設定
+const workflowConfigYAML = `statuses:
+  - name: Open
+    prefix: O
+  - name: In Progress
+    prefix: P
+  - name: Review
+    prefix: R
+  - name: Blocked
+    prefix: B
+  - name: Done
+    prefix: D
+    done: true
+`
+
+// ワークフロー設定ファイルを書き込み、それを反映した設定を返す
+func setupWorkflowConfig(t *testing.T, cfg *config.Config) *config.Config {
+	t.Helper()
+
+	configPath := filepath.Join(filepath.Dir(cfg.ProjectsDir), config.FileName)
+	if err := os.WriteFile(configPath, []byte(workflowConfigYAML), 0644); err != nil {
+		t.Fatalf("設定ファイルの作成に失敗しました: %v", err)
+	}
+
+	loaded, err := config.ForProject(cfg.ProjectsDir)
+	if err != nil {
+		t.Fatalf("設定ファイルの読み込みに失敗しました: %v", err)
+	}
+	return loaded
+}
+
+// ワークフロー用のIssueを作成
+func createWorkflowIssue(t *testing.T, cfg *config.Config, id int, title, status string, epicID int) {
+	t.Helper()
+
+	issue := &models.Issue{ID: id, Title: title, Status: status, Epic: epicID, Estimate: 1, Content: "ワークフローテスト"}
+	if err := fileops.WriteIssueWithStatuses(cfg.IssuesDir, cfg.StatusSet(), issue); err != nil {
+		t.Fatalf("テスト用Issueの作成に失敗しました: %v", err)
+	}
+}
+
+/**
+ * 設定ファイルで独自のステータスを定義できること
+ *
+ * In Progress や Review などのステータスを追加した場合でも、
+ * 完了扱いのステータスのみがorder.csvから除外され、
+ * ファイル名には設定した頭文字が使用されることを確認します。
+ */
+func TestConfigurableWorkflowStatuses(t *testing.T) {
+	// テスト環境をセットアップ
+	baseCfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	cfg := setupWorkflowConfig(t, baseCfg)
+
+	// 各ステータスのIssueを作成
+	epic := &models.Epic{ID: 1, Title: "ワークフロー", Status: "Open"}
+	if err := fileops.WriteEpicWithStatuses(cfg.EpicDir, cfg.StatusSet(), epic); err != nil {
+		t.Fatalf("テスト用Epicの作成に失敗しました: %v", err)
+	}
+	createWorkflowIssue(t, cfg, 1, "着手前", "Open", 1)
+	createWorkflowIssue(t, cfg, 2, "作業中", "In Progress", 1)
+	createWorkflowIssue(t, cfg, 3, "レビュー中", "Review", 1)
+	createWorkflowIssue(t, cfg, 4, "完了", "Done", 1)
+
+	// 頭文字がファイル名に反映されていることを確認
+	for _, name := range []string{"1_O_着手前.md", "2_P_作業中.md", "3_R_レビュー中.md", "4_D_完了.md"} {
+		if !fileExists(filepath.Join(cfg.IssuesDir, name)) {
+			t.Errorf("ステータスの頭文字がファイル名に反映されていません: %s", name)
+		}
+	}
+
+	// syncコマンドを実行
+	if err := commands.SyncCommand(cfg); err != nil {
+		t.Fatalf("syncコマンドの実行に失敗しました: %v", err)
+	}
+
+	// 完了扱いのIssueのみがorder.csvから除外されていることを確認
+	orderItems, err := parser.ReadOrderCSV(cfg.OrderCSV)
+	if err != nil {
+		t.Fatalf("order.csvの読み込みに失敗しました: %v", err)
+	}
+	ids := make(map[int]bool)
+	for _, item := range orderItems {
+		ids[item.ID] = true
+	}
+	for _, id := range []int{1, 2, 3} {
+		if !ids[id] {
+			t.Errorf("未完了のIssue(ID=%d)がorder.csvに含まれていません", id)
+		}
+	}
+	if ids[4] {
+		t.Errorf("完了扱いのIssue(ID=4)がorder.csvに含まれています")
+	}
+
+	// 未完了のIssueがあるためEpicはOpenのまま
+	if !fileExists(filepath.Join(cfg.EpicDir, "1_O_ワークフロー.md")) {
+		t.Errorf("未完了のIssueがあるのにEpicのファイル名が変更されています")
+	}
+}
+
+/**
+ * すべてのIssueが完了扱いになった場合、Epicも設定された完了ステータスになること
+ */
+func TestWorkflowEpicAutoCloseUsesDoneStatus(t *testing.T) {
+	// テスト環境をセットアップ
+	baseCfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	cfg := setupWorkflowConfig(t, baseCfg)
+
+	epic := &models.Epic{ID: 1, Title: "完了するエピック", Status: "In Progress"}
+	if err := fileops.WriteEpicWithStatuses(cfg.EpicDir, cfg.StatusSet(), epic); err != nil {
+		t.Fatalf("テスト用Epicの作成に失敗しました: %v", err)
+	}
+	createWorkflowIssue(t, cfg, 1, "完了タスク1", "Done", 1)
+	createWorkflowIssue(t, cfg, 2, "完了タスク2", "Done", 1)
+
+	// syncコマンドを実行
+	if err := commands.SyncCommand(cfg); err != nil {
+		t.Fatalf("syncコマンドの実行に失敗しました: %v", err)
+	}
+
+	// Epicが完了ステータスになり、ファイル名も更新されていることを確認
+	epicPath := filepath.Join(cfg.EpicDir, "1_D_完了するエピック.md")
+	updated, err := parser.ParseEpicFile(epicPath)
+	if err != nil {
+		t.Fatalf("更新後Epicファイルの読み込みに失敗しました: %v", err)
+	}
+	if updated.Status != "Done" {
+		t.Errorf("Epicのステータスが不正です。期待値: Done, 実際: %s", updated.Status)
+	}
+	if fileExists(filepath.Join(cfg.EpicDir, "1_P_完了するエピック.md")) {
+		t.Errorf("古いEpicファイルが残っています")
+	}
+}
+
+/**
+ * ファイル名の解析とバリデーションが設定されたステータスに従うこと
+ */
+func TestWorkflowFilenameAndValidation(t *testing.T) {
+	baseCfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	cfg := setupWorkflowConfig(t, baseCfg)
+	statuses := cfg.StatusSet()
+
+	id, status, title, err := utils.ParseFilenameWithStatuses(statuses, "7_R_レビュー_待ち.md")
+	if err != nil {
+		t.Fatalf("ファイル名の解析に失敗しました: %v", err)
+	}
+	if id != 7 || status != "Review" || title != "レビュー 待ち" {
+		t.Errorf("ファイル名の解析結果が不正です: id=%d, status=%s, title=%s", id, status, title)
+	}
+
+	if _, _, _, err := utils.ParseFilenameWithStatuses(statuses, "7_X_不明.md"); err == nil {
+		t.Errorf("未定義の頭文字がエラーになりません")
+	}
+
+	// ステータス定義を渡さない場合はC以外の頭文字を従来どおりOpenとして扱う
+	if _, status, _, err := utils.ParseFilename("7_X_不明.md"); err != nil || status != "Open" {
+		t.Errorf("未知の頭文字がOpenとして解析されません: status=%s, err=%v", status, err)
+	}
+	if _, status, _, err := utils.ParseFilename("7_C_完了.md"); err != nil || status != "Close" {
+		t.Errorf("頭文字Cが解析されません: status=%s, err=%v", status, err)
+	}
+
+	issue := &models.Issue{ID: 1, Title: "検証", Status: "Blocked", Epic: 1}
+	if err := utils.ValidateIssueWithStatuses(statuses, issue); err != nil {
+		t.Errorf("定義済みのステータスがエラーになりました: %v", err)
+	}
+	issue.Status = "Close"
+	if err := utils.ValidateIssueWithStatuses(statuses, issue); err == nil {
+		t.Errorf("未定義のステータスがエラーになりません")
+	}
+
+	// ステータス定義を渡さない場合はデフォルトのOpen/Closeとして扱う
+	if err := utils.ValidateIssue(issue); err != nil {
+		t.Errorf("デフォルトのステータスがエラーになりました: %v", err)
+	}
+	if err := utils.ValidateEpic(&models.Epic{ID: 1, Title: "検証", Status: "Blocked"}); err == nil {
+		t.Errorf("デフォルトにないステータスがエラーになりません")
+	}
+
+	// 大文字小文字のみが異なるステータスはエラーにし、正しいステータス名を提案する
+	issue.Status = "open"
+	err = utils.ValidateIssue(issue)
+	if err == nil {
+		t.Fatalf("大文字小文字が異なるステータスがエラーになりません")
+	}
+	if !strings.Contains(err.Error(), "'open' ではなく 'Open'") {
+		t.Errorf("正しいステータス名が提案されていません: %v", err)
+	}
+}
+
+/**
+ * 不正なステータス定義を含む設定ファイルはエラーになること
+ */
+func TestInvalidWorkflowConfig(t *testing.T) {
+	cfg, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	invalid := "statuses:\n  - name: Open\n    prefix: O\n  - name: Doing\n    prefix: O\n"
+	configPath := filepath.Join(filepath.Dir(cfg.ProjectsDir), config.FileName)
+	if err := os.WriteFile(configPath, []byte(invalid), 0644); err != nil {
+		t.Fatalf("設定ファイルの作成に失敗しました: %v", err)
+	}
+
+	if _, err := config.ForProject(cfg.ProjectsDir); err == nil {
+		t.Errorf("頭文字が重複した設定がエラーになりません")
+	}
+}